@@ -0,0 +1,73 @@
+package brandkit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+
+	"github.com/grokify/brandkit/svg/convert"
+	"github.com/grokify/brandkit/svg/verify"
+)
+
+// IconAudit describes the complexity and purity of an embedded icon.
+type IconAudit struct {
+	Brand        string
+	Variant      IconVariant
+	UsesGradient bool
+	UsesMask     bool
+	UsesClipPath bool
+	UsesExternal bool     // References an external http(s) resource
+	ColorCount   int      // Number of distinct fill/stroke/stop colors
+	IsPureVector bool     // No embedded binary data
+	Errors       []string // Verification errors, if any
+}
+
+var (
+	gradientElementRe = regexp.MustCompile(`<(?:linearGradient|radialGradient)\b`)
+	maskElementRe     = regexp.MustCompile(`<mask\b`)
+	clipPathElementRe = regexp.MustCompile(`<clipPath\b`)
+	externalRefRe     = regexp.MustCompile(`(?i)(?:href\s*=\s*["']|url\(\s*["']?)https?://`)
+)
+
+// AuditIcons audits every available variant of every embedded icon, sorted
+// by brand and then variant in AllVariants order. Icons that use gradients, masks,
+// or many colors are candidates for simplification, and any that are not
+// pure vector slipped through processing.
+func AuditIcons() ([]IconAudit, error) {
+	brands, err := ListIcons()
+	if err != nil {
+		return nil, err
+	}
+
+	var audits []IconAudit
+	for _, brand := range brands {
+		for _, variant := range AllVariants() {
+			content, err := GetIcon(brand, variant)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s %s icon: %w", brand, variant, err)
+			}
+			audits = append(audits, auditIcon(brand, variant, content))
+		}
+	}
+	return audits, nil
+}
+
+// auditIcon audits a single icon's content.
+func auditIcon(brand string, variant IconVariant, content []byte) IconAudit {
+	verifyResult := verify.Content(content, verify.Options{})
+	return IconAudit{
+		Brand:        brand,
+		Variant:      variant,
+		UsesGradient: gradientElementRe.Match(content),
+		UsesMask:     maskElementRe.Match(content),
+		UsesClipPath: clipPathElementRe.Match(content),
+		UsesExternal: externalRefRe.Match(content),
+		ColorCount:   len(convert.ExtractColors(string(content))),
+		IsPureVector: verifyResult.IsPureVector,
+		Errors:       verifyResult.Errors,
+	}
+}
@@ -0,0 +1,47 @@
+package brandkit
+
+import (
+	"testing"
+)
+
+func TestAuditIcons(t *testing.T) {
+	audits, err := AuditIcons()
+	if err != nil {
+		t.Fatalf("AuditIcons() error: %v", err)
+	}
+
+	brands, err := ListIcons()
+	if err != nil {
+		t.Fatal(err)
+	}
+	audited := map[string]bool{}
+	for _, a := range audits {
+		audited[a.Brand] = true
+	}
+	for _, brand := range brands {
+		if !audited[brand] {
+			t.Errorf("no audit entries for %s", brand)
+		}
+	}
+
+	for _, a := range audits {
+		if !a.IsPureVector {
+			t.Errorf("%s %s is not pure vector: %v", a.Brand, a.Variant, a.Errors)
+		}
+		if a.UsesExternal {
+			t.Errorf("%s %s references external resources", a.Brand, a.Variant)
+		}
+	}
+}
+
+func TestAuditIconFlags(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 10 10"><defs><linearGradient id="g"><stop stop-color="#fff"/></linearGradient><mask id="m"><rect fill="#000"/></mask></defs><path fill="url(#g)" mask="url(#m)"/></svg>`)
+
+	a := auditIcon("test", IconVariantColor, content)
+	if !a.UsesGradient || !a.UsesMask || a.UsesClipPath {
+		t.Errorf("flags = %+v", a)
+	}
+	if a.ColorCount != 2 || !a.IsPureVector {
+		t.Errorf("ColorCount = %d, IsPureVector = %v", a.ColorCount, a.IsPureVector)
+	}
+}
@@ -0,0 +1,51 @@
+package brandkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// checksumCommentRe matches an embedded brandkit checksum comment.
+var checksumCommentRe = regexp.MustCompile(`\s*<!--\s*brandkit:sha256=([0-9a-f]{64})\s*-->\s*`)
+
+// Checksum computes the SHA-256 checksum of SVG content in canonical form.
+// Canonicalization removes any existing checksum comment, normalizes line
+// endings to LF, and trims surrounding whitespace, so the checksum is
+// stable across rewrites of the comment itself.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(canonicalChecksumContent(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddChecksum appends a trailing <!-- brandkit:sha256=... --> comment to
+// SVG content, replacing any existing checksum comment.
+func AddChecksum(content []byte) []byte {
+	canonical := canonicalChecksumContent(content)
+	sum := sha256.Sum256(canonical)
+	out := make([]byte, 0, len(canonical)+90)
+	out = append(out, canonical...)
+	out = append(out, fmt.Sprintf("\n<!-- brandkit:sha256=%s -->\n", hex.EncodeToString(sum[:]))...)
+	return out
+}
+
+// VerifyChecksum reports whether the embedded checksum comment matches the
+// content. It returns an error if the content has no checksum comment.
+func VerifyChecksum(content []byte) (bool, error) {
+	matches := checksumCommentRe.FindAllSubmatch(content, -1)
+	if len(matches) == 0 {
+		return false, fmt.Errorf("no brandkit checksum comment found")
+	}
+	if len(matches) > 1 {
+		return false, fmt.Errorf("multiple brandkit checksum comments found")
+	}
+	return string(matches[0][1]) == Checksum(content), nil
+}
+
+func canonicalChecksumContent(content []byte) []byte {
+	content = checksumCommentRe.ReplaceAll(content, []byte("\n"))
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	return bytes.TrimSpace(content)
+}
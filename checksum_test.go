@@ -0,0 +1,81 @@
+package brandkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChecksumRoundTrip(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`)
+
+	signed := AddChecksum(content)
+	if !strings.Contains(string(signed), "<!-- brandkit:sha256=") {
+		t.Fatalf("checksum comment not added: %s", signed)
+	}
+
+	ok, err := VerifyChecksum(signed)
+	if err != nil {
+		t.Fatalf("VerifyChecksum error: %v", err)
+	}
+	if !ok {
+		t.Error("VerifyChecksum = false, want true")
+	}
+
+	// Re-adding replaces the existing comment rather than appending another
+	resigned := AddChecksum(signed)
+	if string(resigned) != string(signed) {
+		t.Errorf("AddChecksum not idempotent:\n%s\n%s", signed, resigned)
+	}
+}
+
+func TestChecksumModified(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`)
+	signed := AddChecksum(content)
+
+	modified := []byte(strings.Replace(string(signed), "90 90", "80 80", 1))
+	ok, err := VerifyChecksum(modified)
+	if err != nil {
+		t.Fatalf("VerifyChecksum error: %v", err)
+	}
+	if ok {
+		t.Error("VerifyChecksum = true for modified content, want false")
+	}
+}
+
+func TestVerifyChecksumMissing(t *testing.T) {
+	if _, err := VerifyChecksum([]byte(`<svg viewBox="0 0 10 10"/>`)); err == nil {
+		t.Error("expected error for content without checksum")
+	}
+}
+
+func TestProcessEmbedChecksum(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="#000000"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessColorWithOptions(input, output, ProcessOptions{EmbedChecksum: true})
+	if err != nil {
+		t.Fatalf("ProcessColorWithOptions error: %v", err)
+	}
+
+	written, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifyChecksum(written)
+	if err != nil || !ok {
+		t.Errorf("VerifyChecksum = %v, %v; want true, nil", ok, err)
+	}
+	if result.Checksum != Checksum(written) {
+		t.Errorf("Checksum = %q, want %q", result.Checksum, Checksum(written))
+	}
+}
@@ -2,10 +2,12 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,6 +16,7 @@ import (
 	"github.com/grokify/brandkit/svg"
 	"github.com/grokify/brandkit/svg/analyze"
 	"github.com/grokify/brandkit/svg/convert"
+	"github.com/grokify/brandkit/svg/minify"
 	"github.com/grokify/brandkit/svg/security"
 	"github.com/grokify/brandkit/svg/verify"
 )
@@ -34,7 +37,10 @@ var rootCmd = &cobra.Command{
 }
 
 // analyze command
-var analyzeShowFix bool
+var (
+	analyzeShowFix bool
+	analyzeFormat  string
+)
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze [path]",
@@ -43,7 +49,9 @@ var analyzeCmd = &cobra.Command{
 - ViewBox dimensions
 - Content centering
 - Padding percentages
-- Suggested viewBox fixes`,
+- Suggested viewBox fixes
+
+Use --format json to print the results as a JSON array for CI or dashboards.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAnalyze,
 }
@@ -74,11 +82,40 @@ func runAnalyze(_ *cobra.Command, args []string) error {
 	}
 
 	hasAnyIssues := false
+	for _, r := range results {
+		if r.HasIssues {
+			hasAnyIssues = true
+		}
+	}
+
+	switch analyzeFormat {
+	case "text":
+		printAnalyzeResults(results)
+	case "json":
+		if results == nil {
+			results = []*analyze.Result{}
+		}
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(out))
+	default:
+		return fmt.Errorf("unknown format %q (expected text or json)", analyzeFormat)
+	}
+
+	if hasAnyIssues {
+		return fmt.Errorf("one or more files have issues")
+	}
+	return nil
+}
+
+// printAnalyzeResults prints the human-readable analyze summary.
+func printAnalyzeResults(results []*analyze.Result) {
 	for _, r := range results {
 		status := "✓"
 		if r.HasIssues {
 			status = "✗"
-			hasAnyIssues = true
 		}
 
 		fmt.Printf("%s %s\n", status, filepath.Base(r.FilePath))
@@ -97,11 +134,6 @@ func runAnalyze(_ *cobra.Command, args []string) error {
 		}
 		fmt.Println()
 	}
-
-	if hasAnyIssues {
-		return fmt.Errorf("one or more files have issues")
-	}
-	return nil
 }
 
 // verify command
@@ -117,6 +149,11 @@ var verifyCmd = &cobra.Command{
 }
 
 // verify-all command (recursive verification for CI)
+var (
+	verifyAllThreads int
+	verifyAllFormat  string
+)
+
 var verifyAllCmd = &cobra.Command{
 	Use:   "verify-all [path]",
 	Short: "Recursively verify all SVG files are pure vector",
@@ -125,9 +162,13 @@ var verifyAllCmd = &cobra.Command{
 This command is designed for CI pipelines to ensure all brand icons
 remain pure vector without embedded binary data.
 
+Use --format json for the results as a JSON array, or --format sarif for a
+SARIF 2.1.0 log that GitHub code scanning can ingest.
+
 Examples:
   brandkit verify-all brands/
-  brandkit verify-all .`,
+  brandkit verify-all .
+  brandkit verify-all brands/ --format sarif > verify.sarif`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runVerifyAll,
 }
@@ -138,7 +179,10 @@ func runVerifyAll(_ *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
-	results, err := verify.DirectoryRecursive(path)
+	results, err := verify.DirectoryWithOptions(path, verify.DirectoryOptions{
+		Recursive: true,
+		Workers:   verifyAllThreads,
+	})
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
@@ -146,19 +190,44 @@ func runVerifyAll(_ *cobra.Command, args []string) error {
 	allValid := true
 	validCount := 0
 	for _, r := range results {
-		if !r.IsSuccess() {
+		if r.IsSuccess() {
+			validCount++
+		} else {
 			allValid = false
+		}
+	}
+
+	switch verifyAllFormat {
+	case "text":
+		for _, r := range results {
+			if r.IsSuccess() {
+				continue
+			}
 			fmt.Printf("✗ %s\n", r.FilePath)
 			for _, e := range r.Errors {
 				fmt.Printf("  Error: %s\n", e)
 			}
-		} else {
-			validCount++
 		}
+		fmt.Printf("\n✓ Verified %d/%d SVG files as pure vector\n", validCount, len(results))
+	case "json":
+		if results == nil {
+			results = []*verify.Result{}
+		}
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(out))
+	case "sarif":
+		out, err := verify.SARIF(results)
+		if err != nil {
+			return fmt.Errorf("failed to encode SARIF: %w", err)
+		}
+		fmt.Println(string(out))
+	default:
+		return fmt.Errorf("unknown format %q (expected text, json, or sarif)", verifyAllFormat)
 	}
 
-	fmt.Printf("\n✓ Verified %d/%d SVG files as pure vector\n", validCount, len(results))
-
 	if !allValid {
 		return fmt.Errorf("one or more files failed verification")
 	}
@@ -221,7 +290,18 @@ var (
 	convertColor            string
 	convertIncludeStroke    bool
 	convertPreserveMasks    bool
+	convertPreserveDefs     bool
 	convertRemoveBackground bool
+	convertRecursive        bool
+	convertPalette          string
+	convertPaletteFile      string
+	convertWarnInvisibleOn  string
+	convertPatch            bool
+	convertHashName         bool
+	convertFlattenVars      bool
+	convertFlattenGradients bool
+	convertMatchTolerance   int
+	convertPrecision        int
 )
 
 var convertCmd = &cobra.Command{
@@ -233,7 +313,13 @@ Examples:
   brandkit convert icon_orig.svg -o icon_white.svg --color ffffff
   brandkit convert icon.svg -o output.svg --color black
   brandkit convert icon.svg -o output.svg --remove-background  # Remove background rect/circle
-  brandkit convert icon.svg -o output.svg  # Just copy without color change`,
+  brandkit convert icon.svg -o output.svg  # Just copy without color change
+  brandkit convert icons/ -o out/ --color fff --recursive  # Convert a directory tree
+  brandkit convert icon.svg -o dark.svg --palette dark --palette-file palettes.json
+  brandkit convert icon.svg --color fff --patch > recolor.patch  # Print a diff instead of writing
+
+A palette file is a JSON object mapping palette names to color maps:
+  {"dark": {"#1a73e8": "#8ab4f8", "#202124": "#e8eaed"}}`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConvert,
 }
@@ -241,7 +327,7 @@ Examples:
 func runConvert(_ *cobra.Command, args []string) error {
 	inputPath := args[0]
 
-	if convertOutput == "" {
+	if convertOutput == "" && !convertPatch {
 		return fmt.Errorf("output path is required (-o, --output)")
 	}
 
@@ -249,7 +335,56 @@ func runConvert(_ *cobra.Command, args []string) error {
 		Color:            convertColor,
 		IncludeStroke:    convertIncludeStroke,
 		PreserveMasks:    convertPreserveMasks,
+		PreserveDefs:     convertPreserveDefs,
 		RemoveBackground: convertRemoveBackground,
+		WarnInvisibleOn:  convertWarnInvisibleOn,
+		HashName:         convertHashName,
+		FlattenVars:      convertFlattenVars,
+		FlattenGradients: convertFlattenGradients,
+		MatchTolerance:   convertMatchTolerance,
+		Precision:        convertPrecision,
+	}
+
+	if convertPaletteFile != "" {
+		if err := loadPaletteFile(convertPaletteFile); err != nil {
+			return err
+		}
+	}
+	if convertPalette != "" {
+		if convertColor != "" {
+			return fmt.Errorf("--color and --palette cannot be used together")
+		}
+		colorMap, err := convert.LookupPalette(convertPalette)
+		if err != nil {
+			return err
+		}
+		opts.ColorMap = colorMap
+	}
+
+	info, err := svg.GetPathInfo(inputPath)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if info.IsDir {
+		if !convertRecursive {
+			return fmt.Errorf("input is a directory; use --recursive to convert a directory tree")
+		}
+		if convertPatch {
+			return fmt.Errorf("--patch is not supported for directories")
+		}
+		return runConvertDirectory(inputPath, opts)
+	}
+
+	if convertPatch {
+		patch, result, err := convert.Patch(inputPath, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Print(patch)
+		for _, w := range result.Warnings {
+			fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+		}
+		return nil
 	}
 
 	result, err := convert.SVG(inputPath, convertOutput, opts)
@@ -261,13 +396,64 @@ func runConvert(_ *cobra.Command, args []string) error {
 		if result.BackgroundRemoved {
 			fmt.Printf("✓ Removed background element\n")
 		}
-		if result.TargetColor != "" {
-			fmt.Printf("✓ Converted %s → %s (color: %s)\n", filepath.Base(inputPath), filepath.Base(convertOutput), result.TargetColor)
+		if (result.TargetColor != "" || convertPalette != "") && !result.ChangesMade {
+			fmt.Printf("✓ Copied %s → %s unchanged\n", filepath.Base(inputPath), filepath.Base(result.OutputPath))
+		} else if result.TargetColor != "" {
+			fmt.Printf("✓ Converted %s → %s (color: %s)\n", filepath.Base(inputPath), filepath.Base(result.OutputPath), result.TargetColor)
+		} else if convertPalette != "" {
+			fmt.Printf("✓ Converted %s → %s (palette: %s)\n", filepath.Base(inputPath), filepath.Base(result.OutputPath), convertPalette)
 		} else {
-			fmt.Printf("✓ Copied %s → %s\n", filepath.Base(inputPath), filepath.Base(convertOutput))
+			fmt.Printf("✓ Copied %s → %s\n", filepath.Base(inputPath), filepath.Base(result.OutputPath))
 		}
 	}
+	for _, w := range result.Warnings {
+		fmt.Printf("⚠ %s\n", w)
+	}
+
+	return nil
+}
 
+// loadPaletteFile registers the palettes defined in a JSON file.
+func loadPaletteFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read palette file: %w", err)
+	}
+	var defs map[string]map[string]string
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return fmt.Errorf("failed to parse palette file: %w", err)
+	}
+	for name, colorMap := range defs {
+		convert.RegisterPalette(name, colorMap)
+	}
+	return nil
+}
+
+func runConvertDirectory(inputDir string, opts convert.Options) error {
+	results, err := convert.Directory(inputDir, convertOutput, opts)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != nil {
+			failed++
+			fmt.Printf("✗ %s\n", r.InputPath)
+			fmt.Printf("  Error: %v\n", r.Error)
+			continue
+		}
+		fmt.Printf("✓ %s → %s\n", r.InputPath, r.OutputPath)
+		for _, w := range r.Warnings {
+			fmt.Printf("  ⚠ %s\n", w)
+		}
+	}
+
+	fmt.Printf("\n✓ Converted %d/%d SVG files\n", len(results)-failed, len(results))
+
+	if failed > 0 {
+		return fmt.Errorf("one or more files failed conversion")
+	}
 	return nil
 }
 
@@ -279,6 +465,7 @@ var (
 	processStrict           bool
 	processIncludeStroke    bool
 	processRemoveBackground bool
+	processFlattenGradients bool
 )
 
 var processCmd = &cobra.Command{
@@ -305,11 +492,18 @@ func runProcess(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("output path is required (-o, --output)")
 	}
 
+	// svgz output is processed as plain SVG and compressed once at the end
+	workPath := processOutput
+	if strings.EqualFold(filepath.Ext(processOutput), ".svgz") {
+		workPath = processOutput + ".tmp.svg"
+		defer func() { _ = os.Remove(workPath) }() // best-effort cleanup
+	}
+
 	// Step 1: Convert colors (to a temp file if we need to modify viewBox)
-	tempOutput := processOutput
+	tempOutput := workPath
 	if processCenter {
 		// Use temp file for intermediate processing
-		tempOutput = processOutput + ".tmp"
+		tempOutput = workPath + ".tmp"
 	}
 
 	opts := convert.Options{
@@ -317,6 +511,7 @@ func runProcess(_ *cobra.Command, args []string) error {
 		IncludeStroke:    processIncludeStroke,
 		PreserveMasks:    true,
 		RemoveBackground: processRemoveBackground,
+		FlattenGradients: processFlattenGradients,
 	}
 
 	result, err := convert.SVG(inputPath, tempOutput, opts)
@@ -342,36 +537,32 @@ func runProcess(_ *cobra.Command, args []string) error {
 
 	if processCenter && analysisResult.HasIssues {
 		// Apply the suggested viewBox fix
-		content, err := os.ReadFile(tempOutput)
+		content, err := svg.ReadFile(tempOutput)
 		if err != nil {
 			_ = os.Remove(tempOutput) // best-effort cleanup
 			return fmt.Errorf("failed to read for centering: %w", err)
 		}
 
-		contentStr := string(content)
-
-		// Replace viewBox with suggested value
-		viewBoxRe := regexp.MustCompile(`viewBox\s*=\s*["'][^"']*["']`)
-		newViewBox := fmt.Sprintf(`viewBox="%s"`, analysisResult.SuggestedViewBox)
-
-		if viewBoxRe.MatchString(contentStr) {
-			contentStr = viewBoxRe.ReplaceAllString(contentStr, newViewBox)
+		contentStr, err := svg.SetViewBox(string(content), analysisResult.SuggestedViewBoxParsed)
+		if err != nil {
+			_ = os.Remove(tempOutput) // best-effort cleanup
+			return fmt.Errorf("failed to set viewBox: %w", err)
 		}
 
-		if err := os.WriteFile(processOutput, []byte(contentStr), 0600); err != nil { //nolint:gosec // G703: Path from CLI flag
+		if err := os.WriteFile(workPath, []byte(contentStr), 0600); err != nil { //nolint:gosec // G703: Path from CLI flag
 			_ = os.Remove(tempOutput) // best-effort cleanup
 			return fmt.Errorf("failed to write centered file: %w", err)
 		}
 
-		if tempOutput != processOutput {
+		if tempOutput != workPath {
 			_ = os.Remove(tempOutput) // best-effort cleanup
 		}
 
 		fmt.Printf("✓ ViewBox centered: %s\n", analysisResult.SuggestedViewBox)
 	} else if processCenter {
 		// No issues, just rename temp to final
-		if tempOutput != processOutput {
-			if err := os.Rename(tempOutput, processOutput); err != nil {
+		if tempOutput != workPath {
+			if err := os.Rename(tempOutput, workPath); err != nil {
 				return fmt.Errorf("failed to finalize output: %w", err)
 			}
 		}
@@ -385,7 +576,7 @@ func runProcess(_ *cobra.Command, args []string) error {
 
 	// Step 3: Verify (if strict mode)
 	if processStrict {
-		verifyResult, err := verify.SVG(processOutput)
+		verifyResult, err := verify.SVG(workPath)
 		if err != nil {
 			return fmt.Errorf("verification failed: %w", err)
 		}
@@ -401,6 +592,16 @@ func runProcess(_ *cobra.Command, args []string) error {
 		fmt.Printf("✓ Verified pure vector (%s)\n", strings.Join(verifyResult.VectorElements, ", "))
 	}
 
+	if workPath != processOutput {
+		content, err := svg.ReadFile(workPath)
+		if err != nil {
+			return fmt.Errorf("failed to read for compression: %w", err)
+		}
+		if err := svg.WriteSVG(processOutput, content, svg.DefaultWriteOptions()); err != nil {
+			return fmt.Errorf("failed to write svgz output: %w", err)
+		}
+	}
+
 	fmt.Printf("\n✓ Processed: %s → %s\n", filepath.Base(inputPath), filepath.Base(processOutput))
 	return nil
 }
@@ -419,7 +620,8 @@ Equivalent to:
 This removes the background, converts all colors to white, centers the content,
 and verifies the result is pure vector.
 
-Security scanning is performed by default. Use --insecure to warn instead of fail.
+Security scanning is performed by default. Use --insecure to warn instead of fail,
+--security-level=standard to ignore low/medium findings, or --no-security to skip it.
 
 Examples:
   brandkit white icon_orig.svg -o icon_white.svg
@@ -429,30 +631,58 @@ Examples:
 		if whiteOutput == "" {
 			return fmt.Errorf("output path is required (-o, --output)")
 		}
-		result, err := brandkit.ProcessWhite(args[0], whiteOutput)
+		level, err := security.ParseScanLevel(whiteSecurityLevel)
+		if err != nil {
+			return err
+		}
+		// The CLI scans the output itself so --insecure can warn instead of fail
+		result, err := brandkit.ProcessWhiteWithOptions(args[0], whiteOutput, brandkit.ProcessOptions{
+			SkipSecurity:     true,
+			EmbedChecksum:    whiteChecksum,
+			FlattenGradients: whiteFlatten,
+		})
 		if err != nil {
 			return err
 		}
 		printProcessResult(result)
-		return runSecurityScanOnOutput(whiteOutput, whiteInsecure)
+		if whiteNoSecurity {
+			fmt.Println("⚠ Security scan skipped (--no-security)")
+			return nil
+		}
+		return runSecurityScanOnOutput(whiteOutput, level, whiteInsecure)
 	},
 }
 
 // color command (preset for preserving original colors)
 var (
-	colorOutput   string
-	colorInsecure bool
+	colorOutput        string
+	colorInsecure      bool
+	colorNoSecurity    bool
+	colorChecksum      bool
+	colorSecurityLevel string
 )
 
-// white command insecure flag
-var whiteInsecure bool
+// white command security flags
+var (
+	whiteInsecure      bool
+	whiteNoSecurity    bool
+	whiteChecksum      bool
+	whiteFlatten       bool
+	whiteSecurityLevel string
+)
 
 // security-scan command flags
 var (
 	securityScanReport  string
 	securityScanStrict  bool
+	securityScanLevel   string
 	securityScanProject string
 	securityScanVersion string
+	// security-scan only
+	securityScanJSON bool
+	// security-scan-all only
+	securityScanByDirectory bool
+	securityScanThreads     int
 )
 
 // security-scan command
@@ -468,13 +698,18 @@ var securityScanCmd = &cobra.Command{
 - Style blocks (low, strict mode only)
 - Anchor links (medium, strict mode only)
 
-Use --strict for comprehensive scanning (default: true).
-Use --report to output JSON report file.
+Use --strict for comprehensive scanning (default: true), or
+--level=strict|standard to choose the scan level explicitly.
+Use --report (-o) to write a JSON report file, or --json to write the
+report to stdout. When a report is generated, the command fails if its
+status is NO-GO.
 
 Examples:
   brandkit security-scan icon.svg
   brandkit security-scan brands/
-  brandkit security-scan brands/ --report=report.json`,
+  brandkit security-scan brands/ --level=standard
+  brandkit security-scan brands/ -o report.json
+  brandkit security-scan brands/ --json --project=acme --version=1.2.0`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSecurityScan,
 }
@@ -485,10 +720,9 @@ func runSecurityScan(_ *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
-	// Determine scan level
-	level := security.ScanLevelStandard
-	if securityScanStrict {
-		level = security.ScanLevelStrict
+	level, err := securityScanLevelFromFlags()
+	if err != nil {
+		return err
 	}
 
 	info, err := svg.GetPathInfo(path)
@@ -499,22 +733,13 @@ func runSecurityScan(_ *cobra.Command, args []string) error {
 	var results []*security.Result
 	if info.IsDir {
 		// Use level-aware scanning
-		files, err := svg.ListSVGFiles(path)
+		results, err = security.ScanDirectoryCtx(context.Background(), path, security.DirectoryScanOptions{
+			Level:   level,
+			Workers: securityScanThreads,
+		})
 		if err != nil {
 			return fmt.Errorf("error: %w", err)
 		}
-		for _, filePath := range files {
-			result, err := security.SVGWithLevel(filePath, level)
-			if err != nil {
-				results = append(results, &security.Result{
-					FilePath: filePath,
-					IsSecure: false,
-					Errors:   []string{err.Error()},
-				})
-				continue
-			}
-			results = append(results, result)
-		}
 	} else {
 		result, err := security.SVGWithLevel(path, level)
 		if err != nil {
@@ -524,7 +749,8 @@ func runSecurityScan(_ *cobra.Command, args []string) error {
 	}
 
 	// Generate report if requested
-	if securityScanReport != "" {
+	var report *security.TeamReport
+	if securityScanReport != "" || securityScanJSON {
 		project := securityScanProject
 		if project == "" {
 			project = "brandkit"
@@ -533,39 +759,65 @@ func runSecurityScan(_ *cobra.Command, args []string) error {
 		if ver == "" {
 			ver = version
 		}
-		report := security.GenerateReport(results, project, ver)
+		report = security.GenerateReport(results, project, ver)
 		reportJSON, err := report.ToJSON()
 		if err != nil {
 			return fmt.Errorf("failed to generate report: %w", err)
 		}
-		if err := os.WriteFile(securityScanReport, reportJSON, 0600); err != nil {
-			return fmt.Errorf("failed to write report: %w", err)
+		if securityScanReport != "" {
+			if err := os.WriteFile(securityScanReport, reportJSON, 0600); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+		}
+		if securityScanJSON {
+			fmt.Println(string(reportJSON))
+		} else {
+			fmt.Printf("✓ Report written to %s\n", securityScanReport)
 		}
-		fmt.Printf("✓ Report written to %s\n", securityScanReport)
 	}
 
+	// Both output modes fail on any threat or scan error
 	allSecure := true
 	for _, r := range results {
 		if !r.IsSuccess() {
 			allSecure = false
+			if securityScanJSON {
+				continue
+			}
 			fmt.Printf("✗ %s\n", filepath.Base(r.FilePath))
+			security.SortThreatsBySeverity(r.Threats)
 			for _, t := range r.Threats {
 				fmt.Printf("  [%s] %s: %s\n", t.Type, t.Description, t.Match)
 			}
 			for _, e := range r.Errors {
 				fmt.Printf("  Error: %s\n", e)
 			}
-		} else {
+		} else if !securityScanJSON {
 			fmt.Printf("✓ %s\n", filepath.Base(r.FilePath))
 		}
 	}
 
+	if report != nil && report.Status == security.StatusNoGo {
+		return fmt.Errorf("security report status is %s", report.Status)
+	}
 	if !allSecure {
 		return fmt.Errorf("one or more files have security threats")
 	}
 	return nil
 }
 
+// securityScanLevelFromFlags returns the scan level selected by --level,
+// falling back to --strict when --level is not set.
+func securityScanLevelFromFlags() (security.ScanLevel, error) {
+	if securityScanLevel != "" {
+		return security.ParseScanLevel(securityScanLevel)
+	}
+	if securityScanStrict {
+		return security.ScanLevelStrict, nil
+	}
+	return security.ScanLevelStandard, nil
+}
+
 // security-scan-all command (recursive for CI)
 var securityScanAllCmd = &cobra.Command{
 	Use:   "security-scan-all [path]",
@@ -580,6 +832,7 @@ Use --report to output a JSON report in team-report format.
 Examples:
   brandkit security-scan-all brands/
   brandkit security-scan-all brands/ --report=security-report.json
+  brandkit security-scan-all brands/ --report=security-report.json --by-directory
   brandkit security-scan-all . --strict=false`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSecurityScanAll,
@@ -591,33 +844,21 @@ func runSecurityScanAll(_ *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
-	// Determine scan level
-	level := security.ScanLevelStandard
-	if securityScanStrict {
-		level = security.ScanLevelStrict
+	level, err := securityScanLevelFromFlags()
+	if err != nil {
+		return err
 	}
 
-	// Get files recursively
-	files, err := svg.ListSVGFilesRecursive(path)
+	// Scan files recursively
+	results, err := security.ScanDirectoryCtx(context.Background(), path, security.DirectoryScanOptions{
+		Level:     level,
+		Recursive: true,
+		Workers:   securityScanThreads,
+	})
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
 
-	var results []*security.Result
-	for _, filePath := range files {
-		result, err := security.SVGWithLevel(filePath, level)
-		if err != nil {
-			results = append(results, &security.Result{
-				FilePath:     filePath,
-				IsSecure:     false,
-				ThreatCounts: make(map[security.ThreatType]int),
-				Errors:       []string{err.Error()},
-			})
-			continue
-		}
-		results = append(results, result)
-	}
-
 	// Generate report if requested
 	if securityScanReport != "" {
 		project := securityScanProject
@@ -629,6 +870,9 @@ func runSecurityScanAll(_ *cobra.Command, args []string) error {
 			ver = version
 		}
 		report := security.GenerateReport(results, project, ver)
+		if securityScanByDirectory {
+			report = security.GenerateRepoReport(results, path, project, ver)
+		}
 		reportJSON, err := report.ToJSON()
 		if err != nil {
 			return fmt.Errorf("failed to generate report: %w", err)
@@ -644,9 +888,13 @@ func runSecurityScanAll(_ *cobra.Command, args []string) error {
 	threatCounts := make(map[security.ThreatType]int)
 
 	for _, r := range results {
+		for _, t := range r.SuppressedThreats {
+			fmt.Printf("- %s: allowed [%s] %s\n", r.FilePath, t.Type, t.Description)
+		}
 		if !r.IsSuccess() {
 			allSecure = false
 			fmt.Printf("✗ %s\n", r.FilePath)
+			security.SortThreatsBySeverity(r.Threats)
 			for _, t := range r.Threats {
 				fmt.Printf("  [%s] %s: %s\n", t.Type, t.Description, t.Match)
 				threatCounts[t.Type]++
@@ -678,6 +926,9 @@ var (
 	sanitizeRemoveEventHandlers bool
 	sanitizeRemoveExternalRefs  bool
 	sanitizeRemoveAll           bool
+	sanitizeRemoveComments      bool
+	sanitizeRemoveStyleBlocks   bool
+	sanitizeKeepIDs             []string
 )
 
 var sanitizeCmd = &cobra.Command{
@@ -692,7 +943,8 @@ Examples:
   brandkit sanitize malicious.svg -o clean.svg
   brandkit sanitize icon.svg -o icon.svg  # In-place (overwrites)
   brandkit sanitize icon.svg -o clean.svg --remove-scripts
-  brandkit sanitize icon.svg -o clean.svg --remove-event-handlers`,
+  brandkit sanitize icon.svg -o clean.svg --remove-event-handlers
+  brandkit sanitize icon.svg -o clean.svg --remove-style-blocks --keep-id=brand-vars`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSanitize,
 }
@@ -709,6 +961,9 @@ func runSanitize(_ *cobra.Command, args []string) error {
 		RemoveEventHandlers: sanitizeRemoveEventHandlers,
 		RemoveExternalRefs:  sanitizeRemoveExternalRefs,
 		RemoveAll:           sanitizeRemoveAll,
+		RemoveComments:      sanitizeRemoveComments,
+		RemoveStyleBlocks:   sanitizeRemoveStyleBlocks,
+		KeepIDs:             sanitizeKeepIDs,
 	}
 
 	// If no specific options set, default to RemoveAll
@@ -734,6 +989,305 @@ func runSanitize(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// minify command
+var (
+	minifyOutput        string
+	minifyKeepComments  bool
+	minifyKeepXMLDecl   bool
+	minifyNoTrimNumbers bool
+)
+
+var minifyCmd = &cobra.Command{
+	Use:   "minify <input>",
+	Short: "Reduce the size of an SVG file",
+	Long: `Minify an SVG file without changing how it renders:
+- Remove XML comments
+- Remove whitespace between tags (text content is untouched)
+- Remove the <?xml?> declaration when it declares UTF-8 or no encoding
+- Trim trailing zeros in numeric attributes (1.500 → 1.5)
+
+Examples:
+  brandkit minify icon.svg -o icon.min.svg
+  brandkit minify icon.svg -o icon.svg  # In-place (overwrites)
+  brandkit minify icon.svg -o icon.min.svg --keep-comments`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMinify,
+}
+
+func runMinify(_ *cobra.Command, args []string) error {
+	inputPath := args[0]
+
+	if minifyOutput == "" {
+		return fmt.Errorf("output path is required (-o, --output)")
+	}
+
+	opts := minify.DefaultOptions()
+	opts.StripComments = !minifyKeepComments
+	opts.StripXMLDeclaration = !minifyKeepXMLDecl
+	opts.TrimNumbers = !minifyNoTrimNumbers
+
+	result, err := minify.MinifyFile(inputPath, minifyOutput, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Minified %s → %s (%d → %d bytes)\n", filepath.Base(inputPath), filepath.Base(minifyOutput), result.OriginalSize, result.MinifiedSize)
+	return nil
+}
+
+// fixup command
+var (
+	fixupRecursive bool
+	fixupMinify    bool
+	fixupWrite     bool
+	fixupBackup    bool
+)
+
+var fixupCmd = &cobra.Command{
+	Use:   "fixup <dir>",
+	Short: "Sanitize, recenter, and optionally minify SVG files in place",
+	Long: `Clean up all SVG files in a directory. For each file:
+1. Remove security threats
+2. Recenter the viewBox if content is off-center
+3. Minify (if --minify)
+
+By default this is a dry run that only reports what would change.
+Use --write to rewrite files in place.
+
+Examples:
+  brandkit fixup brands/ --recursive
+  brandkit fixup brands/ --recursive --write --backup
+  brandkit fixup brands/aws --minify --write`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFixup,
+}
+
+func runFixup(_ *cobra.Command, args []string) error {
+	dir := args[0]
+
+	results, err := brandkit.Fixup(dir, brandkit.FixupOptions{
+		Recursive: fixupRecursive,
+		Minify:    fixupMinify,
+		Write:     fixupWrite,
+		Backup:    fixupBackup,
+	})
+	if err != nil {
+		return err
+	}
+
+	changed, failed := 0, 0
+	var bytesSaved int64
+	for _, r := range results {
+		if r.Error != nil {
+			failed++
+			fmt.Printf("✗ %s: %v\n", r.FilePath, r.Error)
+			continue
+		}
+		for _, w := range r.Warnings {
+			fmt.Printf("⚠ %s: %s\n", r.FilePath, w)
+		}
+		if !r.Changed {
+			continue
+		}
+		changed++
+		bytesSaved += r.BytesSaved
+		var changes []string
+		if len(r.ThreatsRemoved) > 0 {
+			changes = append(changes, fmt.Sprintf("removed %d threats", len(r.ThreatsRemoved)))
+		}
+		if r.Recentered {
+			changes = append(changes, fmt.Sprintf("recentered (viewBox %s)", r.ViewBox))
+		}
+		if r.Minified {
+			changes = append(changes, fmt.Sprintf("minified (%d -> %d bytes)", r.InputSize, r.OutputSize))
+		}
+		if len(changes) == 0 {
+			changes = append(changes, "normalized")
+		}
+		verb := "would fix"
+		if r.Written {
+			verb = "fixed"
+		}
+		fmt.Printf("✓ %s %s: %s\n", verb, r.FilePath, strings.Join(changes, ", "))
+	}
+
+	fmt.Printf("\nSummary: %d files, %d changed, %d errors, %d bytes saved\n", len(results), changed, failed, bytesSaved)
+	if !fixupWrite && changed > 0 {
+		fmt.Println("Dry run: use --write to apply changes")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d files failed", failed)
+	}
+	return nil
+}
+
+// inspect command
+var inspectJSON bool
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <file>",
+	Short: "Show centering, purity, and security results for an icon",
+	Long: `Run every check on a single SVG file and print the results together:
+- Centering and padding (as in analyze)
+- Pure vector verification (as in verify)
+- Security threats (as in security-scan --strict)
+
+Examples:
+  brandkit inspect brands/aws/icon_white.svg
+  brandkit inspect icon.svg --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+// inspectOutput is the --json form of an inspection.
+type inspectOutput struct {
+	File    string
+	Success bool
+	*brandkit.Inspection
+}
+
+func runInspect(_ *cobra.Command, args []string) error {
+	path := args[0]
+
+	content, err := svg.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	inspection, err := brandkit.Inspect(content)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	if inspectJSON {
+		out, err := json.MarshalIndent(inspectOutput{File: path, Success: inspection.IsSuccess(), Inspection: inspection}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(out))
+	} else {
+		printInspection(path, inspection)
+	}
+
+	if !inspection.IsSuccess() {
+		return fmt.Errorf("%s has issues", path)
+	}
+	return nil
+}
+
+func printInspection(path string, i *brandkit.Inspection) {
+	mark := func(ok bool) string {
+		if ok {
+			return "✓"
+		}
+		return "✗"
+	}
+
+	fmt.Printf("%s %s\n", mark(i.IsSuccess()), path)
+
+	a := i.Analysis
+	fmt.Printf("\n%s Centering\n", mark(!a.HasIssues))
+	fmt.Printf("  ViewBox: %.1f %.1f %.1f %.1f\n", a.ViewBox.X, a.ViewBox.Y, a.ViewBox.Width, a.ViewBox.Height)
+	fmt.Printf("  Content: %.1f,%.1f to %.1f,%.1f (%.1fx%.1f)\n",
+		a.ContentBox.MinX, a.ContentBox.MinY, a.ContentBox.MaxX, a.ContentBox.MaxY,
+		a.ContentBox.Width(), a.ContentBox.Height())
+	fmt.Printf("  Padding: L:%.1f%% R:%.1f%% T:%.1f%% B:%.1f%%\n",
+		a.PaddingLeft, a.PaddingRight, a.PaddingTop, a.PaddingBottom)
+	fmt.Printf("  Assessment: %s\n", a.Assessment)
+	if a.HasIssues {
+		fmt.Printf("  Suggested viewBox: %s\n", a.SuggestedViewBox)
+	}
+
+	v := i.Verify
+	fmt.Printf("\n%s Purity\n", mark(v.IsSuccess()))
+	if len(v.VectorElements) > 0 {
+		sort.Strings(v.VectorElements)
+		fmt.Printf("  Vector elements: %s\n", strings.Join(v.VectorElements, ", "))
+	}
+	for _, e := range v.Errors {
+		fmt.Printf("  Error: %s\n", e)
+	}
+
+	sec := i.Security
+	fmt.Printf("\n%s Security\n", mark(sec.IsSuccess()))
+	if len(sec.Threats) == 0 && len(sec.Errors) == 0 {
+		fmt.Println("  No threats found")
+	}
+	security.SortThreatsBySeverity(sec.Threats)
+	for _, t := range sec.Threats {
+		fmt.Printf("  [%s] %s: %s\n", t.Type, t.Description, t.Match)
+	}
+	for _, e := range sec.Errors {
+		fmt.Printf("  Error: %s\n", e)
+	}
+}
+
+// palette command
+var paletteJSON bool
+
+var paletteCmd = &cobra.Command{
+	Use:   "palette <input>",
+	Short: "List the colors used in an SVG file or directory",
+	Long: `List each distinct fill and stroke color with its number of occurrences
+and where it is used, most frequent first. For a directory, colors are
+aggregated across all SVG files in the tree.
+
+Examples:
+  brandkit palette icon.svg
+  brandkit palette brands/aws/ --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPalette,
+}
+
+func runPalette(_ *cobra.Command, args []string) error {
+	path := args[0]
+
+	info, err := svg.GetPathInfo(path)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	var entries []convert.PaletteEntry
+	if info.IsDir {
+		entries, err = convert.PaletteDirectory(path)
+	} else {
+		entries, err = convert.Palette(path)
+	}
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	if paletteJSON {
+		if entries == nil {
+			entries = []convert.PaletteEntry{}
+		}
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No colors found")
+		return nil
+	}
+	for _, e := range entries {
+		var usage []string
+		if e.Fill {
+			usage = append(usage, "fill")
+		}
+		if e.Stroke {
+			usage = append(usage, "stroke")
+		}
+		if e.Style {
+			usage = append(usage, "style")
+		}
+		fmt.Printf("%s  %5d  %s\n", e.Color, e.Count, strings.Join(usage, ", "))
+	}
+	return nil
+}
+
 var colorCmd = &cobra.Command{
 	Use:   "color <input>",
 	Short: "Create centered color icon on transparent background",
@@ -745,7 +1299,8 @@ Equivalent to:
 This removes the background, centers the content, and verifies the result
 is pure vector while preserving the original colors.
 
-Security scanning is performed by default. Use --insecure to warn instead of fail.
+Security scanning is performed by default. Use --insecure to warn instead of fail,
+--security-level=standard to ignore low/medium findings, or --no-security to skip it.
 
 Examples:
   brandkit color icon_orig.svg -o icon_color.svg
@@ -755,12 +1310,24 @@ Examples:
 		if colorOutput == "" {
 			return fmt.Errorf("output path is required (-o, --output)")
 		}
-		result, err := brandkit.ProcessColor(args[0], colorOutput)
+		level, err := security.ParseScanLevel(colorSecurityLevel)
+		if err != nil {
+			return err
+		}
+		// The CLI scans the output itself so --insecure can warn instead of fail
+		result, err := brandkit.ProcessColorWithOptions(args[0], colorOutput, brandkit.ProcessOptions{
+			SkipSecurity:  true,
+			EmbedChecksum: colorChecksum,
+		})
 		if err != nil {
 			return err
 		}
 		printProcessResult(result)
-		return runSecurityScanOnOutput(colorOutput, colorInsecure)
+		if colorNoSecurity {
+			fmt.Println("⚠ Security scan skipped (--no-security)")
+			return nil
+		}
+		return runSecurityScanOnOutput(colorOutput, level, colorInsecure)
 	},
 }
 
@@ -778,17 +1345,26 @@ func printProcessResult(result *brandkit.ProcessResult) {
 	if result.Verified {
 		fmt.Printf("✓ Verified pure vector (%s)\n", strings.Join(result.VectorElements, ", "))
 	}
+	if result.Checksum != "" {
+		fmt.Printf("✓ Checksum embedded: sha256=%s\n", result.Checksum)
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("⚠ %s\n", w)
+	}
 	fmt.Printf("\n✓ Processed: %s → %s\n", filepath.Base(result.InputPath), filepath.Base(result.OutputPath))
 }
 
 // runSecurityScanOnOutput performs a security scan on the output file and handles the result.
-func runSecurityScanOnOutput(outputPath string, insecureMode bool) error {
-	secResult, err := security.SVG(outputPath)
+func runSecurityScanOnOutput(outputPath string, level security.ScanLevel, insecureMode bool) error {
+	// Read through svg.ReadFile so svgz output is scanned decompressed
+	content, err := svg.ReadFile(outputPath)
 	if err != nil {
 		return fmt.Errorf("security scan failed: %w", err)
 	}
+	secResult := security.ScanContentWithLevel(string(content), nil, level)
 	if !secResult.IsSuccess() {
 		fmt.Printf("⚠ Security threats detected:\n")
+		security.SortThreatsBySeverity(secResult.Threats)
 		for _, t := range secResult.Threats {
 			fmt.Printf("  [%s] %s: %s\n", t.Type, t.Description, t.Match)
 		}
@@ -805,12 +1381,15 @@ func runSecurityScanOnOutput(outputPath string, insecureMode bool) error {
 func init() {
 	// analyze command
 	analyzeCmd.Flags().BoolVar(&analyzeShowFix, "fix", false, "Show suggested viewBox fixes")
+	analyzeCmd.Flags().StringVar(&analyzeFormat, "format", "text", "Output format: text or json")
 	rootCmd.AddCommand(analyzeCmd)
 
 	// verify command
 	rootCmd.AddCommand(verifyCmd)
 
 	// verify-all command
+	verifyAllCmd.Flags().IntVar(&verifyAllThreads, "threads", 0, "Number of files to verify concurrently (default GOMAXPROCS)")
+	verifyAllCmd.Flags().StringVar(&verifyAllFormat, "format", "text", "Output format: text, json, or sarif")
 	rootCmd.AddCommand(verifyAllCmd)
 
 	// convert command
@@ -818,7 +1397,18 @@ func init() {
 	convertCmd.Flags().StringVarP(&convertColor, "color", "c", "", "Target color (hex or name, e.g., ffffff, white)")
 	convertCmd.Flags().BoolVar(&convertIncludeStroke, "include-stroke", false, "Also convert stroke colors")
 	convertCmd.Flags().BoolVar(&convertPreserveMasks, "preserve-masks", true, "Don't modify colors in mask/clipPath")
+	convertCmd.Flags().BoolVar(&convertPreserveDefs, "preserve-defs", false, "Don't modify colors in defs (gradients, symbols)")
 	convertCmd.Flags().BoolVar(&convertRemoveBackground, "remove-background", false, "Remove full-bleed background rect/circle")
+	convertCmd.Flags().BoolVarP(&convertRecursive, "recursive", "r", false, "Convert all SVG files in a directory tree into the output directory")
+	convertCmd.Flags().StringVar(&convertPalette, "palette", "", "Named palette remapping several colors at once")
+	convertCmd.Flags().StringVar(&convertPaletteFile, "palette-file", "", "JSON file defining named palettes")
+	convertCmd.Flags().BoolVar(&convertPatch, "patch", false, "Print a unified diff of the conversion instead of writing the output file")
+	convertCmd.Flags().BoolVar(&convertHashName, "hash-name", false, "Name the output {sha256}.svg in the output directory")
+	convertCmd.Flags().BoolVar(&convertFlattenVars, "flatten-vars", false, "Replace var(--name, fallback) colors instead of preserving them")
+	convertCmd.Flags().BoolVar(&convertFlattenGradients, "flatten-gradients", false, "Replace gradient and pattern fills with the flat color")
+	convertCmd.Flags().IntVar(&convertMatchTolerance, "match-tolerance", 0, "Max per-channel difference (0-255) for a color to match a palette source")
+	convertCmd.Flags().IntVar(&convertPrecision, "precision", 0, "Round path coordinates to this many decimal places (0 = unchanged)")
+	convertCmd.Flags().StringVar(&convertWarnInvisibleOn, "warn-invisible-on", "", "Background color; warn about output colors that would be invisible on it")
 	rootCmd.AddCommand(convertCmd)
 
 	// process command
@@ -828,30 +1418,44 @@ func init() {
 	processCmd.Flags().BoolVar(&processStrict, "strict", true, "Fail on embedded binary")
 	processCmd.Flags().BoolVar(&processIncludeStroke, "include-stroke", false, "Also convert stroke colors")
 	processCmd.Flags().BoolVar(&processRemoveBackground, "remove-background", false, "Remove full-bleed background rect/circle")
+	processCmd.Flags().BoolVar(&processFlattenGradients, "flatten-gradients", false, "Replace gradient and pattern fills with the flat color")
 	rootCmd.AddCommand(processCmd)
 
 	// white command
 	whiteCmd.Flags().StringVarP(&whiteOutput, "output", "o", "", "Output file path (required)")
 	whiteCmd.Flags().BoolVar(&whiteInsecure, "insecure", false, "Warn on security threats instead of failing")
+	whiteCmd.Flags().BoolVar(&whiteNoSecurity, "no-security", false, "Skip the security scan")
+	whiteCmd.Flags().StringVar(&whiteSecurityLevel, "security-level", "strict", "Security scan level (strict, standard)")
+	whiteCmd.Flags().BoolVar(&whiteChecksum, "checksum", false, "Embed a brandkit:sha256 checksum comment in the output")
+	whiteCmd.Flags().BoolVar(&whiteFlatten, "flatten-gradients", false, "Replace gradient and pattern fills with white")
 	rootCmd.AddCommand(whiteCmd)
 
 	// color command
 	colorCmd.Flags().StringVarP(&colorOutput, "output", "o", "", "Output file path (required)")
 	colorCmd.Flags().BoolVar(&colorInsecure, "insecure", false, "Warn on security threats instead of failing")
+	colorCmd.Flags().BoolVar(&colorNoSecurity, "no-security", false, "Skip the security scan")
+	colorCmd.Flags().StringVar(&colorSecurityLevel, "security-level", "strict", "Security scan level (strict, standard)")
+	colorCmd.Flags().BoolVar(&colorChecksum, "checksum", false, "Embed a brandkit:sha256 checksum comment in the output")
 	rootCmd.AddCommand(colorCmd)
 
 	// security-scan command
-	securityScanCmd.Flags().StringVar(&securityScanReport, "report", "", "Output JSON report file path")
+	securityScanCmd.Flags().StringVarP(&securityScanReport, "report", "o", "", "Output JSON report file path")
+	securityScanCmd.Flags().BoolVar(&securityScanJSON, "json", false, "Write the JSON report to stdout instead of the per-file summary")
 	securityScanCmd.Flags().BoolVar(&securityScanStrict, "strict", true, "Strict mode: detect all threats including style blocks and animations")
+	securityScanCmd.Flags().StringVar(&securityScanLevel, "level", "", "Scan level (strict, standard); overrides --strict")
 	securityScanCmd.Flags().StringVar(&securityScanProject, "project", "", "Project name for report (default: brandkit)")
 	securityScanCmd.Flags().StringVar(&securityScanVersion, "version", "", "Version for report (default: CLI version)")
+	securityScanCmd.Flags().IntVar(&securityScanThreads, "threads", 0, "Number of files to scan concurrently (default GOMAXPROCS)")
 	rootCmd.AddCommand(securityScanCmd)
 
 	// security-scan-all command (shares flags with security-scan)
 	securityScanAllCmd.Flags().StringVar(&securityScanReport, "report", "", "Output JSON report file path")
 	securityScanAllCmd.Flags().BoolVar(&securityScanStrict, "strict", true, "Strict mode: detect all threats including style blocks and animations")
+	securityScanAllCmd.Flags().StringVar(&securityScanLevel, "level", "", "Scan level (strict, standard); overrides --strict")
 	securityScanAllCmd.Flags().StringVar(&securityScanProject, "project", "", "Project name for report (default: brandkit)")
 	securityScanAllCmd.Flags().StringVar(&securityScanVersion, "version", "", "Version for report (default: CLI version)")
+	securityScanAllCmd.Flags().IntVar(&securityScanThreads, "threads", 0, "Number of files to scan concurrently (default GOMAXPROCS)")
+	securityScanAllCmd.Flags().BoolVar(&securityScanByDirectory, "by-directory", false, "Group the report by top-level directory instead of threat category")
 	rootCmd.AddCommand(securityScanAllCmd)
 
 	// sanitize command
@@ -860,5 +1464,30 @@ func init() {
 	sanitizeCmd.Flags().BoolVar(&sanitizeRemoveEventHandlers, "remove-event-handlers", false, "Remove event handler attributes only")
 	sanitizeCmd.Flags().BoolVar(&sanitizeRemoveExternalRefs, "remove-external-refs", false, "Remove external URLs only")
 	sanitizeCmd.Flags().BoolVar(&sanitizeRemoveAll, "remove-all", true, "Remove all threat types (default)")
+	sanitizeCmd.Flags().BoolVar(&sanitizeRemoveComments, "remove-comments", false, "Also remove XML comments")
+	sanitizeCmd.Flags().BoolVar(&sanitizeRemoveStyleBlocks, "remove-style-blocks", false, "Also remove <style> elements")
+	sanitizeCmd.Flags().StringSliceVar(&sanitizeKeepIDs, "keep-id", nil, "Id of a known-safe style block to keep (repeatable)")
 	rootCmd.AddCommand(sanitizeCmd)
+
+	// minify command
+	minifyCmd.Flags().StringVarP(&minifyOutput, "output", "o", "", "Output file path (required)")
+	minifyCmd.Flags().BoolVar(&minifyKeepComments, "keep-comments", false, "Keep XML comments")
+	minifyCmd.Flags().BoolVar(&minifyKeepXMLDecl, "keep-xml-declaration", false, "Keep the <?xml?> declaration")
+	minifyCmd.Flags().BoolVar(&minifyNoTrimNumbers, "no-trim-numbers", false, "Do not trim trailing zeros in numeric attributes")
+	rootCmd.AddCommand(minifyCmd)
+
+	// fixup command
+	fixupCmd.Flags().BoolVarP(&fixupRecursive, "recursive", "r", false, "Process the whole directory tree")
+	fixupCmd.Flags().BoolVar(&fixupMinify, "minify", false, "Also minify files")
+	fixupCmd.Flags().BoolVar(&fixupWrite, "write", false, "Rewrite files in place (default is a dry run)")
+	fixupCmd.Flags().BoolVar(&fixupBackup, "backup", false, "Keep a .bak copy of each rewritten file")
+	rootCmd.AddCommand(fixupCmd)
+
+	// inspect command
+	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Output the results as JSON")
+	rootCmd.AddCommand(inspectCmd)
+
+	// palette command
+	paletteCmd.Flags().BoolVar(&paletteJSON, "json", false, "Output the palette as a JSON array")
+	rootCmd.AddCommand(paletteCmd)
 }
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grokify/brandkit/svg/analyze"
+	"github.com/grokify/brandkit/svg/security"
+	"github.com/grokify/brandkit/svg/verify"
+)
+
+// runCLI runs the root command with args and returns what it printed to
+// stdout.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+
+	rootCmd.SetArgs(args)
+	runErr := rootCmd.Execute()
+	_ = w.Close()
+	return <-done, runErr
+}
+
+func TestThreads(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 40; i++ {
+		content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`
+		if i%10 == 0 {
+			content = `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,AAAA"/><script>alert(1)</script></svg>`
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("icon%02d.svg", i)), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := runCLI(t, "verify-all", dir, "--threads", "4")
+	if err == nil {
+		t.Error("verify-all: expected error for files with embedded data")
+	}
+	if !strings.Contains(out, "Verified 36/40 SVG files") {
+		t.Errorf("verify-all output missing aggregate count:\n%s", out)
+	}
+
+	out, err = runCLI(t, "security-scan", dir, "--threads", "4")
+	if err == nil {
+		t.Error("security-scan: expected error for files with scripts")
+	}
+	if got := strings.Count(out, "✗ "); got != 4 {
+		t.Errorf("security-scan reported %d insecure files, want 4", got)
+	}
+	if got := strings.Count(out, "✓ "); got != 36 {
+		t.Errorf("security-scan reported %d secure files, want 36", got)
+	}
+	// Results are sorted by file path regardless of completion order
+	if first := strings.Index(out, "icon00.svg"); first < 0 || first > strings.Index(out, "icon01.svg") {
+		t.Errorf("security-scan output not sorted:\n%s", out)
+	}
+}
+
+func TestSecurityScanReport(t *testing.T) {
+	defer func() {
+		securityScanJSON = false
+		securityScanReport = ""
+		securityScanLevel = ""
+		securityScanProject = ""
+	}()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "clean.svg"), []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "script.svg"), []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "security-scan", dir, "--json", "--level", "standard", "--project", "acme")
+	if err == nil || !strings.Contains(err.Error(), "NO-GO") {
+		t.Errorf("security-scan --json: err = %v, want NO-GO error", err)
+	}
+	var report security.TeamReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("security-scan --json: invalid JSON: %v\n%s", err, out)
+	}
+	if report.Status != security.StatusNoGo {
+		t.Errorf("report status = %s, want %s", report.Status, security.StatusNoGo)
+	}
+	if report.Project != "acme" {
+		t.Errorf("report project = %q, want acme", report.Project)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	securityScanJSON = false
+	if _, err := runCLI(t, "security-scan", dir, "-o", reportPath); err == nil {
+		t.Error("security-scan -o: expected error for NO-GO report")
+	}
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Errorf("security-scan -o: report not written: %v", err)
+	}
+
+	if _, err := runCLI(t, "security-scan", dir, "--level", "paranoid"); err == nil {
+		t.Error("security-scan --level paranoid: expected error")
+	}
+}
+
+func TestSecurityScanJSONScanError(t *testing.T) {
+	defer func() {
+		securityScanJSON = false
+		securityScanLevel = ""
+	}()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "clean.svg"), []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "empty.svg"), []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "security-scan", dir, "--json")
+	if err == nil {
+		t.Error("security-scan --json: expected error for unscannable file")
+	}
+	var report security.TeamReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("security-scan --json: invalid JSON: %v\n%s", err, out)
+	}
+	if report.Status != security.StatusNoGo {
+		t.Errorf("report status = %s, want %s", report.Status, security.StatusNoGo)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+	clean := filepath.Join(dir, "clean.svg")
+	bad := filepath.Join(dir, "bad.svg")
+	files := map[string]string{
+		clean: `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="5" y="5" width="90" height="90"/></svg>`,
+		bad:   `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="60" y="60" width="30" height="30"/><image href="data:image/png;base64,AAAA"/><script>alert(1)</script></svg>`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := runCLI(t, "inspect", clean, "--json=false")
+	if err != nil {
+		t.Errorf("inspect clean icon: %v", err)
+	}
+	for _, want := range []string{"✓ Centering", "✓ Purity", "✓ Security", "No threats found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("clean output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "inspect", bad, "--json=false")
+	if err == nil {
+		t.Error("inspect problematic icon: expected error")
+	}
+	for _, want := range []string{"✗ Centering", "Suggested viewBox", "✗ Purity", "base64 embedded image", "✗ Security", "script"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("problematic output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, _ = runCLI(t, "inspect", bad, "--json")
+	var decoded struct {
+		File     string
+		Success  bool
+		Analysis struct {
+			HasIssues bool `json:"has_issues"`
+		}
+		Verify   struct{ IsPureVector bool }
+		Security struct{ IsSecure bool }
+	}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if decoded.File != bad || decoded.Success || !decoded.Analysis.HasIssues || decoded.Verify.IsPureVector || decoded.Security.IsSecure {
+		t.Errorf("unexpected JSON result: %+v", decoded)
+	}
+}
+
+func TestPalette(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.svg": `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 0 0 L 10 10" fill="#f00"/><path d="M 0 0 L 20 20" fill="red" stroke="#000"/></svg>`,
+		"b.svg": `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 0 0 L 10 10" style="fill:#ff0000"/></svg>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := runCLI(t, "palette", filepath.Join(dir, "a.svg"), "--json=false")
+	if err != nil {
+		t.Fatalf("palette: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "#ff0000      2  fill") || !strings.HasPrefix(lines[1], "#000000      1  stroke") {
+		t.Errorf("unexpected palette output:\n%s", out)
+	}
+
+	out, err = runCLI(t, "palette", dir, "--json")
+	if err != nil {
+		t.Fatalf("palette --json: %v", err)
+	}
+	var entries []struct {
+		Color string
+		Count int
+		Style bool
+	}
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(entries) != 2 || entries[0].Color != "#ff0000" || entries[0].Count != 3 || !entries[0].Style {
+		t.Errorf("unexpected aggregated palette: %+v", entries)
+	}
+}
+
+func TestAnalyzeJSON(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "icon.svg")
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="60" y="60" width="30" height="30"/></svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { analyzeFormat = "text" }()
+
+	out, err := runCLI(t, "analyze", input, "--format", "json")
+	if err == nil {
+		t.Error("expected error for off-center icon")
+	}
+	var decoded []*analyze.Result
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	want, err := analyze.SVG(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || !reflect.DeepEqual(decoded[0], want) {
+		t.Errorf("decoded result = %+v, want %+v", decoded, want)
+	}
+	for _, key := range []string{`"view_box"`, `"content_box"`, `"min_x"`, `"padding_left"`, `"assessment"`, `"suggested_view_box"`, `"has_issues": true`} {
+		if !strings.Contains(out, key) {
+			t.Errorf("JSON output missing %s:\n%s", key, out)
+		}
+	}
+
+	if _, err := runCLI(t, "analyze", input, "--format", "yaml"); err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+}
+
+func TestVerifyAllFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"clean.svg":  `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`,
+		"raster.svg": `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,AAAA"/></svg>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { verifyAllFormat = "text" }()
+
+	out, err := runCLI(t, "verify-all", dir, "--format", "json")
+	if err == nil {
+		t.Error("expected error for file with embedded data")
+	}
+	var results []*verify.Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(results) != 2 {
+		t.Errorf("got %d JSON results, want 2", len(results))
+	}
+
+	out, _ = runCLI(t, "verify-all", dir, "--format", "sarif")
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid SARIF output: %v\n%s", err, out)
+	}
+	for _, key := range []string{"$schema", "version", "runs"} {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("SARIF document missing %q", key)
+		}
+	}
+	var runs []struct {
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(doc["runs"], &runs); err != nil {
+		t.Fatalf("invalid SARIF runs: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Results == nil {
+		t.Fatalf("SARIF runs = %+v, want one run with results", runs)
+	}
+	var rules []string
+	for _, r := range runs[0].Results {
+		rules = append(rules, r.RuleID)
+		if uri := r.Locations[0].PhysicalLocation.ArtifactLocation.URI; !strings.HasSuffix(uri, "raster.svg") {
+			t.Errorf("result location = %q, want raster.svg", uri)
+		}
+	}
+	if strings.Join(rules, ",") != "base64-embedded-image,href-with-embedded-image-data" {
+		t.Errorf("SARIF rule IDs = %v", rules)
+	}
+}
+
+func TestMinify(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "icon.svg")
+	output := filepath.Join(dir, "icon.min.svg")
+	content := `<?xml version="1.0"?>
+<!-- comment -->
+<svg viewBox="0 0 100.0 100.0" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10.0 10.0 L 90.0 90.0"/>
+</svg>
+`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "minify", input, "-o", output)
+	if err != nil {
+		t.Fatalf("minify: %v", err)
+	}
+	if !strings.Contains(out, "Minified icon.svg → icon.min.svg") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`
+	if string(got) != want {
+		t.Errorf("minified output =\n%s\nwant\n%s", got, want)
+	}
+}
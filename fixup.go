@@ -0,0 +1,127 @@
+package brandkit
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grokify/mogo/os/osutil"
+
+	"github.com/grokify/brandkit/svg"
+	"github.com/grokify/brandkit/svg/analyze"
+	"github.com/grokify/brandkit/svg/optimize"
+	"github.com/grokify/brandkit/svg/security"
+)
+
+// FixupOptions configures Fixup.
+type FixupOptions struct {
+	Recursive bool // Process the whole directory tree
+	Minify    bool // Also apply the default optimizations
+	Write     bool // Rewrite changed files in place; otherwise only report (dry run)
+	Backup    bool // Keep a copy of each rewritten file with a .bak suffix
+}
+
+// FixupResult describes the changes Fixup made, or would make, to a file.
+type FixupResult struct {
+	FilePath       string
+	ThreatsRemoved []security.Threat
+	Recentered     bool
+	ViewBox        string // New viewBox, if recentered
+	Minified       bool
+	Changed        bool
+	Written        bool
+	BackupPath     string
+	Warnings       []string
+	InputSize      int64 // Size of the original file in bytes
+	OutputSize     int64 // Size of the fixed content in bytes, whether or not it was written
+	BytesSaved     int64 // InputSize minus OutputSize; negative if the content grew
+	Error          error
+}
+
+// Fixup sanitizes security threats, recenters off-center content, and
+// optionally minifies every SVG file in a directory. Files are only
+// rewritten when opts.Write is set. Errors for individual files are
+// recorded in their FixupResult and do not stop the batch.
+func Fixup(dir string, opts FixupOptions) ([]*FixupResult, error) {
+	var files []string
+	var results []*FixupResult
+	if opts.Recursive {
+		var pathErrs []*svg.PathError
+		var err error
+		files, pathErrs, err = svg.WalkSVGFiles(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+		for _, pe := range pathErrs {
+			results = append(results, &FixupResult{FilePath: pe.Path, Error: pe})
+		}
+	} else {
+		var err error
+		files, err = svg.ListSVGFiles(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+	}
+
+	for _, filePath := range files {
+		results = append(results, fixupFile(filePath, opts))
+	}
+	return results, nil
+}
+
+// fixupFile applies the fixup steps to a single file.
+func fixupFile(filePath string, opts FixupOptions) *FixupResult {
+	result := &FixupResult{FilePath: filePath}
+
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return result
+	}
+
+	fixed, threats := security.SanitizeContent(string(original), security.DefaultSanitizeOptions())
+	result.ThreatsRemoved = threats
+
+	analysis, err := analyze.Content([]byte(fixed))
+	switch {
+	case err != nil:
+		result.Warnings = append(result.Warnings, fmt.Sprintf("skipped recentering: %v", err))
+	case analysis.HasIssues:
+		recentered, err := svg.SetViewBox(fixed, analysis.SuggestedViewBoxParsed)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("skipped recentering: %v", err))
+			break
+		}
+		fixed = recentered
+		result.Recentered = true
+		result.ViewBox = analysis.SuggestedViewBox
+	}
+
+	if opts.Minify {
+		minified := optimize.Content(fixed, optimize.DefaultOptions())
+		result.Minified = minified != fixed
+		fixed = minified
+	} else {
+		fixed = string(svg.DetectLineEndings(original).Apply([]byte(fixed)))
+	}
+	result.Changed = fixed != string(original)
+	result.InputSize = int64(len(original))
+	result.OutputSize = int64(len(fixed))
+	result.BytesSaved = result.InputSize - result.OutputSize
+	if !result.Changed || !opts.Write {
+		return result
+	}
+
+	if opts.Backup {
+		result.BackupPath = filePath + ".bak"
+		if err := osutil.WriteFileSecure(result.BackupPath, original, 0600); err != nil {
+			result.Error = fmt.Errorf("failed to write backup: %w", err)
+			return result
+		}
+	}
+	if err := osutil.WriteFileSecure(filePath, []byte(fixed), 0600); err != nil {
+		result.Error = fmt.Errorf("failed to write file: %w", err)
+		return result
+	}
+	result.Written = true
+	return result
+}
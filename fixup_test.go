@@ -0,0 +1,107 @@
+package brandkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixup(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ok.svg":       `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="5" y="5" width="90" height="90"/></svg>`,
+		"sub/evil.svg": `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><rect x="5" y="5" width="90" height="90"/></svg>`,
+		"sub/off.svg":  `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="60" y="60" width="30" height="30"/></svg>`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Dry run reports changes without modifying files
+	results, err := Fixup(dir, FixupOptions{Recursive: true})
+	if err != nil {
+		t.Fatalf("Fixup error: %v", err)
+	}
+	byName := map[string]*FixupResult{}
+	for _, r := range results {
+		rel, _ := filepath.Rel(dir, r.FilePath)
+		byName[filepath.ToSlash(rel)] = r
+	}
+	if len(byName) != 3 {
+		t.Fatalf("got %d results, want 3", len(byName))
+	}
+	if byName["ok.svg"].Changed {
+		t.Error("ok.svg should be unchanged")
+	}
+	if evil := byName["sub/evil.svg"]; !evil.Changed || len(evil.ThreatsRemoved) == 0 {
+		t.Errorf("evil.svg = %+v, want threats removed", evil)
+	}
+	if off := byName["sub/off.svg"]; !off.Changed || !off.Recentered {
+		t.Errorf("off.svg = %+v, want recentered", off)
+	}
+	for name, content := range files {
+		got, _ := os.ReadFile(filepath.Join(dir, name))
+		if string(got) != content {
+			t.Errorf("dry run modified %s", name)
+		}
+	}
+
+	// Write with backups
+	if _, err := Fixup(dir, FixupOptions{Recursive: true, Write: true, Backup: true}); err != nil {
+		t.Fatalf("Fixup error: %v", err)
+	}
+	evil, _ := os.ReadFile(filepath.Join(dir, "sub/evil.svg"))
+	if strings.Contains(string(evil), "<script") {
+		t.Errorf("evil.svg not sanitized: %s", evil)
+	}
+	off, _ := os.ReadFile(filepath.Join(dir, "sub/off.svg"))
+	if strings.Contains(string(off), `viewBox="0 0 100 100"`) {
+		t.Errorf("off.svg not recentered: %s", off)
+	}
+	if backup, err := os.ReadFile(filepath.Join(dir, "sub/evil.svg.bak")); err != nil || string(backup) != files["sub/evil.svg"] {
+		t.Errorf("evil.svg.bak missing or wrong: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ok.svg.bak")); !os.IsNotExist(err) {
+		t.Error("unchanged file should not be backed up")
+	}
+}
+
+func TestFixupBytesSaved(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "icon.svg")
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <!-- exported by an editor -->
+  <rect x="5" y="5" width="90" height="90"/>
+</svg>
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Fixup(dir, FixupOptions{Minify: true, Write: true})
+	if err != nil {
+		t.Fatalf("Fixup error: %v", err)
+	}
+	r := results[0]
+	if !r.Minified || !r.Written {
+		t.Fatalf("Minified, Written = %v, %v, want true", r.Minified, r.Written)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.InputSize != int64(len(content)) || r.OutputSize != int64(len(written)) {
+		t.Errorf("sizes = %d -> %d, want %d -> %d", r.InputSize, r.OutputSize, len(content), len(written))
+	}
+	if r.BytesSaved != r.InputSize-r.OutputSize || r.BytesSaved <= 0 {
+		t.Errorf("BytesSaved = %d, want %d", r.BytesSaved, r.InputSize-r.OutputSize)
+	}
+}
@@ -14,11 +14,18 @@ package brandkit
 
 import (
 	"embed"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io/fs"
+	"os"
 	"path"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+
+	"github.com/grokify/brandkit/svg"
 )
 
 //go:embed brands/*/icon_white.svg brands/*/icon_color.svg brands/*/icon_orig.svg
@@ -36,12 +43,100 @@ const (
 	IconVariantOrig IconVariant = "orig"
 )
 
+// AllVariants returns all icon variants.
+func AllVariants() []IconVariant {
+	return []IconVariant{IconVariantWhite, IconVariantColor, IconVariantOrig}
+}
+
+// ParseIconVariant parses a variant name such as "white". Matching is
+// case-insensitive. For unknown names the error suggests the closest
+// valid variant, e.g. "whte" suggests "white".
+func ParseIconVariant(s string) (IconVariant, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	var names []string
+	best, bestDist := "", -1
+	for _, v := range AllVariants() {
+		if name == string(v) {
+			return v, nil
+		}
+		names = append(names, string(v))
+		if d := editDistance(name, string(v)); bestDist < 0 || d < bestDist {
+			best, bestDist = string(v), d
+		}
+	}
+	if bestDist <= 2 {
+		return "", fmt.Errorf("unknown icon variant %q (did you mean %q?)", s, best)
+	}
+	return "", fmt.Errorf("unknown icon variant %q (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+var (
+	overlayMu  sync.RWMutex
+	overlayDir string
+)
+
+// SetOverlayDir sets a directory whose icons take precedence over the
+// embedded set, e.g. to iterate on new icons without rebuilding. GetIcon
+// first looks for dir/brands/{brand}/icon_{variant}.svg and falls back to
+// the embedded icon if that file does not exist. An empty dir disables the
+// overlay.
+func SetOverlayDir(dir string) {
+	overlayMu.Lock()
+	defer overlayMu.Unlock()
+	overlayDir = dir
+}
+
 // GetIcon retrieves an icon by brand name and variant.
-// Returns the SVG content as bytes.
+// Returns the SVG content as bytes. The brand must be a single path
+// segment, so it cannot reach outside the overlay directory.
 func GetIcon(brand string, variant IconVariant) ([]byte, error) {
+	if !fs.ValidPath(brand) || brand == "." || strings.ContainsAny(brand, `/\`) {
+		return nil, fmt.Errorf("invalid brand name %q", brand)
+	}
 	filename := fmt.Sprintf("icon_%s.svg", variant)
-	filepath := path.Join("brands", brand, filename)
-	return brandsFS.ReadFile(filepath)
+
+	overlayMu.RLock()
+	dir := overlayDir
+	overlayMu.RUnlock()
+	if dir != "" {
+		content, err := os.ReadFile(filepath.Join(dir, "brands", brand, filename))
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return content, err
+		}
+	}
+
+	return brandsFS.ReadFile(path.Join("brands", brand, filename))
+}
+
+// GetIconByName retrieves an icon by brand name and variant name, e.g. from
+// a config file. The variant is validated with ParseIconVariant.
+func GetIconByName(brand, variant string) ([]byte, error) {
+	v, err := ParseIconVariant(variant)
+	if err != nil {
+		return nil, err
+	}
+	return GetIcon(brand, v)
 }
 
 // GetIconWhite retrieves the white variant icon for dark backgrounds.
@@ -59,6 +154,16 @@ func GetIconOrig(brand string) ([]byte, error) {
 	return GetIcon(brand, IconVariantOrig)
 }
 
+// GetIconDataURI retrieves an icon as a base64 data URI, minified to a
+// single line, e.g. for use in CSS or an <img> src attribute.
+func GetIconDataURI(brand string, variant IconVariant) (string, error) {
+	content, err := GetIcon(brand, variant)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg.MinifyForDataURI(content)), nil
+}
+
 // ListIcons returns all available brand names.
 func ListIcons() ([]string, error) {
 	entries, err := fs.ReadDir(brandsFS, "brands")
@@ -1,6 +1,10 @@
 package brandkit
 
 import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -38,3 +42,105 @@ func TestIconExists(t *testing.T) {
 		t.Error("IconExists(nonexistent-brand) should be false")
 	}
 }
+
+func TestGetIconDataURI(t *testing.T) {
+	uri, err := GetIconDataURI("aws", IconVariantWhite)
+	if err != nil {
+		t.Fatalf("GetIconDataURI error: %v", err)
+	}
+	const prefix = "data:image/svg+xml;base64,"
+	if !strings.HasPrefix(uri, prefix) {
+		t.Fatalf("GetIconDataURI = %.40q..., want %q prefix", uri, prefix)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, prefix))
+	if err != nil {
+		t.Fatalf("invalid base64: %v", err)
+	}
+	if strings.ContainsAny(string(decoded), "\r\n") {
+		t.Error("data URI content is not a single line")
+	}
+
+	if _, err := GetIconDataURI("nonexistent-brand", IconVariantWhite); err == nil {
+		t.Error("expected error for nonexistent brand")
+	}
+}
+
+func TestGetIconByName(t *testing.T) {
+	content, err := GetIconByName("aws", "White")
+	if err != nil {
+		t.Fatalf("GetIconByName() error: %v", err)
+	}
+	want, _ := GetIconWhite("aws")
+	if string(content) != string(want) {
+		t.Error("GetIconByName() returned a different icon than GetIconWhite()")
+	}
+
+	_, err = GetIconByName("aws", "whte")
+	if err == nil || !strings.Contains(err.Error(), `did you mean "white"`) {
+		t.Errorf("GetIconByName(whte) error = %v, want suggestion for white", err)
+	}
+
+	_, err = GetIconByName("aws", "monochrome")
+	if err == nil || !strings.Contains(err.Error(), "valid: white, color, orig") {
+		t.Errorf("GetIconByName(monochrome) error = %v, want list of variants", err)
+	}
+}
+
+func TestSetOverlayDir(t *testing.T) {
+	dir := t.TempDir()
+	overlay := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><rect width="10" height="10"/></svg>`)
+	if err := os.MkdirAll(filepath.Join(dir, "brands", "aws"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "brands", "aws", "icon_white.svg"), overlay, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	SetOverlayDir(dir)
+	t.Cleanup(func() { SetOverlayDir("") })
+
+	got, err := GetIconWhite("aws")
+	if err != nil {
+		t.Fatalf("GetIconWhite(aws) error: %v", err)
+	}
+	if string(got) != string(overlay) {
+		t.Errorf("GetIconWhite(aws) = %q, want overlay file", got)
+	}
+
+	// Variants missing from the overlay fall back to the embedded icon
+	embedded, err := brandsFS.ReadFile("brands/aws/icon_color.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = GetIconColor("aws")
+	if err != nil {
+		t.Fatalf("GetIconColor(aws) error: %v", err)
+	}
+	if string(got) != string(embedded) {
+		t.Error("GetIconColor(aws) should fall back to the embedded icon")
+	}
+
+	SetOverlayDir("")
+	got, err = GetIconWhite("aws")
+	if err != nil {
+		t.Fatalf("GetIconWhite(aws) error: %v", err)
+	}
+	if string(got) == string(overlay) {
+		t.Error("GetIconWhite(aws) should use the embedded icon after clearing the overlay")
+	}
+}
+
+func TestGetIconInvalidBrand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "icon_white.svg"), []byte(`<svg/>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	SetOverlayDir(filepath.Join(dir, "overlay"))
+	t.Cleanup(func() { SetOverlayDir("") })
+
+	for _, brand := range []string{"", ".", "..", "../..", "aws/../..", `..\..`, "/etc"} {
+		if _, err := GetIconWhite(brand); err == nil {
+			t.Errorf("GetIconWhite(%q) expected error", brand)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package brandkit
+
+import (
+	"fmt"
+
+	"github.com/grokify/brandkit/svg/analyze"
+	"github.com/grokify/brandkit/svg/security"
+	"github.com/grokify/brandkit/svg/verify"
+)
+
+// Inspection combines the centering analysis, purity verification, and
+// security scan of an icon.
+type Inspection struct {
+	Analysis *analyze.Result  // Centering and padding
+	Verify   *verify.Result   // Pure vector verification
+	Security *security.Result // Strict security scan
+}
+
+// IsSuccess returns true if the icon is centered, pure vector, and secure.
+func (i *Inspection) IsSuccess() bool {
+	return !i.Analysis.HasIssues && i.Verify.IsSuccess() && i.Security.IsSuccess()
+}
+
+// Inspect analyzes, verifies, and security scans SVG content in memory.
+// It returns an error if the content cannot be analyzed, e.g. because it
+// has no viewBox or no parseable geometry.
+func Inspect(content []byte) (*Inspection, error) {
+	analysis, err := analyze.Content(content)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+	return &Inspection{
+		Analysis: analysis,
+		Verify:   verify.Content(content, verify.Options{}),
+		Security: security.ScanContent(string(content), nil),
+	}, nil
+}
@@ -0,0 +1,36 @@
+package brandkit
+
+import (
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	content, err := GetIconWhite("aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inspection, err := Inspect(content)
+	if err != nil {
+		t.Fatalf("Inspect() error: %v", err)
+	}
+	if inspection.Analysis == nil || !inspection.Analysis.ContentBox.IsValid() {
+		t.Errorf("Analysis not populated: %+v", inspection.Analysis)
+	}
+	if inspection.Verify == nil || !inspection.Verify.IsPureVector || len(inspection.Verify.VectorElements) == 0 {
+		t.Errorf("Verify not populated: %+v", inspection.Verify)
+	}
+	if inspection.Security == nil || !inspection.Security.IsSecure {
+		t.Errorf("Security not populated: %+v", inspection.Security)
+	}
+}
+
+func TestInspectInsecure(t *testing.T) {
+	inspection, err := Inspect([]byte(`<svg viewBox="0 0 100 100"><script>alert(1)</script><rect x="5" y="5" width="90" height="90"/></svg>`))
+	if err != nil {
+		t.Fatalf("Inspect() error: %v", err)
+	}
+	if inspection.Security.IsSecure || inspection.IsSuccess() {
+		t.Error("expected insecure inspection")
+	}
+}
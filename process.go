@@ -2,10 +2,14 @@
 package brandkit
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"regexp"
+	"path/filepath"
+	"strings"
 
+	"github.com/grokify/brandkit/svg"
 	"github.com/grokify/brandkit/svg/analyze"
 	"github.com/grokify/brandkit/svg/convert"
 	"github.com/grokify/brandkit/svg/security"
@@ -16,7 +20,7 @@ import (
 // ProcessResult contains the result of a processing operation.
 type ProcessResult struct {
 	InputPath         string
-	OutputPath        string
+	OutputPath        string // Path written, which differs from the requested path with HashName
 	BackgroundRemoved bool
 	ColorConverted    bool
 	TargetColor       string
@@ -26,6 +30,45 @@ type ProcessResult struct {
 	VectorElements    []string
 	SecurityScanned   bool
 	SecurityThreats   []security.Threat
+	Checksum          string   // SHA-256 checksum embedded in the output, if requested
+	Blank             bool     // True if the output has no visible content
+	Warnings          []string // Non-fatal issues found during processing
+	InputSize         int64    // Size of the input file in bytes
+	OutputSize        int64    // Size of the written output file in bytes
+	BytesSaved        int64    // InputSize minus OutputSize; negative if the output grew
+	Error             string   // Error message if processing failed
+	ErrorStage        string   // Stage that failed, one of the Stage constants
+}
+
+// Processing stages reported in ProcessError.
+const (
+	StageConvert  = "convert"
+	StageAnalyze  = "analyze"
+	StageVerify   = "verify"
+	StageSecurity = "security"
+	StageWrite    = "write"
+)
+
+// ProcessError is returned when a processing stage fails.
+// Stage identifies which step of the pipeline failed.
+type ProcessError struct {
+	Stage string
+	Err   error
+}
+
+// Error returns the underlying error message.
+func (e *ProcessError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ProcessError) Unwrap() error {
+	return e.Err
+}
+
+// stageError wraps an error with the stage that produced it.
+func stageError(stage string, err error) *ProcessError {
+	return &ProcessError{Stage: stage, Err: err}
 }
 
 // ProcessWhite creates a white icon on transparent background.
@@ -35,13 +78,23 @@ type ProcessResult struct {
 //
 // Equivalent to CLI: brandkit white <input> -o <output>
 func ProcessWhite(inputPath, outputPath string) (*ProcessResult, error) {
+	return ProcessWhiteWithOptions(inputPath, outputPath, DefaultProcessOptions())
+}
+
+// ProcessWhiteWithOptions is ProcessWhite with configurable security scanning.
+func ProcessWhiteWithOptions(inputPath, outputPath string, opts ProcessOptions) (*ProcessResult, error) {
 	return process(inputPath, outputPath, processOptions{
 		color:            "ffffff",
 		removeBackground: true,
 		includeStroke:    true,
 		center:           true,
 		strict:           true,
-		securityScan:     true,
+		securityScan:     !opts.SkipSecurity,
+		securityLevel:    opts.SecurityLevel,
+		embedChecksum:    opts.EmbedChecksum,
+		hashName:         opts.HashName,
+		flattenGradients: opts.FlattenGradients,
+		logger:           svg.LoggerOrDiscard(opts.Logger),
 	})
 }
 
@@ -52,16 +105,45 @@ func ProcessWhite(inputPath, outputPath string) (*ProcessResult, error) {
 //
 // Equivalent to CLI: brandkit color <input> -o <output>
 func ProcessColor(inputPath, outputPath string) (*ProcessResult, error) {
+	return ProcessColorWithOptions(inputPath, outputPath, DefaultProcessOptions())
+}
+
+// ProcessColorWithOptions is ProcessColor with configurable security scanning.
+func ProcessColorWithOptions(inputPath, outputPath string, opts ProcessOptions) (*ProcessResult, error) {
 	return process(inputPath, outputPath, processOptions{
 		color:            "", // No color conversion - keep originals
 		removeBackground: true,
 		includeStroke:    false, // Irrelevant since color is empty (no conversion happens)
 		center:           true,
 		strict:           true,
-		securityScan:     true,
+		securityScan:     !opts.SkipSecurity,
+		securityLevel:    opts.SecurityLevel,
+		embedChecksum:    opts.EmbedChecksum,
+		hashName:         opts.HashName,
+		flattenGradients: opts.FlattenGradients,
+		logger:           svg.LoggerOrDiscard(opts.Logger),
 	})
 }
 
+// ProcessOptions configures optional steps of the ProcessWhite and
+// ProcessColor presets.
+type ProcessOptions struct {
+	SkipSecurity     bool               // Skip the security scan entirely
+	SecurityLevel    security.ScanLevel // Scan level used when scanning
+	EmbedChecksum    bool               // Append a brandkit:sha256 checksum comment to the output
+	HashName         bool               // Rename the output to {sha256}.svg in its directory; see ProcessResult.OutputPath
+	FlattenGradients bool               // Replace gradient/pattern fills with the target color (ProcessWhite only); gradients are kept by default
+	Logger           *slog.Logger       // Receives a structured event per step; nil discards them
+}
+
+// DefaultProcessOptions returns options that perform a strict security scan.
+func DefaultProcessOptions() ProcessOptions {
+	return ProcessOptions{
+		SkipSecurity:  false,
+		SecurityLevel: security.ScanLevelStrict,
+	}
+}
+
 type processOptions struct {
 	color            string
 	removeBackground bool
@@ -69,18 +151,57 @@ type processOptions struct {
 	center           bool
 	strict           bool
 	securityScan     bool
+	securityLevel    security.ScanLevel
+	embedChecksum    bool
+	hashName         bool
+	flattenGradients bool
+	logger           *slog.Logger
 }
 
+// process runs the pipeline and logs its overall outcome.
 func process(inputPath, outputPath string, opts processOptions) (*ProcessResult, error) {
+	log := opts.logger.With("file", inputPath)
+	result, err := runProcess(inputPath, outputPath, opts, log)
+	if err != nil {
+		var pe *ProcessError
+		stage := ""
+		if errors.As(err, &pe) {
+			stage = pe.Stage
+		}
+		log.Error("process failed", "action", stage, "outcome", "error", "error", err)
+		if result != nil {
+			result.Error = err.Error()
+			result.ErrorStage = stage
+		}
+		return result, err
+	}
+	log.Info("processed", "action", "process", "outcome", "ok", "output", result.OutputPath)
+	return result, nil
+}
+
+func runProcess(inputPath, outputPath string, opts processOptions, log *slog.Logger) (*ProcessResult, error) {
 	result := &ProcessResult{
 		InputPath:  inputPath,
 		OutputPath: outputPath,
 	}
 
+	// Record the input size before an in-place run overwrites it
+	if info, err := os.Stat(inputPath); err == nil {
+		result.InputSize = info.Size()
+	}
+
+	// svgz output is processed as plain SVG and compressed once at the end
+	compress := strings.EqualFold(filepath.Ext(outputPath), ".svgz")
+	workPath := outputPath
+	if compress {
+		workPath = outputPath + ".tmp.svg"
+		defer func() { _ = os.Remove(workPath) }()
+	}
+
 	// Step 1: Convert colors (to a temp file if we need to modify viewBox)
-	tempOutput := outputPath
+	tempOutput := workPath
 	if opts.center {
-		tempOutput = outputPath + ".tmp"
+		tempOutput = workPath + ".tmp"
 	}
 
 	convertOpts := convert.Options{
@@ -88,11 +209,13 @@ func process(inputPath, outputPath string, opts processOptions) (*ProcessResult,
 		IncludeStroke:    opts.includeStroke,
 		PreserveMasks:    true,
 		RemoveBackground: opts.removeBackground,
+		FlattenGradients: opts.flattenGradients,
+		Logger:           opts.logger,
 	}
 
 	convertResult, err := convert.SVG(inputPath, tempOutput, convertOpts)
 	if err != nil {
-		return result, fmt.Errorf("conversion failed: %w", err)
+		return result, stageError(StageConvert, fmt.Errorf("conversion failed: %w", err))
 	}
 
 	result.BackgroundRemoved = convertResult.BackgroundRemoved
@@ -100,83 +223,151 @@ func process(inputPath, outputPath string, opts processOptions) (*ProcessResult,
 		result.ColorConverted = true
 		result.TargetColor = convertResult.TargetColor
 	}
+	log.Info("step complete", "action", StageConvert, "outcome", "ok",
+		"color_converted", result.ColorConverted, "background_removed", result.BackgroundRemoved)
+
+	// Flag destructive runs (e.g. only a background element); there is
+	// nothing to analyze or center, but the remaining steps still run
+	if converted, err := svg.ReadFile(tempOutput); err == nil && svg.IsBlank(converted) {
+		result.Blank = true
+		result.Warnings = append(result.Warnings, "output has no visible content")
+		log.Warn("step complete", "action", StageAnalyze, "outcome", "blank")
+	}
 
 	// Step 2: Analyze (and optionally fix centering)
-	analysisResult, err := analyze.SVG(tempOutput)
-	if err != nil {
-		if opts.center {
-			_ = os.Remove(tempOutput)
+	var analysisResult *analyze.Result
+	if !result.Blank {
+		analysisResult, err = analyze.SVG(tempOutput)
+		if err != nil {
+			if opts.center {
+				_ = os.Remove(tempOutput)
+			}
+			return result, stageError(StageAnalyze, fmt.Errorf("analysis failed: %w", err))
 		}
-		return result, fmt.Errorf("analysis failed: %w", err)
 	}
 
-	if opts.center && analysisResult.HasIssues {
+	if opts.center && analysisResult != nil && analysisResult.HasIssues {
 		// Apply the suggested viewBox fix
-		content, err := os.ReadFile(tempOutput)
+		content, err := svg.ReadFile(tempOutput)
 		if err != nil {
 			_ = os.Remove(tempOutput)
-			return result, fmt.Errorf("failed to read for centering: %w", err)
+			return result, stageError(StageAnalyze, fmt.Errorf("failed to read for centering: %w", err))
 		}
 
-		contentStr := string(content)
-
-		// Replace viewBox with suggested value
-		viewBoxRe := regexp.MustCompile(`viewBox\s*=\s*["'][^"']*["']`)
-		newViewBox := fmt.Sprintf(`viewBox="%s"`, analysisResult.SuggestedViewBox)
-
-		if viewBoxRe.MatchString(contentStr) {
-			contentStr = viewBoxRe.ReplaceAllString(contentStr, newViewBox)
+		contentStr, err := svg.SetViewBox(string(content), analysisResult.SuggestedViewBoxParsed)
+		if err != nil {
+			_ = os.Remove(tempOutput)
+			return result, stageError(StageAnalyze, fmt.Errorf("failed to set viewBox: %w", err))
 		}
 
-		if err := osutil.WriteFileSecure(outputPath, []byte(contentStr), 0600); err != nil {
+		if err := osutil.WriteFileSecure(workPath, []byte(contentStr), 0600); err != nil {
 			_ = os.Remove(tempOutput)
-			return result, fmt.Errorf("failed to write centered file: %w", err)
+			return result, stageError(StageWrite, fmt.Errorf("failed to write centered file: %w", err))
 		}
 
-		if tempOutput != outputPath {
+		if tempOutput != workPath {
 			_ = os.Remove(tempOutput)
 		}
 
 		result.Centered = true
 		result.SuggestedViewBox = analysisResult.SuggestedViewBox
+		log.Info("step complete", "action", StageAnalyze, "outcome", "centered", "viewbox", result.SuggestedViewBox)
 	} else if opts.center {
 		// No issues, just rename temp to final
-		if tempOutput != outputPath {
-			if err := os.Rename(tempOutput, outputPath); err != nil {
-				return result, fmt.Errorf("failed to finalize output: %w", err)
+		if tempOutput != workPath {
+			if err := os.Rename(tempOutput, workPath); err != nil {
+				return result, stageError(StageWrite, fmt.Errorf("failed to finalize output: %w", err))
 			}
 		}
+		if !result.Blank {
+			log.Info("step complete", "action", StageAnalyze, "outcome", "already centered")
+		}
 	}
 
 	// Step 3: Verify (if strict mode)
 	if opts.strict {
-		verifyResult, err := verify.SVG(outputPath)
+		verifyResult, err := verify.SVG(workPath)
 		if err != nil {
-			return result, fmt.Errorf("verification failed: %w", err)
+			return result, stageError(StageVerify, fmt.Errorf("verification failed: %w", err))
 		}
 
 		if !verifyResult.IsSuccess() {
-			return result, fmt.Errorf("SVG contains embedded binary data: %v", verifyResult.Errors)
+			return result, stageError(StageVerify, fmt.Errorf("SVG contains embedded binary data: %v", verifyResult.Errors))
 		}
 
 		result.Verified = true
 		result.VectorElements = verifyResult.VectorElements
+		log.Info("step complete", "action", StageVerify, "outcome", "ok")
 	}
 
 	// Step 4: Security scan (if enabled)
 	if opts.securityScan {
-		secResult, err := security.SVG(outputPath)
+		secResult, err := security.SVGWithLevel(workPath, opts.securityLevel)
 		if err != nil {
-			return result, fmt.Errorf("security scan failed: %w", err)
+			return result, stageError(StageSecurity, fmt.Errorf("security scan failed: %w", err))
 		}
 
 		result.SecurityScanned = true
 		result.SecurityThreats = secResult.Threats
 
 		if !secResult.IsSuccess() {
-			return result, fmt.Errorf("SVG contains security threats: %d threats detected", len(secResult.Threats))
+			return result, stageError(StageSecurity, fmt.Errorf("SVG contains security threats: %d threats detected", len(secResult.Threats)))
+		}
+		log.Info("step complete", "action", StageSecurity, "outcome", "ok", "threats", len(secResult.Threats))
+	}
+
+	// Step 5: Embed checksum (if enabled)
+	if opts.embedChecksum {
+		content, err := svg.ReadFile(workPath)
+		if err != nil {
+			return result, stageError(StageWrite, fmt.Errorf("failed to read for checksum: %w", err))
+		}
+		content = svg.DetectLineEndings(content).Apply(AddChecksum(content))
+		if err := osutil.WriteFileSecure(workPath, content, 0600); err != nil {
+			return result, stageError(StageWrite, fmt.Errorf("failed to write checksum: %w", err))
 		}
+		result.Checksum = Checksum(content)
+		log.Info("step complete", "action", "checksum", "outcome", "ok", "checksum", result.Checksum)
 	}
 
+	// Compress svgz output now that its content is final
+	if compress {
+		content, err := svg.ReadFile(workPath)
+		if err != nil {
+			return result, stageError(StageWrite, fmt.Errorf("failed to read for compression: %w", err))
+		}
+		if err := svg.WriteSVG(outputPath, content, svg.DefaultWriteOptions()); err != nil {
+			return result, stageError(StageWrite, fmt.Errorf("failed to write svgz output: %w", err))
+		}
+	}
+
+	// Step 6: Name by content hash (if enabled)
+	if opts.hashName {
+		content, err := os.ReadFile(outputPath)
+		if err != nil {
+			return result, stageError(StageWrite, fmt.Errorf("failed to read for hashing: %w", err))
+		}
+		hashed := svg.HashedPath(outputPath, content)
+		if err := os.Rename(outputPath, hashed); err != nil {
+			return result, stageError(StageWrite, fmt.Errorf("failed to rename output: %w", err))
+		}
+		result.OutputPath = hashed
+		log.Info("step complete", "action", "hash_name", "outcome", "ok", "output", hashed)
+	}
+
+	result.recordOutputSize()
+
 	return result, nil
 }
+
+// recordOutputSize sets the output size from the file on disk. The output
+// is already written, so a failed stat is a warning rather than an error.
+func (r *ProcessResult) recordOutputSize() {
+	out, err := os.Stat(r.OutputPath)
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("failed to stat output: %v", err))
+		return
+	}
+	r.OutputSize = out.Size()
+	r.BytesSaved = r.InputSize - r.OutputSize
+}
@@ -0,0 +1,300 @@
+package brandkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/brandkit/svg"
+	"github.com/grokify/brandkit/svg/security"
+)
+
+func TestProcessErrorStageVerify(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="#000000"/>
+  <image href="data:image/png;base64,iVBORw0KGgo=" width="10" height="10"/>
+</svg>`
+
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ProcessColor(input, output)
+	if err == nil {
+		t.Fatal("expected error for embedded binary data")
+	}
+
+	var procErr *ProcessError
+	if !errors.As(err, &procErr) {
+		t.Fatalf("expected *ProcessError, got %T", err)
+	}
+	if procErr.Stage != StageVerify {
+		t.Errorf("Stage = %q, want %q", procErr.Stage, StageVerify)
+	}
+}
+
+func TestProcessErrorStageConvert(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ProcessWhite(filepath.Join(dir, "missing.svg"), filepath.Join(dir, "output.svg"))
+
+	var procErr *ProcessError
+	if !errors.As(err, &procErr) {
+		t.Fatalf("expected *ProcessError, got %T", err)
+	}
+	if procErr.Stage != StageConvert {
+		t.Errorf("Stage = %q, want %q", procErr.Stage, StageConvert)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected wrapped error to unwrap to os.ErrNotExist")
+	}
+}
+
+func TestProcessSizes(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="#ff0000"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessWhite(input, output)
+	if err != nil {
+		t.Fatalf("ProcessWhite error: %v", err)
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.InputSize != int64(len(content)) || result.OutputSize != info.Size() {
+		t.Errorf("sizes = %d -> %d, want %d -> %d", result.InputSize, result.OutputSize, len(content), info.Size())
+	}
+	if result.BytesSaved != result.InputSize-result.OutputSize {
+		t.Errorf("BytesSaved = %d, want %d", result.BytesSaved, result.InputSize-result.OutputSize)
+	}
+}
+
+func TestProcessSizesInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icon.svg")
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="red"/>
+</svg>`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessWhite(path, path)
+	if err != nil {
+		t.Fatalf("ProcessWhite error: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.InputSize != int64(len(content)) || result.OutputSize != int64(len(written)) {
+		t.Errorf("sizes = %d -> %d, want %d -> %d", result.InputSize, result.OutputSize, len(content), len(written))
+	}
+	if want := int64(len(content) - len(written)); result.BytesSaved != want {
+		t.Errorf("BytesSaved = %d, want %d", result.BytesSaved, want)
+	}
+}
+
+func TestProcessSVGZ(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svgz")
+
+	// Off-center content so the centering step rewrites the output
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 60 60 L 90 60 L 90 90 L 60 90 Z" fill="#ff0000"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultProcessOptions()
+	opts.EmbedChecksum = true
+	result, err := ProcessWhiteWithOptions(input, output, opts)
+	if err != nil {
+		t.Fatalf("ProcessWhite error: %v", err)
+	}
+	if !result.Centered || !result.Verified || !result.SecurityScanned {
+		t.Errorf("result = %+v, want centered, verified and scanned", result)
+	}
+
+	raw, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		t.Fatalf("output is not gzip-compressed: %q", raw[:min(len(raw), 20)])
+	}
+	decoded, err := svg.ReadFile(output)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if !bytes.Contains(decoded, []byte("#ffffff")) || !bytes.Contains(decoded, []byte("brandkit:sha256")) {
+		t.Errorf("decoded output missing color or checksum:\n%s", decoded)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected only input and output in %s, got %d entries", dir, len(entries))
+	}
+}
+
+func TestProcessWhiteFlattenGradients(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <defs><linearGradient id="g"><stop offset="0" stop-color="#f00"/><stop offset="1" stop-color="#00f"/></linearGradient></defs>
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="url(#g)"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Gradients are kept unless FlattenGradients is set
+	for _, flatten := range []bool{false, true} {
+		output := filepath.Join(dir, fmt.Sprintf("output_%t.svg", flatten))
+		opts := DefaultProcessOptions()
+		opts.FlattenGradients = flatten
+		if _, err := ProcessWhiteWithOptions(input, output, opts); err != nil {
+			t.Fatalf("ProcessWhite(flatten=%t) error: %v", flatten, err)
+		}
+		got, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kept := bytes.Contains(got, []byte("url(#g)")); kept == flatten {
+			t.Errorf("flatten=%t: gradient kept = %t\n%s", flatten, kept, got)
+		}
+	}
+}
+
+func TestProcessColorSkipSecurity(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <style>.a { fill: #ff0000; }</style>
+  <path class="a" d="M 10 10 L 90 10 L 90 90 L 10 90 Z"/>
+</svg>`
+
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Strict scanning fails on the style block
+	_, err := ProcessColor(input, output)
+	var procErr *ProcessError
+	if !errors.As(err, &procErr) || procErr.Stage != StageSecurity {
+		t.Fatalf("expected security stage error, got %v", err)
+	}
+
+	result, err := ProcessColorWithOptions(input, output, ProcessOptions{SkipSecurity: true})
+	if err != nil {
+		t.Fatalf("ProcessColorWithOptions error: %v", err)
+	}
+	if result.SecurityScanned {
+		t.Error("expected SecurityScanned = false")
+	}
+
+	// Standard level ignores low severity style blocks
+	result, err = ProcessColorWithOptions(input, output, ProcessOptions{SecurityLevel: security.ScanLevelStandard})
+	if err != nil {
+		t.Fatalf("ProcessColorWithOptions standard error: %v", err)
+	}
+	if !result.SecurityScanned {
+		t.Error("expected SecurityScanned = true")
+	}
+}
+
+func TestProcessBlankAfterBackgroundRemoval(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <rect x="0" y="0" width="100" height="100" fill="#ff0000"/>
+</svg>`
+
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessWhite(input, output)
+	if err != nil {
+		t.Fatalf("ProcessWhite error: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected blank output to be written: %v", err)
+	}
+	if !result.BackgroundRemoved {
+		t.Error("expected BackgroundRemoved = true")
+	}
+	if !result.Blank {
+		t.Error("expected Blank = true")
+	}
+	if len(result.Warnings) == 0 {
+		t.Error("expected a blank output warning")
+	}
+}
+
+func TestProcessLogging(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="#ff0000"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	opts := DefaultProcessOptions()
+	opts.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	if _, err := ProcessWhiteWithOptions(input, output, opts); err != nil {
+		t.Fatalf("ProcessWhite error: %v", err)
+	}
+
+	actions := map[string]bool{}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec["file"] != input {
+			t.Errorf("record %v has file %v, want %s", rec["msg"], rec["file"], input)
+		}
+		if action, ok := rec["action"].(string); ok {
+			actions[action] = true
+		}
+	}
+	for _, want := range []string{StageConvert, StageAnalyze, StageVerify, StageSecurity, "process"} {
+		if !actions[want] {
+			t.Errorf("no log record for action %q", want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package brandkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/grokify/mogo/os/osutil"
+)
+
+// SaveResults writes batch processing results to path as JSON, so a later
+// run can load them and reprocess only the files that failed.
+func SaveResults(path string, results []*ProcessResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	if err := osutil.WriteFileSecure(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// LoadResults reads results written by SaveResults.
+func LoadResults(path string) ([]*ProcessResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	var results []*ProcessResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode results: %w", err)
+	}
+	return results, nil
+}
+
+// FilterFailed returns the input paths of results that errored or had
+// security threats, in order, for reprocessing.
+func FilterFailed(results []*ProcessResult) []string {
+	var failed []string
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		if r.Error != "" || len(r.SecurityThreats) > 0 {
+			failed = append(failed, r.InputPath)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,58 @@
+package brandkit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/grokify/brandkit/svg/security"
+)
+
+func TestSaveLoadResultsFilterFailed(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.svg")
+	bad := filepath.Join(dir, "bad.svg")
+	if err := os.WriteFile(good, []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="#000000"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 10 L 90 90 L 10 90 Z"/><image href="data:image/png;base64,iVBORw0KGgo=" width="10" height="10"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []*ProcessResult
+	for _, input := range []string{good, bad} {
+		result, _ := ProcessColor(input, input+".out.svg")
+		results = append(results, result)
+	}
+	if results[1].Error == "" || results[1].ErrorStage != StageVerify {
+		t.Fatalf("expected verify failure recorded, got Error=%q ErrorStage=%q", results[1].Error, results[1].ErrorStage)
+	}
+	results = append(results, &ProcessResult{
+		InputPath:       filepath.Join(dir, "threat.svg"),
+		SecurityThreats: []security.Threat{{Type: security.ThreatScript, Description: "script element"}},
+	})
+
+	path := filepath.Join(dir, "results.json")
+	if err := SaveResults(path, results); err != nil {
+		t.Fatalf("SaveResults error: %v", err)
+	}
+	loaded, err := LoadResults(path)
+	if err != nil {
+		t.Fatalf("LoadResults error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, results) {
+		t.Errorf("loaded results differ:\n got %+v\nwant %+v", loaded, results)
+	}
+
+	want := []string{bad, filepath.Join(dir, "threat.svg")}
+	if got := FilterFailed(loaded); !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterFailed = %v, want %v", got, want)
+	}
+}
+
+func TestLoadResultsMissingFile(t *testing.T) {
+	if _, err := LoadResults(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing results file")
+	}
+}
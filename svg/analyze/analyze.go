@@ -2,41 +2,104 @@
 package analyze
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"strings"
 
-	"github.com/JoshVarga/svgparser"
-
 	"github.com/grokify/brandkit/svg"
 )
 
 // Result contains the analysis of an SVG file.
 type Result struct {
-	FilePath         string
-	ViewBox          svg.ViewBox
-	ContentBox       svg.BoundingBox
-	CenterOffsetX    float64
-	CenterOffsetY    float64
-	PaddingLeft      float64
-	PaddingRight     float64
-	PaddingTop       float64
-	PaddingBottom    float64
-	Assessment       string
-	SuggestedViewBox string
-	HasIssues        bool
+	FilePath               string          `json:"file_path"`
+	ViewBox                svg.ViewBox     `json:"view_box"`
+	ContentBox             svg.BoundingBox `json:"content_box"`
+	CenterOffsetX          float64         `json:"center_offset_x"`
+	CenterOffsetY          float64         `json:"center_offset_y"`
+	PaddingLeft            float64         `json:"padding_left"`
+	PaddingRight           float64         `json:"padding_right"`
+	PaddingTop             float64         `json:"padding_top"`
+	PaddingBottom          float64         `json:"padding_bottom"`
+	Assessment             string          `json:"assessment"`
+	SuggestedViewBox       string          `json:"suggested_view_box"`
+	SuggestedViewBoxParsed svg.ViewBox     `json:"suggested_view_box_parsed"` // Suggested viewBox at full precision
+	HasIssues              bool            `json:"has_issues"`
+	InvalidViewBox         bool            `json:"invalid_view_box"` // True if the viewBox had negative dimensions and was normalized
+	ContentClipped         bool            `json:"content_clipped"`  // True if content extends outside the viewBox
+	OverflowLeft           float64         `json:"overflow_left"`    // Percent of viewBox width clipped on the left
+	OverflowRight          float64         `json:"overflow_right"`   // Percent of viewBox width clipped on the right
+	OverflowTop            float64         `json:"overflow_top"`     // Percent of viewBox height clipped at the top
+	OverflowBottom         float64         `json:"overflow_bottom"`  // Percent of viewBox height clipped at the bottom
+}
+
+// Options configures the centering and padding thresholds. All values
+// are percentages of the viewBox dimension, and zero is a valid value,
+// e.g. a TargetPaddingPct of 0 for a tight viewBox. Start from
+// DefaultOptions and override fields as needed.
+type Options struct {
+	CenterThresholdPct float64 // Max content center offset before it is flagged as shifted (default 5)
+	MaxPaddingPct      float64 // Max padding on any side before it is flagged as excessive (default 20)
+	UnevenPaddingPct   float64 // Max difference between opposite paddings (default 10)
+	TargetPaddingPct   float64 // Padding on each side of the suggested viewBox (default 5)
+}
+
+// DefaultOptions returns the default thresholds.
+func DefaultOptions() Options {
+	return Options{
+		CenterThresholdPct: 5,
+		MaxPaddingPct:      20,
+		UnevenPaddingPct:   10,
+		TargetPaddingPct:   5,
+	}
 }
 
 // SVG analyzes an SVG file for centering and padding.
 func SVG(filePath string) (*Result, error) {
-	file, err := os.Open(filePath)
+	return SVGWithOptions(filePath, DefaultOptions())
+}
+
+// SVGWithOptions analyzes an SVG file using the given thresholds.
+func SVGWithOptions(filePath string, opts Options) (*Result, error) {
+	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
-	defer func() { _ = file.Close() }()
+	defer func() { _ = f.Close() }()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	result, err := ContentWithOptions(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.FilePath = filePath
+	return result, nil
+}
+
+// Reader analyzes SVG or svgz content read from r, e.g. an HTTP request
+// body, for centering and padding.
+func Reader(r io.Reader) (*Result, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	return Content(content)
+}
 
-	svgDoc, err := svgparser.Parse(file, false)
+// Content analyzes SVG content in memory for centering and padding.
+func Content(content []byte) (*Result, error) {
+	return ContentWithOptions(content, DefaultOptions())
+}
+
+// ContentWithOptions analyzes SVG content in memory using the given
+// thresholds.
+func ContentWithOptions(content []byte, opts Options) (*Result, error) {
+	svgDoc, err := svg.Parse(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SVG: %w", err)
 	}
@@ -59,147 +122,222 @@ func SVG(filePath string) (*Result, error) {
 		}
 	}
 
+	// Normalize malformed viewBoxes with negative dimensions
+	normalized := viewBox.Normalize()
+	invalidViewBox := normalized != viewBox
+	viewBox = normalized
+
 	// Calculate content bounds
-	contentBox := svg.NewBoundingBox()
-	for _, child := range svgDoc.Children {
-		// Skip defs, mask, clipPath
-		if child.Name == "defs" || child.Name == "mask" || child.Name == "clipPath" {
-			continue
-		}
-		childBox := svg.GetElementBounds(child)
-		contentBox.Merge(childBox)
-	}
+	contentBox := svg.ContentBounds(svgDoc)
 
 	if !contentBox.IsValid() {
 		return nil, fmt.Errorf("no parseable content found")
 	}
 
-	// Calculate center offsets
-	viewBoxCenterX := viewBox.CenterX()
-	viewBoxCenterY := viewBox.CenterY()
-	contentCenterX := contentBox.CenterX()
-	contentCenterY := contentBox.CenterY()
+	p := measurePlacement(viewBox, contentBox)
+
+	// Generate assessment
+	var issues []string
+	if invalidViewBox {
+		issues = append(issues, "invalid viewBox (negative dimensions)")
+	}
+	issues = append(issues, p.issues(opts)...)
+	hasIssues := len(issues) > 0
+
+	assessment := "OK"
+	if len(issues) > 0 {
+		assessment = strings.Join(issues, "; ")
+	}
 
-	centerOffsetX := contentCenterX - viewBoxCenterX
-	centerOffsetY := contentCenterY - viewBoxCenterY
+	// Suggest fixed viewBox (TargetPaddingPct on all sides)
+	suggested := suggestViewBox(contentBox, opts.TargetPaddingPct/100)
 
-	// Calculate padding percentages
-	paddingLeft := ((contentBox.MinX - viewBox.X) / viewBox.Width) * 100
-	paddingRight := ((viewBox.X + viewBox.Width - contentBox.MaxX) / viewBox.Width) * 100
-	paddingTop := ((contentBox.MinY - viewBox.Y) / viewBox.Height) * 100
-	paddingBottom := ((viewBox.Y + viewBox.Height - contentBox.MaxY) / viewBox.Height) * 100
+	return &Result{
+		ViewBox:                viewBox,
+		ContentBox:             *contentBox,
+		CenterOffsetX:          p.centerOffsetX,
+		CenterOffsetY:          p.centerOffsetY,
+		PaddingLeft:            p.paddingLeft,
+		PaddingRight:           p.paddingRight,
+		PaddingTop:             p.paddingTop,
+		PaddingBottom:          p.paddingBottom,
+		Assessment:             assessment,
+		SuggestedViewBox:       suggested.String(),
+		SuggestedViewBoxParsed: suggested,
+		HasIssues:              hasIssues,
+		InvalidViewBox:         invalidViewBox,
+		ContentClipped:         p.clipped(),
+		OverflowLeft:           p.overflowLeft(),
+		OverflowRight:          p.overflowRight(),
+		OverflowTop:            p.overflowTop(),
+		OverflowBottom:         p.overflowBottom(),
+	}, nil
+}
 
-	// Generate assessment
+// placement describes where content sits within a viewBox. Offsets are in
+// user units; padding is a percentage of the viewBox dimension and is
+// negative where content extends outside the viewBox.
+type placement struct {
+	viewBox       svg.ViewBox
+	centerOffsetX float64
+	centerOffsetY float64
+	paddingLeft   float64
+	paddingRight  float64
+	paddingTop    float64
+	paddingBottom float64
+}
+
+func measurePlacement(viewBox svg.ViewBox, contentBox *svg.BoundingBox) placement {
+	return placement{
+		viewBox:       viewBox,
+		centerOffsetX: contentBox.CenterX() - viewBox.CenterX(),
+		centerOffsetY: contentBox.CenterY() - viewBox.CenterY(),
+		paddingLeft:   ((contentBox.MinX - viewBox.X) / viewBox.Width) * 100,
+		paddingRight:  ((viewBox.X + viewBox.Width - contentBox.MaxX) / viewBox.Width) * 100,
+		paddingTop:    ((contentBox.MinY - viewBox.Y) / viewBox.Height) * 100,
+		paddingBottom: ((viewBox.Y + viewBox.Height - contentBox.MaxY) / viewBox.Height) * 100,
+	}
+}
+
+func (p placement) overflowLeft() float64   { return math.Max(0, -p.paddingLeft) }
+func (p placement) overflowRight() float64  { return math.Max(0, -p.paddingRight) }
+func (p placement) overflowTop() float64    { return math.Max(0, -p.paddingTop) }
+func (p placement) overflowBottom() float64 { return math.Max(0, -p.paddingBottom) }
+
+func (p placement) clipped() bool {
+	return p.overflowLeft() > 0 || p.overflowRight() > 0 || p.overflowTop() > 0 || p.overflowBottom() > 0
+}
+
+// issues checks the placement against the centering and padding
+// thresholds in opts and describes each one that is exceeded.
+func (p placement) issues(opts Options) []string {
 	var issues []string
-	hasIssues := false
 
-	// Check centering (threshold: 5% of viewBox dimension)
-	centerThresholdX := viewBox.Width * 0.05
-	centerThresholdY := viewBox.Height * 0.05
+	// Negative padding means content extends outside the viewBox and is clipped
+	if p.clipped() {
+		issues = append(issues, fmt.Sprintf("content clipped by viewBox (L:%.1f%% R:%.1f%% T:%.1f%% B:%.1f%%)",
+			p.overflowLeft(), p.overflowRight(), p.overflowTop(), p.overflowBottom()))
+	}
+
+	// Check centering (threshold: CenterThresholdPct of viewBox dimension)
+	centerThresholdX := p.viewBox.Width * opts.CenterThresholdPct / 100
+	centerThresholdY := p.viewBox.Height * opts.CenterThresholdPct / 100
 
-	if math.Abs(centerOffsetX) > centerThresholdX {
-		if centerOffsetX > 0 {
-			issues = append(issues, fmt.Sprintf("content shifted RIGHT by %.1f%%", (centerOffsetX/viewBox.Width)*100))
+	if math.Abs(p.centerOffsetX) > centerThresholdX {
+		if p.centerOffsetX > 0 {
+			issues = append(issues, fmt.Sprintf("content shifted RIGHT by %.1f%%", (p.centerOffsetX/p.viewBox.Width)*100))
 		} else {
-			issues = append(issues, fmt.Sprintf("content shifted LEFT by %.1f%%", (-centerOffsetX/viewBox.Width)*100))
+			issues = append(issues, fmt.Sprintf("content shifted LEFT by %.1f%%", (-p.centerOffsetX/p.viewBox.Width)*100))
 		}
-		hasIssues = true
 	}
 
-	if math.Abs(centerOffsetY) > centerThresholdY {
-		if centerOffsetY > 0 {
-			issues = append(issues, fmt.Sprintf("content shifted DOWN by %.1f%%", (centerOffsetY/viewBox.Height)*100))
+	if math.Abs(p.centerOffsetY) > centerThresholdY {
+		if p.centerOffsetY > 0 {
+			issues = append(issues, fmt.Sprintf("content shifted DOWN by %.1f%%", (p.centerOffsetY/p.viewBox.Height)*100))
 		} else {
-			issues = append(issues, fmt.Sprintf("content shifted UP by %.1f%%", (-centerOffsetY/viewBox.Height)*100))
+			issues = append(issues, fmt.Sprintf("content shifted UP by %.1f%%", (-p.centerOffsetY/p.viewBox.Height)*100))
 		}
-		hasIssues = true
 	}
 
-	// Check for excessive padding (more than 20%)
-	if paddingLeft > 20 || paddingRight > 20 || paddingTop > 20 || paddingBottom > 20 {
-		maxPadding := math.Max(math.Max(paddingLeft, paddingRight), math.Max(paddingTop, paddingBottom))
+	// Check for excessive padding (more than MaxPaddingPct)
+	maxPct := opts.MaxPaddingPct
+	if p.paddingLeft > maxPct || p.paddingRight > maxPct || p.paddingTop > maxPct || p.paddingBottom > maxPct {
+		maxPadding := math.Max(math.Max(p.paddingLeft, p.paddingRight), math.Max(p.paddingTop, p.paddingBottom))
 		issues = append(issues, fmt.Sprintf("excessive padding (max %.1f%%)", maxPadding))
-		hasIssues = true
 	}
 
-	// Check for uneven padding (difference > 10%)
-	hPaddingDiff := math.Abs(paddingLeft - paddingRight)
-	vPaddingDiff := math.Abs(paddingTop - paddingBottom)
-	if hPaddingDiff > 10 {
-		issues = append(issues, fmt.Sprintf("uneven horizontal padding (L:%.1f%% R:%.1f%%)", paddingLeft, paddingRight))
-		hasIssues = true
+	// Check for uneven padding (difference > UnevenPaddingPct)
+	if math.Abs(p.paddingLeft-p.paddingRight) > opts.UnevenPaddingPct {
+		issues = append(issues, fmt.Sprintf("uneven horizontal padding (L:%.1f%% R:%.1f%%)", p.paddingLeft, p.paddingRight))
 	}
-	if vPaddingDiff > 10 {
-		issues = append(issues, fmt.Sprintf("uneven vertical padding (T:%.1f%% B:%.1f%%)", paddingTop, paddingBottom))
-		hasIssues = true
+	if math.Abs(p.paddingTop-p.paddingBottom) > opts.UnevenPaddingPct {
+		issues = append(issues, fmt.Sprintf("uneven vertical padding (T:%.1f%% B:%.1f%%)", p.paddingTop, p.paddingBottom))
 	}
 
-	assessment := "OK"
-	if len(issues) > 0 {
-		assessment = strings.Join(issues, "; ")
-	}
-
-	// Suggest fixed viewBox (5% padding on all sides)
-	suggestedViewBox := SuggestViewBox(contentBox)
-
-	return &Result{
-		FilePath:         filePath,
-		ViewBox:          viewBox,
-		ContentBox:       *contentBox,
-		CenterOffsetX:    centerOffsetX,
-		CenterOffsetY:    centerOffsetY,
-		PaddingLeft:      paddingLeft,
-		PaddingRight:     paddingRight,
-		PaddingTop:       paddingTop,
-		PaddingBottom:    paddingBottom,
-		Assessment:       assessment,
-		SuggestedViewBox: suggestedViewBox,
-		HasIssues:        hasIssues,
-	}, nil
+	return issues
 }
 
 // SuggestViewBox suggests a viewBox with 5% padding that centers the content.
 func SuggestViewBox(contentBox *svg.BoundingBox) string {
-	targetPadding := 0.05 // 5%
-	contentWidth := contentBox.Width()
-	contentHeight := contentBox.Height()
-	newWidth := contentWidth / (1 - 2*targetPadding)
-	newHeight := contentHeight / (1 - 2*targetPadding)
+	vb := suggestViewBox(contentBox, DefaultOptions().TargetPaddingPct/100)
+	return vb.String()
+}
+
+// SuggestViewBoxWithOptions suggests a viewBox with opts.TargetPaddingPct
+// padding that centers the content.
+func SuggestViewBoxWithOptions(contentBox *svg.BoundingBox, opts Options) string {
+	vb := suggestViewBox(contentBox, opts.TargetPaddingPct/100)
+	return vb.String()
+}
+
+func suggestViewBox(contentBox *svg.BoundingBox, targetPadding float64) svg.ViewBox {
+	vb := suggestViewBoxSides(contentBox, targetPadding, targetPadding, targetPadding, targetPadding)
 
 	// Make it square if aspect ratio is close
-	aspectRatio := newWidth / newHeight
+	aspectRatio := vb.Width / vb.Height
 	if aspectRatio > 0.9 && aspectRatio < 1.1 {
-		size := math.Max(newWidth, newHeight)
-		newWidth = size
-		newHeight = size
+		size := math.Max(vb.Width, vb.Height)
+		vb.X = contentBox.CenterX() - size/2
+		vb.Y = contentBox.CenterY() - size/2
+		vb.Width = size
+		vb.Height = size
 	}
 
-	newX := contentBox.MinX - (newWidth-contentWidth)/2
-	newY := contentBox.MinY - (newHeight-contentHeight)/2
+	return vb
+}
 
-	return fmt.Sprintf("%.1f %.1f %.1f %.1f", newX, newY, newWidth, newHeight)
+// SuggestViewBoxSides suggests a viewBox with independent padding on each
+// side. Padding values are fractions of the resulting viewBox dimension,
+// e.g. 0.08 for 8%. If opposing sides sum to 1 or more, that axis is left
+// unpadded.
+func SuggestViewBoxSides(contentBox *svg.BoundingBox, top, right, bottom, left float64) string {
+	vb := suggestViewBoxSides(contentBox, top, right, bottom, left)
+	return vb.String()
 }
 
-// Directory analyzes all SVG files in a directory.
-func Directory(dirPath string) ([]*Result, error) {
-	files, err := svg.ListSVGFiles(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+func suggestViewBoxSides(contentBox *svg.BoundingBox, top, right, bottom, left float64) svg.ViewBox {
+	if left+right >= 1 {
+		left, right = 0, 0
+	}
+	if top+bottom >= 1 {
+		top, bottom = 0, 0
 	}
 
-	var results []*Result
-	for _, filePath := range files {
-		result, err := SVG(filePath)
-		if err != nil {
-			results = append(results, &Result{
-				FilePath:   filePath,
-				Assessment: fmt.Sprintf("Error: %v", err),
-				HasIssues:  true,
-			})
-			continue
-		}
-		results = append(results, result)
+	newWidth := contentBox.Width() / (1 - left - right)
+	newHeight := contentBox.Height() / (1 - top - bottom)
+
+	return svg.ViewBox{
+		X:      contentBox.MinX - left*newWidth,
+		Y:      contentBox.MinY - top*newHeight,
+		Width:  newWidth,
+		Height: newHeight,
+	}
+}
+
+// SafeArea reports whether content fits within the safe zone of a viewBox
+// inset by insetPct percent on every side, as required for app icons on
+// platforms such as iOS and Android adaptive icons. overflow is the
+// largest distance, as a percentage of the viewBox dimension, by which
+// content extends past the safe zone; it is 0 when the content fits. An
+// empty bounding box always fits.
+func SafeArea(box *svg.BoundingBox, vb svg.ViewBox, insetPct float64) (withinSafeArea bool, overflow float64) {
+	if !box.IsValid() {
+		return true, 0
 	}
+	p := measurePlacement(vb, box)
+	minPadding := math.Min(math.Min(p.paddingLeft, p.paddingRight), math.Min(p.paddingTop, p.paddingBottom))
+	overflow = math.Max(0, insetPct-minPadding)
+	return overflow == 0, overflow
+}
+
+// Directory analyzes all SVG files in a directory concurrently using
+// GOMAXPROCS workers. Results are sorted by FilePath.
+func Directory(dirPath string) ([]*Result, error) {
+	return DirectoryContext(context.Background(), dirPath)
+}
 
-	return results, nil
+// DirectoryContext is like Directory but stops and returns ctx.Err() once
+// ctx is cancelled, e.g. when a server request times out.
+func DirectoryContext(ctx context.Context, dirPath string) ([]*Result, error) {
+	return directoryContext(ctx, dirPath, DirectoryOptions{Options: DefaultOptions()})
 }
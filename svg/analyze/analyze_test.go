@@ -1,8 +1,15 @@
 package analyze
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/grokify/brandkit/svg"
@@ -40,6 +47,71 @@ func TestSVGCentered(t *testing.T) {
 	}
 }
 
+func TestSVGNegativeViewBox(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "negative.svg")
+
+	// Same region as "0 0 100 100", written with a negative width
+	content := `<svg viewBox="100 0 -100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 L 10 90 Z" fill="#000000"/>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+
+	if !result.InvalidViewBox {
+		t.Error("expected InvalidViewBox to be set")
+	}
+	want := svg.ViewBox{X: 0, Y: 0, Width: 100, Height: 100}
+	if result.ViewBox != want {
+		t.Errorf("ViewBox = %v, want %v", result.ViewBox, want)
+	}
+	if result.PaddingLeft < 9 || result.PaddingLeft > 11 {
+		t.Errorf("PaddingLeft = %.1f, want ~10", result.PaddingLeft)
+	}
+	if result.PaddingRight < 9 || result.PaddingRight > 11 {
+		t.Errorf("PaddingRight = %.1f, want ~10", result.PaddingRight)
+	}
+}
+
+func TestSVGContentClipped(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "clipped.svg")
+
+	// Content extends 20 units past the right edge
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <rect x="40" y="10" width="80" height="80"/>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+
+	if !result.ContentClipped {
+		t.Fatal("expected ContentClipped to be set")
+	}
+	if math.Abs(result.OverflowRight-20) > 0.01 {
+		t.Errorf("OverflowRight = %.2f, want 20", result.OverflowRight)
+	}
+	if result.OverflowLeft != 0 || result.OverflowTop != 0 || result.OverflowBottom != 0 {
+		t.Errorf("unexpected overflow L:%.1f T:%.1f B:%.1f", result.OverflowLeft, result.OverflowTop, result.OverflowBottom)
+	}
+	if !strings.Contains(result.Assessment, "content clipped") {
+		t.Errorf("Assessment = %q, want clipping issue", result.Assessment)
+	}
+}
+
 func TestSVGOffCenter(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "offcenter.svg")
@@ -97,6 +169,64 @@ func TestSVGExcessivePadding(t *testing.T) {
 	}
 }
 
+func TestSVGWithOptionsLooserThresholds(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "loose.svg")
+
+	// Shifted right by 10% with 30% padding on the left
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <rect x="30" y="20" width="60" height="60" fill="#000"/>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if !result.HasIssues {
+		t.Fatal("expected issues at default thresholds")
+	}
+
+	opts := DefaultOptions()
+	opts.CenterThresholdPct = 15
+	opts.MaxPaddingPct = 35
+	opts.UnevenPaddingPct = 25
+	result, err = SVGWithOptions(file, opts)
+	if err != nil {
+		t.Fatalf("SVGWithOptions error: %v", err)
+	}
+	if result.HasIssues {
+		t.Errorf("expected no issues at loosened thresholds, got: %s", result.Assessment)
+	}
+}
+
+func TestSuggestViewBoxWithOptions(t *testing.T) {
+	box := svg.NewBoundingBox()
+	box.Expand(0, 0)
+	box.Expand(80, 40)
+
+	vb, err := svg.ParseViewBox(SuggestViewBoxWithOptions(box, Options{TargetPaddingPct: 10}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 80 is 80% of the width with 10% padding on each side
+	if math.Abs(vb.Width-100) > 0.01 || math.Abs(vb.X+10) > 0.01 {
+		t.Errorf("suggested viewBox = %+v, want width 100 starting at -10", vb)
+	}
+
+	// Zero padding gives a tight viewBox rather than the default
+	vb, err = svg.ParseViewBox(SuggestViewBoxWithOptions(box, Options{TargetPaddingPct: 0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(vb.Width-80) > 0.01 || math.Abs(vb.X) > 0.01 {
+		t.Errorf("tight viewBox = %+v, want width 80 starting at 0", vb)
+	}
+}
+
 func TestSVGWithWidthHeight(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "wh.svg")
@@ -121,6 +251,39 @@ func TestSVGWithWidthHeight(t *testing.T) {
 	}
 }
 
+func TestSuggestedViewBoxParsed(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "offcenter.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 13.33 7.77 L 61.11 7.77 L 61.11 52.22 L 13.33 52.22 Z"/>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+
+	parsed, err := svg.ParseViewBox(result.SuggestedViewBox)
+	if err != nil {
+		t.Fatalf("ParseViewBox(%q) error: %v", result.SuggestedViewBox, err)
+	}
+
+	got := result.SuggestedViewBoxParsed
+	// The string is rounded to one decimal place
+	if math.Abs(got.X-parsed.X) > 0.05 || math.Abs(got.Y-parsed.Y) > 0.05 ||
+		math.Abs(got.Width-parsed.Width) > 0.05 || math.Abs(got.Height-parsed.Height) > 0.05 {
+		t.Errorf("SuggestedViewBoxParsed = %v, want values of %q", got, result.SuggestedViewBox)
+	}
+	if got.String() != result.SuggestedViewBox {
+		t.Errorf("SuggestedViewBoxParsed.String() = %q, want %q", got.String(), result.SuggestedViewBox)
+	}
+}
+
 func TestSVGNoViewBoxOrDimensions(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "nodims.svg")
@@ -244,3 +407,143 @@ func TestDirectoryWithBadFile(t *testing.T) {
 		t.Error("expected at least one result with issues")
 	}
 }
+
+func TestDirectoryWithOptionsOrdering(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 60; i++ {
+		content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="10" y="10" width="80" height="80"/></svg>`
+		if i%15 == 0 {
+			content = `not valid svg at all`
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("icon%02d.svg", i)), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var first []string
+	for run := 0; run < 3; run++ {
+		results, err := DirectoryWithOptions(dir, DirectoryOptions{Options: DefaultOptions(), Workers: 8})
+		if err != nil {
+			t.Fatalf("DirectoryWithOptions error: %v", err)
+		}
+		if len(results) != 60 {
+			t.Fatalf("got %d results, want 60", len(results))
+		}
+		var paths []string
+		failed := 0
+		for _, r := range results {
+			paths = append(paths, r.FilePath)
+			if strings.HasPrefix(r.Assessment, "Error:") {
+				failed++
+			}
+		}
+		if failed != 4 {
+			t.Errorf("got %d failed results, want 4", failed)
+		}
+		if !sort.StringsAreSorted(paths) {
+			t.Errorf("results not sorted by FilePath: %v", paths)
+		}
+		if run == 0 {
+			first = paths
+		} else if strings.Join(paths, ",") != strings.Join(first, ",") {
+			t.Errorf("run %d ordering differs from first run", run)
+		}
+	}
+}
+
+func TestDirectoryContextCancelled(t *testing.T) {
+	dir := t.TempDir()
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="10" y="10" width="80" height="80"/></svg>`
+	if err := os.WriteFile(filepath.Join(dir, "icon.svg"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DirectoryContext(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestSuggestViewBoxSides(t *testing.T) {
+	box := svg.NewBoundingBox()
+	box.Expand(0, 0)
+	box.Expand(152, 80)
+
+	suggested := SuggestViewBoxSides(box, 0.08, 0.12, 0.08, 0.12)
+	vb, err := svg.ParseViewBox(suggested)
+	if err != nil {
+		t.Fatalf("failed to parse suggested viewBox: %v", err)
+	}
+
+	padLeft := (box.MinX - vb.X) / vb.Width
+	padRight := (vb.X + vb.Width - box.MaxX) / vb.Width
+	padTop := (box.MinY - vb.Y) / vb.Height
+	padBottom := (vb.Y + vb.Height - box.MaxY) / vb.Height
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"left", padLeft, 0.12},
+		{"right", padRight, 0.12},
+		{"top", padTop, 0.08},
+		{"bottom", padBottom, 0.08},
+	}
+	for _, tt := range tests {
+		if diff := tt.got - tt.want; diff > 0.005 || diff < -0.005 {
+			t.Errorf("%s padding = %.3f, want %.3f", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSafeArea(t *testing.T) {
+	vb := svg.ViewBox{Width: 100, Height: 100}
+
+	fits := svg.NewBoundingBox()
+	fits.Expand(15, 12)
+	fits.Expand(85, 90)
+	within, overflow := SafeArea(fits, vb, 10)
+	if !within || overflow != 0 {
+		t.Errorf("SafeArea(fits) = %v, %.1f; want true, 0", within, overflow)
+	}
+
+	overflows := svg.NewBoundingBox()
+	overflows.Expand(4, 20)
+	overflows.Expand(80, 97)
+	within, overflow = SafeArea(overflows, vb, 10)
+	if within || math.Abs(overflow-7) > 1e-9 {
+		t.Errorf("SafeArea(overflows) = %v, %.1f; want false, 7.0", within, overflow)
+	}
+
+	if within, _ := SafeArea(svg.NewBoundingBox(), vb, 10); !within {
+		t.Error("SafeArea(empty) = false, want true")
+	}
+}
+
+func TestReader(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="40" y="40" width="60" height="60"/></svg>`)
+
+	result, err := Reader(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Reader error: %v", err)
+	}
+	if !result.HasIssues || result.FilePath != "" {
+		t.Errorf("HasIssues, FilePath = %v, %q, want true, empty", result.HasIssues, result.FilePath)
+	}
+}
+
+func TestSVGEmptyFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "empty.svg")
+	if err := os.WriteFile(file, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := SVG(file)
+	if !errors.Is(err, svg.ErrEmptySVG) {
+		t.Fatalf("SVG error = %v, want ErrEmptySVG", err)
+	}
+	if !strings.Contains(err.Error(), "empty SVG file") {
+		t.Errorf("error %q does not mention the empty file", err)
+	}
+}
@@ -0,0 +1,74 @@
+package analyze
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// FixOptions configures FixViewBox. Use DefaultFixOptions for the default
+// thresholds.
+type FixOptions struct {
+	Options          // Thresholds for the self-check and padding of the suggestion
+	ForceSquare bool // Always use a square viewBox, not only for near-square content
+}
+
+// DefaultFixOptions returns options with the default thresholds.
+func DefaultFixOptions() FixOptions {
+	return FixOptions{Options: DefaultOptions()}
+}
+
+// ErrSuggestionRejected is returned by FixViewBox when the suggested
+// viewBox does not itself pass the centering and padding checks.
+var ErrSuggestionRejected = errors.New("suggested viewBox fails centering check")
+
+// FixViewBox rewrites the root viewBox of content to the suggested viewBox
+// that centers the content with opts.TargetPaddingPct padding. Before
+// rewriting, the suggestion is measured with the same thresholds used by
+// ContentWithOptions; if it would still be reported as off-center,
+// clipped, or unevenly padded, an error wrapping ErrSuggestionRejected is
+// returned and content is not modified.
+func FixViewBox(content []byte, opts FixOptions) ([]byte, error) {
+	analysisOpts := opts.Options
+	result, err := ContentWithOptions(content, analysisOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	suggested := result.SuggestedViewBoxParsed
+	if opts.ForceSquare {
+		suggested = SquareViewBox(&result.ContentBox, analysisOpts.TargetPaddingPct)
+	}
+
+	if issues := measurePlacement(suggested, &result.ContentBox).issues(analysisOpts); len(issues) > 0 {
+		return nil, fmt.Errorf("%w: viewBox %s: %s", ErrSuggestionRejected, suggested.String(), strings.Join(issues, "; "))
+	}
+
+	fixed, err := svg.SetViewBox(string(content), suggested)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fixed), nil
+}
+
+// SquareViewBox returns the smallest square viewBox centered on the
+// content box with paddingPct percent padding on each side, e.g. 5 for 5%.
+// The side is the larger content dimension inflated by the padding, so the
+// shorter dimension gets extra space split evenly on both sides. Padding of
+// 50% or more is ignored.
+func SquareViewBox(box *svg.BoundingBox, paddingPct float64) svg.ViewBox {
+	pad := paddingPct / 100
+	if 2*pad >= 1 {
+		pad = 0
+	}
+	size := math.Max(box.Width(), box.Height()) / (1 - 2*pad)
+	return svg.ViewBox{
+		X:      box.CenterX() - size/2,
+		Y:      box.CenterY() - size/2,
+		Width:  size,
+		Height: size,
+	}
+}
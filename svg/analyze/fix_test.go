@@ -0,0 +1,92 @@
+package analyze
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+func TestFixViewBox(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="40" y="40" width="60" height="60"/></svg>`
+
+	fixed, err := FixViewBox([]byte(content), DefaultFixOptions())
+	if err != nil {
+		t.Fatalf("FixViewBox error: %v", err)
+	}
+
+	result, err := Content(fixed)
+	if err != nil {
+		t.Fatalf("Content error: %v", err)
+	}
+	if result.HasIssues {
+		t.Errorf("fixed content still has issues: %s", result.Assessment)
+	}
+	if !strings.Contains(string(fixed), `<rect x="40" y="40" width="60" height="60"/>`) {
+		t.Errorf("content changed outside the viewBox: %s", fixed)
+	}
+}
+
+func TestFixViewBoxForceSquareWideContent(t *testing.T) {
+	// A 10:1 banner forced into a square leaves ~45% padding above and below
+	content := `<svg viewBox="0 0 300 100" xmlns="http://www.w3.org/2000/svg"><rect x="0" y="40" width="200" height="20"/></svg>`
+
+	fixed, err := FixViewBox([]byte(content), FixOptions{Options: DefaultOptions(), ForceSquare: true})
+	if !errors.Is(err, ErrSuggestionRejected) {
+		t.Fatalf("err = %v, want ErrSuggestionRejected", err)
+	}
+	if fixed != nil {
+		t.Errorf("expected no content when the suggestion is rejected")
+	}
+	if !strings.Contains(err.Error(), "excessive padding") {
+		t.Errorf("error = %q, want it to describe the failed check", err)
+	}
+
+	// Without ForceSquare the suggestion passes
+	if _, err := FixViewBox([]byte(content), DefaultFixOptions()); err != nil {
+		t.Errorf("FixViewBox error without ForceSquare: %v", err)
+	}
+}
+
+func TestSquareViewBox(t *testing.T) {
+	box := svg.NewBoundingBox()
+	box.Expand(20, 30)
+	box.Expand(80, 70) // 60x40
+
+	vb := SquareViewBox(box, 10)
+
+	// 60 is 80% of the side with 10% padding on each side
+	if vb.Width != 75 || vb.Height != 75 {
+		t.Errorf("size = %gx%g, want 75x75", vb.Width, vb.Height)
+	}
+	if vb.CenterX() != box.CenterX() || vb.CenterY() != box.CenterY() {
+		t.Errorf("center = (%g, %g), want (%g, %g)", vb.CenterX(), vb.CenterY(), box.CenterX(), box.CenterY())
+	}
+	if vb.X != 12.5 || vb.Y != 12.5 {
+		t.Errorf("origin = (%g, %g), want (12.5, 12.5)", vb.X, vb.Y)
+	}
+}
+
+func TestFixViewBoxTranslatedGroup(t *testing.T) {
+	// The rect is drawn at the origin and moved into place by the group, so
+	// its rendered bounds are (50,50)-(90,90)
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><g transform="translate(50,50)"><rect width="40" height="40"/></g></svg>`
+
+	fixed, err := FixViewBox([]byte(content), DefaultFixOptions())
+	if err != nil {
+		t.Fatalf("FixViewBox error: %v", err)
+	}
+
+	result, err := Content(fixed)
+	if err != nil {
+		t.Fatalf("Content error: %v", err)
+	}
+	if result.HasIssues {
+		t.Errorf("fixed content still has issues: %s", result.Assessment)
+	}
+	if cx, cy := result.ViewBox.CenterX(), result.ViewBox.CenterY(); math.Abs(cx-70) > 1e-9 || math.Abs(cy-70) > 1e-9 {
+		t.Errorf("viewBox center = (%g, %g), want (70, 70)", cx, cy)
+	}
+}
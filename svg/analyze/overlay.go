@@ -0,0 +1,81 @@
+package analyze
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+var (
+	rootOpenTagRe = regexp.MustCompile(`(?s)<svg\b[^>]*>`)
+	nsDeclRe      = regexp.MustCompile(`\sxmlns:[\w.-]+\s*=\s*(?:"[^"]*"|'[^']*')`)
+)
+
+// OverlaySVG renders a debug SVG for reviewing auto-centering.
+// The output contains the original content plus semi-transparent
+// rectangles outlining the current viewBox (red) and the suggested
+// viewBox (green).
+func OverlaySVG(filePath string) ([]byte, error) {
+	result, err := SVG(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	suggested, err := svg.ParseViewBox(result.SuggestedViewBox)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse suggested viewBox: %w", err)
+	}
+
+	content, err := svg.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	inner, err := innerContent(string(content))
+	if err != nil {
+		return nil, err
+	}
+	// Prefixed content such as xlink:href needs the root's declarations
+	nsDecls := strings.Join(nsDeclRe.FindAllString(rootOpenTagRe.FindString(string(content)), -1), "")
+
+	current := result.ViewBox
+
+	// The overlay canvas must show both rectangles in full
+	minX := math.Min(current.X, suggested.X)
+	minY := math.Min(current.Y, suggested.Y)
+	maxX := math.Max(current.X+current.Width, suggested.X+suggested.Width)
+	maxY := math.Max(current.Y+current.Height, suggested.Y+suggested.Height)
+	canvas := svg.ViewBox{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+	strokeWidth := math.Max(canvas.Width, canvas.Height) * 0.005
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg"%s viewBox="%s">`+"\n", nsDecls, canvas.String())
+	fmt.Fprintf(&buf, "<g>%s</g>\n", inner)
+	fmt.Fprintf(&buf, `<rect class="overlay-current" x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#ff0000" fill-opacity="0.15" stroke="#ff0000" stroke-width="%.2f"/>`+"\n",
+		current.X, current.Y, current.Width, current.Height, strokeWidth)
+	fmt.Fprintf(&buf, `<rect class="overlay-suggested" x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#00ff00" fill-opacity="0.15" stroke="#00ff00" stroke-width="%.2f"/>`+"\n",
+		suggested.X, suggested.Y, suggested.Width, suggested.Height, strokeWidth)
+	buf.WriteString("</svg>\n")
+
+	return buf.Bytes(), nil
+}
+
+// innerContent returns the markup between the root <svg> open and close tags.
+func innerContent(content string) (string, error) {
+	loc := rootOpenTagRe.FindStringIndex(content)
+	if loc == nil {
+		return "", fmt.Errorf("missing <svg> element")
+	}
+	if strings.HasSuffix(content[loc[0]:loc[1]], "/>") {
+		return "", nil
+	}
+	end := strings.LastIndex(content, "</svg>")
+	if end < loc[1] {
+		return "", fmt.Errorf("missing </svg> closing tag")
+	}
+	return content[loc[1]:end], nil
+}
@@ -0,0 +1,83 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+func TestOverlaySVG(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "offcenter.svg")
+
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <rect x="40" y="40" width="60" height="60" fill="#000"/>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	overlay, err := OverlaySVG(file)
+	if err != nil {
+		t.Fatalf("OverlaySVG error: %v", err)
+	}
+	out := string(overlay)
+
+	if n := strings.Count(out, `class="overlay-`); n != 2 {
+		t.Errorf("got %d overlay rects, want 2", n)
+	}
+	if !strings.Contains(out, `<rect x="40" y="40" width="60" height="60" fill="#000"/>`) {
+		t.Error("overlay should contain the original content")
+	}
+	if !strings.Contains(out, `x="0.0" y="0.0" width="100.0" height="100.0"`) {
+		t.Error("overlay should contain the current viewBox rect")
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggested, err := svg.ParseViewBox(result.SuggestedViewBox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf(`x="%.1f" y="%.1f" width="%.1f" height="%.1f"`,
+		suggested.X, suggested.Y, suggested.Width, suggested.Height)
+	if !strings.Contains(out, want) {
+		t.Errorf("overlay should contain the suggested viewBox rect %s", want)
+	}
+
+	if _, err := svg.Parse(overlay); err != nil {
+		t.Errorf("overlay should parse: %v", err)
+	}
+}
+
+func TestOverlaySVGNamespaces(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "xlink.svg")
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+  <defs><rect id="r" x="0" y="0" width="10" height="10"/></defs>
+  <rect x="40" y="40" width="60" height="60"/>
+  <use xlink:href="#r"/>
+</svg>`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	overlay, err := OverlaySVG(file)
+	if err != nil {
+		t.Fatalf("OverlaySVG error: %v", err)
+	}
+	root, _, _ := strings.Cut(string(overlay), ">")
+	if !strings.Contains(root, `xmlns:xlink="http://www.w3.org/1999/xlink"`) {
+		t.Errorf("overlay root missing xlink declaration: %s>", root)
+	}
+	if !strings.Contains(string(overlay), `<use xlink:href="#r"/>`) {
+		t.Error("overlay should contain the original content")
+	}
+}
@@ -0,0 +1,83 @@
+package svg
+
+import (
+	"math"
+)
+
+// expandCubic expands box by the cubic Bézier from (x0, y0) to (x3, y3)
+// with control points (x1, y1) and (x2, y2). Control points usually lie
+// outside the curve, so rather than the control-point hull the box is
+// expanded by the endpoints and by the curve points where dx/dt or dy/dt
+// is zero. Where the derivative has no root in (0, 1) the curve is
+// monotonic on that axis and the endpoints already bound it. Points are
+// transformed by m first.
+func expandCubic(box *BoundingBox, m Matrix, x0, y0, x1, y1, x2, y2, x3, y3 float64) {
+	x0, y0 = m.Apply(x0, y0)
+	x1, y1 = m.Apply(x1, y1)
+	x2, y2 = m.Apply(x2, y2)
+	x3, y3 = m.Apply(x3, y3)
+	box.Expand(x0, y0)
+	box.Expand(x3, y3)
+
+	point := func(t float64) (float64, float64) {
+		mt := 1 - t
+		a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+		return a*x0 + b*x1 + c*x2 + d*x3, a*y0 + b*y1 + c*y2 + d*y3
+	}
+	// B'(t)/3 = a t^2 + b t + c for each axis
+	for _, p := range [][4]float64{{x0, x1, x2, x3}, {y0, y1, y2, y3}} {
+		a := -p[0] + 3*p[1] - 3*p[2] + p[3]
+		b := 2 * (p[0] - 2*p[1] + p[2])
+		c := p[1] - p[0]
+		for _, t := range quadraticRoots(a, b, c) {
+			if t > 0 && t < 1 {
+				box.Expand(point(t))
+			}
+		}
+	}
+}
+
+// expandQuadratic expands box by the quadratic Bézier from (x0, y0) to
+// (x2, y2) with control point (x1, y1), using the curve's extrema rather
+// than the control point. Points are transformed by m first.
+func expandQuadratic(box *BoundingBox, m Matrix, x0, y0, x1, y1, x2, y2 float64) {
+	x0, y0 = m.Apply(x0, y0)
+	x1, y1 = m.Apply(x1, y1)
+	x2, y2 = m.Apply(x2, y2)
+	box.Expand(x0, y0)
+	box.Expand(x2, y2)
+
+	point := func(t float64) (float64, float64) {
+		mt := 1 - t
+		a, b, c := mt*mt, 2*mt*t, t*t
+		return a*x0 + b*x1 + c*x2, a*y0 + b*y1 + c*y2
+	}
+	// B'(t)/2 = (p0 - 2p1 + p2) t + (p1 - p0) for each axis
+	for _, p := range [][3]float64{{x0, x1, x2}, {y0, y1, y2}} {
+		denom := p[0] - 2*p[1] + p[2]
+		if denom == 0 {
+			continue
+		}
+		if t := (p[0] - p[1]) / denom; t > 0 && t < 1 {
+			box.Expand(point(t))
+		}
+	}
+}
+
+// quadraticRoots returns the real roots of a t^2 + b t + c = 0, degrading
+// to the linear case when a is zero.
+func quadraticRoots(a, b, c float64) []float64 {
+	const epsilon = 1e-12
+	if math.Abs(a) < epsilon {
+		if math.Abs(b) < epsilon {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return nil
+	}
+	sq := math.Sqrt(disc)
+	return []float64{(-b + sq) / (2 * a), (-b - sq) / (2 * a)}
+}
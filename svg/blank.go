@@ -0,0 +1,74 @@
+package svg
+
+import (
+	"github.com/JoshVarga/svgparser"
+)
+
+// geometryElements are drawable elements whose bounds can be computed.
+var geometryElements = map[string]bool{
+	"path":     true,
+	"rect":     true,
+	"circle":   true,
+	"ellipse":  true,
+	"line":     true,
+	"polyline": true,
+	"polygon":  true,
+}
+
+// opaqueDrawableElements are drawable elements whose bounds cannot be
+// computed without resolving references or font metrics.
+var opaqueDrawableElements = map[string]bool{
+	"text":  true,
+	"use":   true,
+	"image": true,
+}
+
+// IsBlank returns true if the SVG has no visible content: there are no
+// drawable elements outside defs/mask/clipPath, or the drawable geometry
+// has an empty bounding box. Content that cannot be parsed is not
+// considered blank.
+func IsBlank(content []byte) bool {
+	root, err := Parse(content)
+	if err != nil {
+		return false
+	}
+
+	hasGeometry := false
+	hasOpaque := false
+	walkDrawable(root, func(elem *svgparser.Element) {
+		if geometryElements[elem.Name] {
+			hasGeometry = true
+		}
+		if opaqueDrawableElements[elem.Name] {
+			hasOpaque = true
+		}
+	})
+
+	if hasOpaque {
+		return false
+	}
+	if !hasGeometry {
+		return true
+	}
+
+	box := ContentBounds(root)
+	return !box.IsValid() || (box.Width() == 0 && box.Height() == 0)
+}
+
+// walkDrawable calls fn for each element that may render, skipping
+// defs, mask, and clipPath subtrees.
+func walkDrawable(elem *svgparser.Element, fn func(*svgparser.Element)) {
+	for _, child := range elem.Children {
+		if isNonRenderedContainer(child.Name) {
+			continue
+		}
+		fn(child)
+		walkDrawable(child, fn)
+	}
+}
+
+// isNonRenderedContainer returns true for elements whose children are not
+// rendered directly.
+func isNonRenderedContainer(name string) bool {
+	return name == "defs" || name == "mask" || name == "clipPath"
+}
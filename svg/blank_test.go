@@ -0,0 +1,27 @@
+package svg
+
+import (
+	"testing"
+)
+
+func TestIsBlank(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"empty root", `<svg viewBox="0 0 100 100"></svg>`, true},
+		{"only defs", `<svg viewBox="0 0 100 100"><defs><rect width="10" height="10"/></defs></svg>`, true},
+		{"empty group", `<svg viewBox="0 0 100 100"><g fill="#000"></g></svg>`, true},
+		{"degenerate path", `<svg viewBox="0 0 100 100"><path d="M 10 10"/></svg>`, true},
+		{"rect", `<svg viewBox="0 0 100 100"><rect width="10" height="10"/></svg>`, false},
+		{"nested path", `<svg viewBox="0 0 100 100"><g><path d="M 0 0 L 10 10"/></g></svg>`, false},
+		{"text", `<svg viewBox="0 0 100 100"><text x="10" y="10">Hi</text></svg>`, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsBlank([]byte(tt.content)); got != tt.want {
+			t.Errorf("%s: IsBlank() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -10,10 +10,10 @@ import (
 
 // BoundingBox represents a rectangular bounding box.
 type BoundingBox struct {
-	MinX float64
-	MinY float64
-	MaxX float64
-	MaxY float64
+	MinX float64 `json:"min_x"`
+	MinY float64 `json:"min_y"`
+	MaxX float64 `json:"max_x"`
+	MaxY float64 `json:"max_y"`
 }
 
 // NewBoundingBox creates an empty bounding box.
@@ -78,10 +78,10 @@ func (b *BoundingBox) Merge(other *BoundingBox) {
 
 // ViewBox represents an SVG viewBox.
 type ViewBox struct {
-	X      float64
-	Y      float64
-	Width  float64
-	Height float64
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // CenterX returns the X coordinate of the viewBox center.
@@ -99,6 +99,21 @@ func (v *ViewBox) String() string {
 	return fmt.Sprintf("%.1f %.1f %.1f %.1f", v.X, v.Y, v.Width, v.Height)
 }
 
+// Normalize returns the viewBox with positive Width and Height. A negative
+// dimension is made positive and the origin moved to the opposite edge, so
+// the normalized viewBox covers the same region.
+func (v ViewBox) Normalize() ViewBox {
+	if v.Width < 0 {
+		v.X += v.Width
+		v.Width = -v.Width
+	}
+	if v.Height < 0 {
+		v.Y += v.Height
+		v.Height = -v.Height
+	}
+	return v
+}
+
 // ParseViewBox parses a viewBox string like "0 0 100 100".
 func ParseViewBox(s string) (ViewBox, error) {
 	parts := strings.Fields(s)
@@ -110,6 +110,24 @@ func TestViewBoxCenterAndString(t *testing.T) {
 	}
 }
 
+func TestViewBoxNormalize(t *testing.T) {
+	tests := []struct {
+		input ViewBox
+		want  ViewBox
+	}{
+		{ViewBox{0, 0, 100, 100}, ViewBox{0, 0, 100, 100}},
+		{ViewBox{100, 0, -100, 50}, ViewBox{0, 0, 100, 50}},
+		{ViewBox{0, 50, 100, -50}, ViewBox{0, 0, 100, 50}},
+		{ViewBox{10, 20, -10, -20}, ViewBox{0, 0, 10, 20}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.Normalize(); got != tt.want {
+			t.Errorf("%v.Normalize() = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestParseViewBox(t *testing.T) {
 	tests := []struct {
 		input   string
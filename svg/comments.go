@@ -0,0 +1,55 @@
+package svg
+
+import (
+	"bytes"
+)
+
+var (
+	commentStart = []byte("<!--")
+	commentEnd   = []byte("-->")
+	cdataStart   = []byte("<![CDATA[")
+	cdataEnd     = []byte("]]>")
+	piStart      = []byte("<?")
+	piEnd        = []byte("?>")
+)
+
+// StripComments removes XML comments (<!-- ... -->) from SVG content.
+// Per XML rules a comment ends at the first "-->", so nested-looking
+// comments are not treated as nested. CDATA sections and processing
+// instructions are copied unchanged. An unterminated comment is removed
+// through the end of the content.
+func StripComments(content []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(content))
+
+	for i := 0; i < len(content); {
+		rest := content[i:]
+		switch {
+		case bytes.HasPrefix(rest, cdataStart):
+			n := sectionLength(rest, cdataStart, cdataEnd)
+			buf.Write(rest[:n])
+			i += n
+		case bytes.HasPrefix(rest, piStart):
+			n := sectionLength(rest, piStart, piEnd)
+			buf.Write(rest[:n])
+			i += n
+		case bytes.HasPrefix(rest, commentStart):
+			i += sectionLength(rest, commentStart, commentEnd)
+		default:
+			buf.WriteByte(content[i])
+			i++
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// sectionLength returns the length of a section starting with start and
+// ending with end, or the remaining length if the section is unterminated.
+func sectionLength(b, start, end []byte) int {
+	idx := bytes.Index(b[len(start):], end)
+	if idx < 0 {
+		return len(b)
+	}
+	return len(start) + idx + len(end)
+}
@@ -0,0 +1,40 @@
+package svg
+
+import (
+	"testing"
+)
+
+func TestStripComments(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><!-- hidden payload --><path d="M 0 0 L 10 10"/><!-- a <!-- b --></svg>`
+
+	got := string(StripComments([]byte(content)))
+	want := `<svg viewBox="0 0 10 10"><path d="M 0 0 L 10 10"/></svg>`
+	if got != want {
+		t.Errorf("StripComments() = %q, want %q", got, want)
+	}
+
+	root, err := Parse([]byte(got))
+	if err != nil {
+		t.Fatalf("stripped content should parse: %v", err)
+	}
+	if len(root.Children) != 1 || root.Children[0].Name != "path" {
+		t.Errorf("unexpected children after stripping: %v", root.Children)
+	}
+}
+
+func TestStripCommentsPreservesCDATA(t *testing.T) {
+	content := `<svg><style><![CDATA[ .a { fill: red } /* <!-- not a comment --> */ ]]></style></svg>`
+
+	if got := string(StripComments([]byte(content))); got != content {
+		t.Errorf("StripComments() = %q, want unchanged", got)
+	}
+}
+
+func TestStripCommentsUnterminated(t *testing.T) {
+	content := `<svg><path d="M 0 0"/><!-- unterminated`
+
+	want := `<svg><path d="M 0 0"/>`
+	if got := string(StripComments([]byte(content))); got != want {
+		t.Errorf("StripComments() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package convert
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseColorFunction parses rgb(), rgba(), hsl(), and hsla() notation into
+// #rrggbb, or #rrggbbaa when an alpha component is given. Components may be
+// separated by commas or whitespace, with the alpha optionally after a
+// slash. Out-of-range values are clamped. The input must already be
+// lowercased and trimmed.
+func parseColorFunction(color string) (string, error) {
+	open := strings.IndexByte(color, '(')
+	if open < 0 || !strings.HasSuffix(color, ")") {
+		return "", fmt.Errorf("invalid color function: %s", color)
+	}
+	name := strings.TrimSpace(color[:open])
+	args := strings.FieldsFunc(color[open+1:len(color)-1], func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == '\t'
+	})
+	if len(args) != 3 && len(args) != 4 {
+		return "", fmt.Errorf("invalid color function: %s (expected 3 or 4 components)", color)
+	}
+
+	var r, g, b float64
+	var err error
+	switch name {
+	case "rgb", "rgba":
+		channels := make([]float64, 3)
+		for i, arg := range args[:3] {
+			if channels[i], err = parseColorComponent(arg, 255); err != nil {
+				return "", fmt.Errorf("invalid color function: %s: %w", color, err)
+			}
+		}
+		r, g, b = channels[0], channels[1], channels[2]
+	case "hsl", "hsla":
+		h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid color function: %s: invalid hue %q", color, args[0])
+		}
+		s, err := parseColorComponent(strings.TrimSuffix(args[1], "%"), 100)
+		if err != nil {
+			return "", fmt.Errorf("invalid color function: %s: %w", color, err)
+		}
+		l, err := parseColorComponent(strings.TrimSuffix(args[2], "%"), 100)
+		if err != nil {
+			return "", fmt.Errorf("invalid color function: %s: %w", color, err)
+		}
+		r, g, b = hslToRGB(h, s/100, l/100)
+	default:
+		return "", fmt.Errorf("unknown color function: %s", name)
+	}
+
+	hex := fmt.Sprintf("#%02x%02x%02x", colorByte(r), colorByte(g), colorByte(b))
+	if len(args) == 4 {
+		a, err := parseColorComponent(args[3], 1)
+		if err != nil {
+			return "", fmt.Errorf("invalid color function: %s: %w", color, err)
+		}
+		hex += fmt.Sprintf("%02x", colorByte(a*255))
+	}
+	return hex, nil
+}
+
+// parseColorComponent parses a number or percentage and clamps it to
+// [0, limit]. A percentage is taken relative to limit.
+func parseColorComponent(s string, limit float64) (float64, error) {
+	pct := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid component %q", s)
+	}
+	if pct {
+		v = v / 100 * limit
+	}
+	return math.Min(math.Max(v, 0), limit), nil
+}
+
+// hslToRGB converts hue in degrees and saturation and lightness in [0, 1]
+// to RGB channels in [0, 255].
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return (r + m) * 255, (g + m) * 255, (b + m) * 255
+}
+
+// colorByte rounds a channel value in [0, 255] to a byte.
+func colorByte(v float64) uint8 {
+	return uint8(math.Round(math.Min(math.Max(v, 0), 255)))
+}
@@ -0,0 +1,54 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	paintTagRe        = regexp.MustCompile(`<[A-Za-z][^<>]*>`)
+	paintStyleAttrRe  = regexp.MustCompile(`\sstyle\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	fillPaintAttrRe   = regexp.MustCompile(`\sfill\s*=\s*(?:"[^"]*"|'[^']*')`)
+	strokePaintAttrRe = regexp.MustCompile(`\sstroke\s*=\s*(?:"[^"]*"|'[^']*')`)
+)
+
+// resolvePaintConflicts removes fill and stroke attributes that are
+// overridden by the same property in the element's style attribute. The
+// style declaration determines the rendered color, so dropping the dead
+// attribute ensures conversion acts on the authoritative value. Returns
+// the updated content and the number of attributes removed.
+func resolvePaintConflicts(content string) (string, int) {
+	removed := 0
+	content = paintTagRe.ReplaceAllStringFunc(content, func(tag string) string {
+		m := paintStyleAttrRe.FindStringSubmatch(tag)
+		if m == nil {
+			return tag
+		}
+		style := m[1] + m[2]
+		for _, p := range []struct {
+			prop string
+			re   *regexp.Regexp
+		}{
+			{"fill", fillPaintAttrRe},
+			{"stroke", strokePaintAttrRe},
+		} {
+			if styleDeclares(style, p.prop) && p.re.MatchString(tag) {
+				tag = p.re.ReplaceAllString(tag, "")
+				removed++
+			}
+		}
+		return tag
+	})
+	return content, removed
+}
+
+// styleDeclares reports whether a style attribute value declares prop.
+func styleDeclares(style, prop string) bool {
+	for _, decl := range strings.Split(style, ";") {
+		name, _, ok := strings.Cut(decl, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), prop) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,50 +2,223 @@
 package convert
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/grokify/mogo/os/osutil"
+
+	"github.com/grokify/brandkit/svg"
 )
 
 // Options configures the color conversion behavior.
 type Options struct {
-	Color            string // Target color (hex or named)
-	IncludeStroke    bool   // Also convert stroke colors
-	PreserveMasks    bool   // Don't modify colors in mask/clipPath
-	RemoveBackground bool   // Remove background rect/circle elements
+	Color             string            // Target color (hex or named)
+	ColorMap          map[string]string // Source to target colors; takes precedence over Color when non-empty
+	MatchTolerance    int               // Max per-channel difference (0-255) for a color to match a ColorMap source
+	IncludeStroke     bool              // Also convert stroke colors
+	PreserveMasks     bool              // Don't modify colors in mask/clipPath
+	PreserveDefs      bool              // Don't modify colors in defs (gradients, symbols)
+	RemoveBackground  bool              // Remove background rect/circle elements
+	VerboseBackground bool              // Record why each background candidate was kept or removed
+	WarnInvisibleOn   string            // Background color; warn about output colors that match it
+	GzipLevel         int               // gzip level for .svgz output, as in svg.WriteOptions (0 = gzip.DefaultCompression)
+	TargetIDs         []string          // Only convert elements with these ids (and their descendants)
+	TargetClasses     []string          // Only convert elements with these classes (and their descendants)
+	HashName          bool              // Write to {sha256}.svg in the output path's directory instead of its basename
+	FlattenVars       bool              // Replace var(--name, fallback) colors with Color instead of preserving them
+	FlattenGradients  bool              // Replace url(#id) gradient/pattern paints with Color and prune unused definitions
+	Precision         int               // When > 0, round path data coordinates to this many fractional digits
+	Logger            *slog.Logger      // Receives structured events; nil discards them
 }
 
 // Result contains the result of a color conversion.
 type Result struct {
-	InputPath         string
-	OutputPath        string
-	OriginalColor     string
-	TargetColor       string
-	Converted         bool
-	BackgroundRemoved bool
-	Error             error
+	InputPath           string
+	OutputPath          string
+	OriginalColor       string
+	TargetColor         string
+	Converted           bool
+	BackgroundRemoved   bool
+	ChangesMade         bool     // Output differs from the input
+	BackgroundDecisions []string // Per-candidate RemoveBackground decisions, if VerboseBackground
+	Warnings            []string // Non-fatal issues, e.g. colors invisible on WarnInvisibleOn
+	Error               error
 }
 
-// namedColors maps color names to hex values.
+// namedColors maps the CSS Color Module Level 4 named colors to hex
+// values. Keys are lowercase; NormalizeColor lowercases input before lookup.
+// "transparent" maps to "none", which SVG paints treat the same way.
 var namedColors = map[string]string{
-	"white":       "#ffffff",
-	"black":       "#000000",
-	"red":         "#ff0000",
-	"green":       "#00ff00",
-	"blue":        "#0000ff",
-	"yellow":      "#ffff00",
-	"cyan":        "#00ffff",
-	"magenta":     "#ff00ff",
-	"gray":        "#808080",
-	"grey":        "#808080",
-	"transparent": "none",
+	"aliceblue":            "#f0f8ff",
+	"antiquewhite":         "#faebd7",
+	"aqua":                 "#00ffff",
+	"aquamarine":           "#7fffd4",
+	"azure":                "#f0ffff",
+	"beige":                "#f5f5dc",
+	"bisque":               "#ffe4c4",
+	"black":                "#000000",
+	"blanchedalmond":       "#ffebcd",
+	"blue":                 "#0000ff",
+	"blueviolet":           "#8a2be2",
+	"brown":                "#a52a2a",
+	"burlywood":            "#deb887",
+	"cadetblue":            "#5f9ea0",
+	"chartreuse":           "#7fff00",
+	"chocolate":            "#d2691e",
+	"coral":                "#ff7f50",
+	"cornflowerblue":       "#6495ed",
+	"cornsilk":             "#fff8dc",
+	"crimson":              "#dc143c",
+	"cyan":                 "#00ffff",
+	"darkblue":             "#00008b",
+	"darkcyan":             "#008b8b",
+	"darkgoldenrod":        "#b8860b",
+	"darkgray":             "#a9a9a9",
+	"darkgreen":            "#006400",
+	"darkgrey":             "#a9a9a9",
+	"darkkhaki":            "#bdb76b",
+	"darkmagenta":          "#8b008b",
+	"darkolivegreen":       "#556b2f",
+	"darkorange":           "#ff8c00",
+	"darkorchid":           "#9932cc",
+	"darkred":              "#8b0000",
+	"darksalmon":           "#e9967a",
+	"darkseagreen":         "#8fbc8f",
+	"darkslateblue":        "#483d8b",
+	"darkslategray":        "#2f4f4f",
+	"darkslategrey":        "#2f4f4f",
+	"darkturquoise":        "#00ced1",
+	"darkviolet":           "#9400d3",
+	"deeppink":             "#ff1493",
+	"deepskyblue":          "#00bfff",
+	"dimgray":              "#696969",
+	"dimgrey":              "#696969",
+	"dodgerblue":           "#1e90ff",
+	"firebrick":            "#b22222",
+	"floralwhite":          "#fffaf0",
+	"forestgreen":          "#228b22",
+	"fuchsia":              "#ff00ff",
+	"gainsboro":            "#dcdcdc",
+	"ghostwhite":           "#f8f8ff",
+	"gold":                 "#ffd700",
+	"goldenrod":            "#daa520",
+	"gray":                 "#808080",
+	"green":                "#008000",
+	"greenyellow":          "#adff2f",
+	"grey":                 "#808080",
+	"honeydew":             "#f0fff0",
+	"hotpink":              "#ff69b4",
+	"indianred":            "#cd5c5c",
+	"indigo":               "#4b0082",
+	"ivory":                "#fffff0",
+	"khaki":                "#f0e68c",
+	"lavender":             "#e6e6fa",
+	"lavenderblush":        "#fff0f5",
+	"lawngreen":            "#7cfc00",
+	"lemonchiffon":         "#fffacd",
+	"lightblue":            "#add8e6",
+	"lightcoral":           "#f08080",
+	"lightcyan":            "#e0ffff",
+	"lightgoldenrodyellow": "#fafad2",
+	"lightgray":            "#d3d3d3",
+	"lightgreen":           "#90ee90",
+	"lightgrey":            "#d3d3d3",
+	"lightpink":            "#ffb6c1",
+	"lightsalmon":          "#ffa07a",
+	"lightseagreen":        "#20b2aa",
+	"lightskyblue":         "#87cefa",
+	"lightslategray":       "#778899",
+	"lightslategrey":       "#778899",
+	"lightsteelblue":       "#b0c4de",
+	"lightyellow":          "#ffffe0",
+	"lime":                 "#00ff00",
+	"limegreen":            "#32cd32",
+	"linen":                "#faf0e6",
+	"magenta":              "#ff00ff",
+	"maroon":               "#800000",
+	"mediumaquamarine":     "#66cdaa",
+	"mediumblue":           "#0000cd",
+	"mediumorchid":         "#ba55d3",
+	"mediumpurple":         "#9370db",
+	"mediumseagreen":       "#3cb371",
+	"mediumslateblue":      "#7b68ee",
+	"mediumspringgreen":    "#00fa9a",
+	"mediumturquoise":      "#48d1cc",
+	"mediumvioletred":      "#c71585",
+	"midnightblue":         "#191970",
+	"mintcream":            "#f5fffa",
+	"mistyrose":            "#ffe4e1",
+	"moccasin":             "#ffe4b5",
+	"navajowhite":          "#ffdead",
+	"navy":                 "#000080",
+	"oldlace":              "#fdf5e6",
+	"olive":                "#808000",
+	"olivedrab":            "#6b8e23",
+	"orange":               "#ffa500",
+	"orangered":            "#ff4500",
+	"orchid":               "#da70d6",
+	"palegoldenrod":        "#eee8aa",
+	"palegreen":            "#98fb98",
+	"paleturquoise":        "#afeeee",
+	"palevioletred":        "#db7093",
+	"papayawhip":           "#ffefd5",
+	"peachpuff":            "#ffdab9",
+	"peru":                 "#cd853f",
+	"pink":                 "#ffc0cb",
+	"plum":                 "#dda0dd",
+	"powderblue":           "#b0e0e6",
+	"purple":               "#800080",
+	"rebeccapurple":        "#663399",
+	"red":                  "#ff0000",
+	"rosybrown":            "#bc8f8f",
+	"royalblue":            "#4169e1",
+	"saddlebrown":          "#8b4513",
+	"salmon":               "#fa8072",
+	"sandybrown":           "#f4a460",
+	"seagreen":             "#2e8b57",
+	"seashell":             "#fff5ee",
+	"sienna":               "#a0522d",
+	"silver":               "#c0c0c0",
+	"skyblue":              "#87ceeb",
+	"slateblue":            "#6a5acd",
+	"slategray":            "#708090",
+	"slategrey":            "#708090",
+	"snow":                 "#fffafa",
+	"springgreen":          "#00ff7f",
+	"steelblue":            "#4682b4",
+	"tan":                  "#d2b48c",
+	"teal":                 "#008080",
+	"thistle":              "#d8bfd8",
+	"tomato":               "#ff6347",
+	"turquoise":            "#40e0d0",
+	"violet":               "#ee82ee",
+	"wheat":                "#f5deb3",
+	"white":                "#ffffff",
+	"whitesmoke":           "#f5f5f5",
+	"yellow":               "#ffff00",
+	"yellowgreen":          "#9acd32",
+	"transparent":          "none",
+}
+
+// keywordColors maps lowercase CSS color keywords to their canonical
+// spelling. These are valid conversion targets written verbatim.
+var keywordColors = map[string]string{
+	"currentcolor": "currentColor",
+	"inherit":      "inherit",
 }
 
 // NormalizeColor converts a color input to a standard #RRGGBB format.
-// Accepts: "ffffff", "#ffffff", "fff", "#fff", "white", etc.
+// Accepts: "ffffff", "#ffffff", "fff", "#fff", "white", etc., as well as
+// rgb(), rgba(), hsl(), and hsla() notation, which yield #RRGGBBAA when an
+// alpha component is given.
+// The keywords "currentColor" and "inherit" are returned as-is.
 func NormalizeColor(color string) (string, error) {
 	if color == "" {
 		return "", nil
@@ -53,11 +226,21 @@ func NormalizeColor(color string) (string, error) {
 
 	color = strings.ToLower(strings.TrimSpace(color))
 
+	// Check for CSS keywords that are written verbatim
+	if keyword, ok := keywordColors[color]; ok {
+		return keyword, nil
+	}
+
 	// Check for named colors
 	if hex, ok := namedColors[color]; ok {
 		return hex, nil
 	}
 
+	// Check for functional notation
+	if strings.ContainsRune(color, '(') {
+		return parseColorFunction(color)
+	}
+
 	// Remove # prefix if present
 	color = strings.TrimPrefix(color, "#")
 
@@ -90,150 +273,472 @@ func SVG(inputPath, outputPath string, opts Options) (*Result, error) {
 		OutputPath: outputPath,
 	}
 
+	log := svg.LoggerOrDiscard(opts.Logger)
+
+	converted, err := convertFile(inputPath, result, opts)
+	if err != nil {
+		log.Error("convert failed", "file", inputPath, "action", "convert", "error", err)
+		return result, err
+	}
+
+	// Write output file, optionally named by the hash of the written bytes
+	data, err := svg.EncodeForPath(outputPath, converted, svg.WriteOptions{GzipLevel: opts.GzipLevel})
+	if err != nil {
+		result.Error = fmt.Errorf("failed to write file: %w", err)
+		log.Error("write failed", "file", outputPath, "action", "write", "error", result.Error)
+		return result, result.Error
+	}
+	if opts.HashName {
+		outputPath = svg.HashedPath(outputPath, data)
+		result.OutputPath = outputPath
+	}
+	if err := osutil.WriteFileSecure(outputPath, data, 0600); err != nil {
+		result.Error = fmt.Errorf("failed to write file: %w", err)
+		log.Error("write failed", "file", outputPath, "action", "write", "error", result.Error)
+		return result, result.Error
+	}
+
+	result.Converted = true
+	log.Info("converted", "file", inputPath, "action", "convert", "output", outputPath,
+		"color", result.TargetColor, "background_removed", result.BackgroundRemoved)
+	return result, nil
+}
+
+// Preview converts an SVG file in memory and returns the converted content
+// without writing any output, e.g. for a GUI preview.
+func Preview(inputPath string, opts Options) ([]byte, *Result, error) {
+	result := &Result{
+		InputPath: inputPath,
+	}
+
+	converted, err := convertFile(inputPath, result, opts)
+	if err != nil {
+		return nil, result, err
+	}
+
+	result.Converted = true
+	return converted, result, nil
+}
+
+// Patch converts an SVG file in memory and returns a unified diff of the
+// original against the converted content, without writing any output.
+// Both sides are labelled with the input path so the diff applies in place
+// with patch -p0. The diff is empty if conversion changes nothing.
+func Patch(inputPath string, opts Options) (string, *Result, error) {
+	converted, result, err := Preview(inputPath, opts)
+	if err != nil {
+		return "", result, err
+	}
+	original, err := svg.ReadFile(inputPath)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return "", result, result.Error
+	}
+	name := filepath.ToSlash(inputPath)
+	return svg.UnifiedDiff(name, name, original, converted), result, nil
+}
+
+// Stream converts SVG or svgz content read from r and writes the converted
+// SVG to w, e.g. to convert an uploaded file without temporary files. The
+// output is always uncompressed.
+func Stream(r io.Reader, w io.Writer, opts Options) (*Result, error) {
+	result := &Result{}
+	converted, err := convertReader(r, result, opts)
+	if err != nil {
+		return result, err
+	}
+	if _, err := w.Write(converted); err != nil {
+		result.Error = fmt.Errorf("failed to write output: %w", err)
+		return result, result.Error
+	}
+	result.Converted = true
+	return result, nil
+}
+
+// convertFile reads and converts an SVG file, recording details and any
+// error in result.
+func convertFile(inputPath string, result *Result, opts Options) ([]byte, error) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return nil, result.Error
+	}
+	defer func() { _ = f.Close() }()
+	return convertReader(f, result, opts)
+}
+
+// convertReader reads and converts SVG content, recording details and any
+// error in result.
+func convertReader(r io.Reader, result *Result, opts Options) ([]byte, error) {
 	// Normalize target color
 	targetColor, err := NormalizeColor(opts.Color)
 	if err != nil {
 		result.Error = err
-		return result, err
+		return nil, err
 	}
 	result.TargetColor = targetColor
 
-	// Read input file
-	content, err := os.ReadFile(inputPath)
+	replace := singleColorReplacer(targetColor, opts.FlattenVars, opts.FlattenGradients)
+	if len(opts.ColorMap) > 0 {
+		colorMap, err := NormalizeColorMap(opts.ColorMap)
+		if err != nil {
+			result.Error = err
+			return nil, err
+		}
+		replace = colorMapReplacer(colorMap, opts.MatchTolerance)
+		result.TargetColor = ""
+	}
+
+	// Read input
+	raw, err := io.ReadAll(r)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to read file: %w", err)
-		return result, result.Error
+		return nil, result.Error
+	}
+	content, err := svg.Decode(raw)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return nil, result.Error
 	}
 
 	contentStr := string(content)
+	lineEndings := svg.DetectLineEndings(content)
 
 	// Remove background elements if requested
 	if opts.RemoveBackground {
-		contentStr, result.BackgroundRemoved = removeBackgroundElements(contentStr)
+		var decisions []string
+		contentStr, result.BackgroundRemoved, decisions = removeBackgroundElements(contentStr)
+		if opts.VerboseBackground {
+			result.BackgroundDecisions = decisions
+		}
 	}
 
-	// If no color specified, just copy the file (possibly with background removed)
-	if targetColor == "" {
-		if err := osutil.WriteFileSecure(outputPath, []byte(contentStr), 0600); err != nil {
-			result.Error = fmt.Errorf("failed to write file: %w", err)
-			return result, result.Error
+	// Convert colors. If no color is specified, just copy the file
+	// (possibly with background removed).
+	converting := targetColor != "" || len(opts.ColorMap) > 0
+	if converting {
+		// Drop attributes shadowed by style so conversion acts on the visible color
+		var conflicts int
+		contentStr, conflicts = resolvePaintConflicts(contentStr)
+		if conflicts > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("removed %d fill/stroke attributes overridden by style", conflicts))
+		}
+	}
+	converted := contentStr
+	switch {
+	case !converting:
+		// Nothing to convert
+	case len(opts.TargetIDs) > 0 || len(opts.TargetClasses) > 0:
+		converted, err = convertTargets(contentStr, replace, opts)
+		if err != nil {
+			result.Error = err
+			return nil, err
 		}
-		result.Converted = true
-		return result, nil
+	default:
+		converted = convertColors(contentStr, replace, opts)
+	}
+	if converting && opts.FlattenGradients && len(opts.ColorMap) == 0 {
+		converted = pruneUnusedPaintServers(converted)
 	}
 
-	// Convert colors
-	converted := convertColors(contentStr, targetColor, opts)
+	if opts.WarnInvisibleOn != "" {
+		background, err := NormalizeColor(opts.WarnInvisibleOn)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid background color: %w", err)
+			return nil, result.Error
+		}
+		result.Warnings = append(result.Warnings, invisibleColorWarnings(converted, background)...)
+	}
 
-	// Write output file
-	if err := osutil.WriteFileSecure(outputPath, []byte(converted), 0600); err != nil {
-		result.Error = fmt.Errorf("failed to write file: %w", err)
-		return result, result.Error
+	if converting && bytes.Equal(lineEndings.Apply([]byte(converted)), content) {
+		result.Warnings = append(result.Warnings, "no colors were changed; gradient (url()) and var() paints are preserved unless flattened")
+	}
+	if opts.Precision > 0 {
+		converted = roundPathData(converted, opts.Precision)
 	}
 
-	result.Converted = true
-	return result, nil
+	out := lineEndings.Apply([]byte(converted))
+	result.ChangesMade = !bytes.Equal(out, content)
+	return out, nil
 }
 
-// convertColors replaces colors in SVG content.
-func convertColors(content, targetColor string, opts Options) string {
-	// Skip values that shouldn't be converted
-	skipValues := map[string]bool{
-		"none":         true,
-		"transparent":  true,
-		"currentColor": true,
-		"inherit":      true,
+// pathDataRe matches a d attribute, capturing its value.
+var pathDataRe = regexp.MustCompile(`(\sd\s*=\s*)(?:"([^"]*)"|'([^']*)')`)
+
+// roundPathData rounds the coordinates of every d attribute in content to
+// decimals fractional digits.
+func roundPathData(content string, decimals int) string {
+	return pathDataRe.ReplaceAllStringFunc(content, func(attr string) string {
+		m := pathDataRe.FindStringSubmatch(attr)
+		return m[1] + `"` + svg.RoundPathPrecision(m[2]+m[3], decimals) + `"`
+	})
+}
+
+// Directory converts all SVG files in a directory tree, mirroring the
+// input tree structure into outDir. Errors for individual files and
+// unreadable subdirectories are recorded in their Result and do not stop
+// the batch.
+func Directory(inDir, outDir string, opts Options) ([]*Result, error) {
+	files, pathErrs, err := svg.WalkSVGFiles(inDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var results []*Result
+	for _, pe := range pathErrs {
+		results = append(results, &Result{InputPath: pe.Path, Error: pe})
 	}
+	for _, inputPath := range files {
+		rel, err := filepath.Rel(inDir, inputPath)
+		if err != nil {
+			results = append(results, &Result{InputPath: inputPath, Error: err})
+			continue
+		}
+		outputPath := filepath.Join(outDir, rel)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0750); err != nil {
+			results = append(results, &Result{
+				InputPath:  inputPath,
+				OutputPath: outputPath,
+				Error:      fmt.Errorf("failed to create directory: %w", err),
+			})
+			continue
+		}
+		result, _ := SVG(inputPath, outputPath, opts)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+var (
+	maskRe     = regexp.MustCompile(`(?s)<mask[^>]*>.*?</mask>`)
+	clipPathRe = regexp.MustCompile(`(?s)<clipPath[^>]*>.*?</clipPath>`)
+	defsRe     = regexp.MustCompile(`(?s)<defs\b[^>]*>.*?</defs>`)
+	// paintRe matches fill and stroke attributes and style properties.
+	paintRe = regexp.MustCompile(`\b(fill|stroke)\s*(?:=\s*["']|:\s*)([^;"']+)`)
+	// colorAttrRe and colorStyleRe match the color attribute and style
+	// property, which currentColor paints inherit. The leading delimiter
+	// excludes stop-color, flood-color, and lighting-color.
+	colorAttrRe  = regexp.MustCompile(`(\scolor\s*=\s*["'])([^"']+)(["'])`)
+	colorStyleRe = regexp.MustCompile(`((?:^|[\s;"'{])color\s*:\s*)([^;"'}]+)`)
+)
+
+// invisibleColorWarnings returns a warning for each fill/stroke color in
+// content that equals background. Masks and clip paths are ignored since
+// their colors are not rendered.
+func invisibleColorWarnings(content, background string) []string {
+	content = maskRe.ReplaceAllString(content, "")
+	content = clipPathRe.ReplaceAllString(content, "")
+
+	var warnings []string
+	seen := map[string]bool{}
+	for _, m := range paintRe.FindAllStringSubmatch(content, -1) {
+		color, err := NormalizeColor(m[2])
+		if err != nil || color != background || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		warnings = append(warnings, fmt.Sprintf("%s color %s is invisible on background %s", m[1], color, background))
+	}
+	return warnings
+}
 
-	// Pattern to match fill attribute
-	fillAttrRe := regexp.MustCompile(`(fill\s*=\s*["'])([^"']+)(["'])`)
+// colorReplacer returns the replacement for a fill/stroke value and
+// whether it should be replaced.
+type colorReplacer func(value string) (string, bool)
+
+// NormalizeColorMap normalizes both the source and target colors of a
+// color map with NormalizeColor.
+func NormalizeColorMap(m map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(m))
+	for from, to := range m {
+		src, err := NormalizeColor(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source color %q: %w", from, err)
+		}
+		dst, err := NormalizeColor(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target color %q: %w", to, err)
+		}
+		normalized[src] = dst
+	}
+	return normalized, nil
+}
+
+// convertColors replaces colors in SVG content.
+func convertColors(content string, replace colorReplacer, opts Options) string {
+	// Pattern to match fill attribute. A url() reference is matched whole
+	// since it may contain quotes.
+	fillAttrRe := regexp.MustCompile(`(fill\s*=\s*["'])(url\([^)]*\)|[^"']+)(["'])`)
 
 	// Pattern to match fill in style attribute
-	fillStyleRe := regexp.MustCompile(`(fill\s*:\s*)([^;"']+)`)
+	fillStyleRe := regexp.MustCompile(`(fill\s*:\s*)(url\([^)]*\)|[^;"']+)`)
 
 	// Pattern to match stroke attribute (if includeStroke)
-	strokeAttrRe := regexp.MustCompile(`(stroke\s*=\s*["'])([^"']+)(["'])`)
+	strokeAttrRe := regexp.MustCompile(`(stroke\s*=\s*["'])(url\([^)]*\)|[^"']+)(["'])`)
 
 	// Pattern to match stroke in style attribute
-	strokeStyleRe := regexp.MustCompile(`(stroke\s*:\s*)([^;"']+)`)
+	strokeStyleRe := regexp.MustCompile(`(stroke\s*:\s*)(url\([^)]*\)|[^;"']+)`)
+
+	// Set aside defs so referenced paint servers and symbols keep their colors
+	var defs []string
+	if opts.PreserveDefs {
+		content = defsRe.ReplaceAllStringFunc(content, func(match string) string {
+			placeholder := fmt.Sprintf("__DEFS_PLACEHOLDER_%d__", len(defs))
+			defs = append(defs, match)
+			return placeholder
+		})
+	}
 
 	// Track if we're inside a mask or clipPath (if preserveMasks)
 	if opts.PreserveMasks {
-		content = convertWithMaskPreservation(content, targetColor, skipValues, fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe, opts.IncludeStroke)
+		content = convertWithMaskPreservation(content, replace, fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe, opts.IncludeStroke)
 	} else {
-		content = convertAllColors(content, targetColor, skipValues, fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe, opts.IncludeStroke)
+		content = convertAllColors(content, replace, fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe, opts.IncludeStroke)
+	}
+
+	for i, d := range defs {
+		placeholder := fmt.Sprintf("__DEFS_PLACEHOLDER_%d__", i)
+		content = strings.Replace(content, placeholder, d, 1)
 	}
 
 	return content
 }
 
-// convertAllColors converts all fill/stroke colors without regard to masks.
-func convertAllColors(content, targetColor string, skipValues map[string]bool,
-	fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe *regexp.Regexp, includeStroke bool) string {
-	// Convert fill attributes
-	content = fillAttrRe.ReplaceAllStringFunc(content, func(match string) string {
-		parts := fillAttrRe.FindStringSubmatch(match)
-		if len(parts) < 4 {
-			return match
-		}
-		value := strings.TrimSpace(parts[2])
+// singleColorReplacer replaces every color with targetColor, leaving
+// none/transparent and inherited values untouched.
+func singleColorReplacer(targetColor string, flattenVars, flattenGradients bool) colorReplacer {
+	// Skip values that shouldn't be converted
+	skipValues := map[string]bool{
+		"none":         true,
+		"transparent":  true,
+		"currentColor": true,
+		"inherit":      true,
+	}
+	return func(value string) (string, bool) {
 		if skipValues[value] {
-			return match
+			return "", false
 		}
-		return parts[1] + targetColor + parts[3]
-	})
+		// CSS custom properties are themable, so keep them unless flattening
+		if !flattenVars && isVarColor(value) {
+			return "", false
+		}
+		// Gradient and pattern paints keep their look unless flattening
+		if !flattenGradients && isPaintServerRef(value) {
+			return "", false
+		}
+		return targetColor, true
+	}
+}
 
-	// Convert fill in style attributes
-	content = fillStyleRe.ReplaceAllStringFunc(content, func(match string) string {
-		parts := fillStyleRe.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
+// colorMapReplacer replaces only colors whose normalized value is a key
+// of colorMap, or within tolerance of one on every channel. The map must
+// already be normalized. When several keys are within tolerance, the
+// closest wins, with ties broken by the smaller key.
+func colorMapReplacer(colorMap map[string]string, tolerance int) colorReplacer {
+	return func(value string) (string, bool) {
+		normalized, err := NormalizeColor(value)
+		if err != nil {
+			return "", false
 		}
-		value := strings.TrimSpace(parts[2])
-		if skipValues[value] {
-			return match
+		if target, ok := colorMap[normalized]; ok {
+			return target, true
 		}
-		return parts[1] + targetColor
-	})
+		if tolerance <= 0 {
+			return "", false
+		}
+		best, bestDist := "", tolerance+1
+		for src := range colorMap {
+			dist, ok := channelDistance(normalized, src)
+			if ok && (dist < bestDist || dist == bestDist && src < best) {
+				best, bestDist = src, dist
+			}
+		}
+		if best == "" {
+			return "", false
+		}
+		return colorMap[best], true
+	}
+}
 
-	if includeStroke {
-		// Convert stroke attributes
-		content = strokeAttrRe.ReplaceAllStringFunc(content, func(match string) string {
-			parts := strokeAttrRe.FindStringSubmatch(match)
+// channelDistance returns the largest per-channel difference between two
+// normalized hex colors of the same form (#rrggbb or #rrggbbaa). It
+// returns false if either is not such a color.
+func channelDistance(a, b string) (int, bool) {
+	if len(a) != len(b) || (len(a) != 7 && len(a) != 9) || a[0] != '#' || b[0] != '#' {
+		return 0, false
+	}
+	maxDist := 0
+	for i := 1; i < len(a); i += 2 {
+		x, err1 := strconv.ParseUint(a[i:i+2], 16, 8)
+		y, err2 := strconv.ParseUint(b[i:i+2], 16, 8)
+		if err1 != nil || err2 != nil {
+			return 0, false
+		}
+		d := int(x) - int(y)
+		if d < 0 {
+			d = -d
+		}
+		maxDist = max(maxDist, d)
+	}
+	return maxDist, true
+}
+
+// convertAllColors converts all fill/stroke colors without regard to masks.
+func convertAllColors(content string, replace colorReplacer,
+	fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe *regexp.Regexp, includeStroke bool) string {
+	replaceAttr := func(re *regexp.Regexp) func(string) string {
+		return func(match string) string {
+			parts := re.FindStringSubmatch(match)
 			if len(parts) < 4 {
 				return match
 			}
-			value := strings.TrimSpace(parts[2])
-			if skipValues[value] {
+			target, ok := replace(strings.TrimSpace(parts[2]))
+			if !ok {
 				return match
 			}
-			return parts[1] + targetColor + parts[3]
-		})
-
-		// Convert stroke in style attributes
-		content = strokeStyleRe.ReplaceAllStringFunc(content, func(match string) string {
-			parts := strokeStyleRe.FindStringSubmatch(match)
+			return parts[1] + target + parts[3]
+		}
+	}
+	replaceStyle := func(re *regexp.Regexp) func(string) string {
+		return func(match string) string {
+			parts := re.FindStringSubmatch(match)
 			if len(parts) < 3 {
 				return match
 			}
-			value := strings.TrimSpace(parts[2])
-			if skipValues[value] {
+			target, ok := replace(strings.TrimSpace(parts[2]))
+			if !ok {
 				return match
 			}
-			return parts[1] + targetColor
+			return parts[1] + target
+		}
+	}
+
+	if includeStroke {
+		// Convert stroke attributes and stroke in style attributes, skipping
+		// strokes that never render. If the content cannot be parsed, every
+		// stroke is converted.
+		hidden, _ := hiddenStrokeTags(content)
+		content = mapOutsideTags(content, hidden, func(s string) string {
+			s = strokeAttrRe.ReplaceAllStringFunc(s, replaceAttr(strokeAttrRe))
+			return strokeStyleRe.ReplaceAllStringFunc(s, replaceStyle(strokeStyleRe))
 		})
 	}
 
+	// Convert fill attributes and fill in style attributes
+	content = fillAttrRe.ReplaceAllStringFunc(content, replaceAttr(fillAttrRe))
+	content = fillStyleRe.ReplaceAllStringFunc(content, replaceStyle(fillStyleRe))
+
+	// Convert the color property so currentColor fills and strokes follow
+	content = colorAttrRe.ReplaceAllStringFunc(content, replaceAttr(colorAttrRe))
+	content = colorStyleRe.ReplaceAllStringFunc(content, replaceStyle(colorStyleRe))
+
 	return content
 }
 
 // convertWithMaskPreservation converts colors but preserves mask/clipPath internals.
-func convertWithMaskPreservation(content, targetColor string, skipValues map[string]bool,
+func convertWithMaskPreservation(content string, replace colorReplacer,
 	fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe *regexp.Regexp, includeStroke bool) string {
-	// Find mask and clipPath regions to exclude
-	maskRe := regexp.MustCompile(`(?s)<mask[^>]*>.*?</mask>`)
-	clipPathRe := regexp.MustCompile(`(?s)<clipPath[^>]*>.*?</clipPath>`)
-
 	// Extract masks and clipPaths, replace with placeholders
 	var masks []string
 	var clipPaths []string
@@ -251,7 +756,7 @@ func convertWithMaskPreservation(content, targetColor string, skipValues map[str
 	})
 
 	// Convert colors in the remaining content
-	content = convertAllColors(content, targetColor, skipValues, fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe, includeStroke)
+	content = convertAllColors(content, replace, fillAttrRe, fillStyleRe, strokeAttrRe, strokeStyleRe, includeStroke)
 
 	// Restore masks and clipPaths
 	for i, mask := range masks {
@@ -268,45 +773,45 @@ func convertWithMaskPreservation(content, targetColor string, skipValues map[str
 }
 
 // removeBackgroundElements removes rect, circle, and path elements that appear to be
-// full-bleed backgrounds (spanning the entire viewBox).
-func removeBackgroundElements(content string) (string, bool) {
+// full-bleed backgrounds (spanning the entire viewBox). It also returns a
+// decision for each candidate explaining why it was kept or removed.
+func removeBackgroundElements(content string) (string, bool, []string) {
 	removed := false
+	var decisions []string
 
 	// Parse viewBox to determine dimensions
 	viewBox := parseViewBoxFromContent(content)
 	if viewBox.width == 0 || viewBox.height == 0 {
-		return content, false
+		return content, false, []string{"no viewBox or width/height; no candidates checked"}
 	}
 
-	// Remove full-bleed rect elements
-	rectRe := regexp.MustCompile(`(?s)<rect\s+[^>]*/>|<rect\s+[^>]*>\s*</rect>`)
-	content = rectRe.ReplaceAllStringFunc(content, func(match string) string {
-		if isFullBleedRect(match, viewBox) {
-			removed = true
-			return ""
-		}
-		return match
-	})
-
-	// Remove full-bleed circle elements
-	circleRe := regexp.MustCompile(`(?s)<circle\s+[^>]*/>|<circle\s+[^>]*>\s*</circle>`)
-	content = circleRe.ReplaceAllStringFunc(content, func(match string) string {
-		if isFullBleedCircle(match, viewBox) {
-			removed = true
-			return ""
-		}
-		return match
-	})
+	candidates := []struct {
+		name        string
+		re          *regexp.Regexp
+		isFullBleed func(string, viewBoxInfo) (bool, string)
+	}{
+		// Remove full-bleed rect elements
+		{"rect", regexp.MustCompile(`(?s)<rect\s+[^>]*/>|<rect\s+[^>]*>\s*</rect>`), isFullBleedRect},
+		// Remove full-bleed circle elements
+		{"circle", regexp.MustCompile(`(?s)<circle\s+[^>]*/>|<circle\s+[^>]*>\s*</circle>`), isFullBleedCircle},
+		// Remove full-bleed path elements
+		{"path", regexp.MustCompile(`(?s)<path\s+[^>]*/>|<path\s+[^>]*>\s*</path>`), isFullBleedPath},
+	}
 
-	// Remove full-bleed path elements
-	pathRe := regexp.MustCompile(`(?s)<path\s+[^>]*/>|<path\s+[^>]*>\s*</path>`)
-	content = pathRe.ReplaceAllStringFunc(content, func(match string) string {
-		if isFullBleedPath(match, viewBox) {
-			removed = true
-			return ""
-		}
-		return match
-	})
+	for _, c := range candidates {
+		n := 0
+		content = c.re.ReplaceAllStringFunc(content, func(match string) string {
+			n++
+			fullBleed, reason := c.isFullBleed(match, viewBox)
+			if fullBleed {
+				removed = true
+				decisions = append(decisions, fmt.Sprintf("%s %d removed: spans the viewBox", c.name, n))
+				return ""
+			}
+			decisions = append(decisions, fmt.Sprintf("%s %d kept: %s", c.name, n, reason))
+			return match
+		})
+	}
 
 	// Clean up any empty lines left behind
 	if removed {
@@ -314,7 +819,7 @@ func removeBackgroundElements(content string) (string, bool) {
 		content = emptyLineRe.ReplaceAllString(content, "\n\n")
 	}
 
-	return content, removed
+	return content, removed, decisions
 }
 
 type viewBoxInfo struct {
@@ -359,8 +864,9 @@ func parseFloatSafe(s string) float64 {
 	return f
 }
 
-// isFullBleedRect checks if a rect element spans the full viewBox.
-func isFullBleedRect(rectElement string, vb viewBoxInfo) bool {
+// isFullBleedRect checks if a rect element spans the full viewBox. If not,
+// it returns the dimensions that did not match.
+func isFullBleedRect(rectElement string, vb viewBoxInfo) (bool, string) {
 	x := extractAttrFloat(rectElement, "x")
 	y := extractAttrFloat(rectElement, "y")
 	width := extractAttrFloat(rectElement, "width")
@@ -368,16 +874,17 @@ func isFullBleedRect(rectElement string, vb viewBoxInfo) bool {
 
 	tolerance := vb.width * 0.01
 
-	xMatch := abs(x-vb.x) < tolerance
-	yMatch := abs(y-vb.y) < tolerance
-	widthMatch := abs(width-vb.width) < tolerance
-	heightMatch := abs(height-vb.height) < tolerance
-
-	return xMatch && yMatch && widthMatch && heightMatch
+	return dimensionsMatch(tolerance,
+		dimension{"x", x, vb.x},
+		dimension{"y", y, vb.y},
+		dimension{"width", width, vb.width},
+		dimension{"height", height, vb.height},
+	)
 }
 
-// isFullBleedCircle checks if a circle element spans the full viewBox.
-func isFullBleedCircle(circleElement string, vb viewBoxInfo) bool {
+// isFullBleedCircle checks if a circle element spans the full viewBox. If
+// not, it returns the dimensions that did not match.
+func isFullBleedCircle(circleElement string, vb viewBoxInfo) (bool, string) {
 	cx := extractAttrFloat(circleElement, "cx")
 	cy := extractAttrFloat(circleElement, "cy")
 	r := extractAttrFloat(circleElement, "r")
@@ -388,11 +895,33 @@ func isFullBleedCircle(circleElement string, vb viewBoxInfo) bool {
 
 	tolerance := vb.width * 0.01
 
-	cxMatch := abs(cx-expectedCx) < tolerance
-	cyMatch := abs(cy-expectedCy) < tolerance
-	rMatch := abs(r-expectedR) < tolerance
+	return dimensionsMatch(tolerance,
+		dimension{"cx", cx, expectedCx},
+		dimension{"cy", cy, expectedCy},
+		dimension{"r", r, expectedR},
+	)
+}
 
-	return cxMatch && cyMatch && rMatch
+// dimension is a measured shape dimension and the value expected for a
+// full-bleed background.
+type dimension struct {
+	name      string
+	got, want float64
+}
+
+// dimensionsMatch reports whether every dimension is within tolerance of
+// its expected value. If not, it describes the dimensions that failed.
+func dimensionsMatch(tolerance float64, dims ...dimension) (bool, string) {
+	var failed []string
+	for _, d := range dims {
+		if abs(d.got-d.want) >= tolerance {
+			failed = append(failed, fmt.Sprintf("%s %g vs %g", d.name, d.got, d.want))
+		}
+	}
+	if len(failed) > 0 {
+		return false, fmt.Sprintf("%s (tolerance %g)", strings.Join(failed, ", "), tolerance)
+	}
+	return true, ""
 }
 
 // extractAttrFloat extracts a float attribute value from an element string.
@@ -412,18 +941,19 @@ func abs(x float64) float64 {
 	return x
 }
 
-// isFullBleedPath checks if a path element draws a rectangle spanning the full viewBox.
-func isFullBleedPath(pathElement string, vb viewBoxInfo) bool {
+// isFullBleedPath checks if a path element draws a rectangle spanning the
+// full viewBox. If not, it returns the reason.
+func isFullBleedPath(pathElement string, vb viewBoxInfo) (bool, string) {
 	dRe := regexp.MustCompile(`d\s*=\s*["']([^"']+)["']`)
 	matches := dRe.FindStringSubmatch(pathElement)
 	if len(matches) < 2 {
-		return false
+		return false, "no path data"
 	}
 	d := matches[1]
 
 	corners := extractPathCorners(d)
 	if len(corners) < 4 {
-		return false
+		return false, fmt.Sprintf("only %d corners", len(corners))
 	}
 
 	tolerance := vb.width * 0.02
@@ -445,12 +975,12 @@ func isFullBleedPath(pathElement string, vb viewBoxInfo) bool {
 		}
 	}
 
-	xMatch := abs(minX-vb.x) < tolerance
-	yMatch := abs(minY-vb.y) < tolerance
-	widthMatch := abs(maxX-minX-vb.width) < tolerance
-	heightMatch := abs(maxY-minY-vb.height) < tolerance
-
-	return xMatch && yMatch && widthMatch && heightMatch
+	return dimensionsMatch(tolerance,
+		dimension{"x", minX, vb.x},
+		dimension{"y", minY, vb.y},
+		dimension{"width", maxX - minX, vb.width},
+		dimension{"height", maxY - minY, vb.height},
+	)
 }
 
 type point struct {
@@ -1,8 +1,12 @@
 package convert
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -25,6 +29,9 @@ func TestNormalizeColor(t *testing.T) {
 		{"black", "#000000", false},
 		{"red", "#ff0000", false},
 		{"transparent", "none", false},
+		{"currentColor", "currentColor", false},
+		{"CURRENTCOLOR", "currentColor", false},
+		{"inherit", "inherit", false},
 		{"", "", false},
 		{"gggggg", "", true},  // invalid hex chars
 		{"12345", "", true},   // wrong length
@@ -43,6 +50,80 @@ func TestNormalizeColor(t *testing.T) {
 	}
 }
 
+func TestNormalizeColorNamed(t *testing.T) {
+	tests := map[string]string{
+		"navy":                 "#000080",
+		"Coral":                "#ff7f50",
+		"REBECCAPURPLE":        "#663399",
+		"silver":               "#c0c0c0",
+		"green":                "#008000",
+		"lime":                 "#00ff00",
+		"lightgoldenrodyellow": "#fafad2",
+		"  SlateGrey  ":        "#708090",
+		"Transparent":          "none",
+	}
+	for input, want := range tests {
+		got, err := NormalizeColor(input)
+		if err != nil {
+			t.Errorf("NormalizeColor(%q) error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeColor(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	if len(namedColors) != 149 {
+		t.Errorf("namedColors has %d entries, want 148 CSS names plus transparent", len(namedColors))
+	}
+}
+
+func TestNormalizeColorUnknownName(t *testing.T) {
+	for _, name := range []string{"notacolor", "blurple", "darkgreenish"} {
+		if got, err := NormalizeColor(name); err == nil {
+			t.Errorf("NormalizeColor(%q) = %q, want error", name, got)
+		}
+	}
+}
+
+func TestNormalizeColorFunctional(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"rgb(255,255,255)", "#ffffff", false},
+		{"rgb(0, 128, 255)", "#0080ff", false},
+		{"RGB(18 52 86)", "#123456", false},
+		{"rgb(100%, 50%, 0%)", "#ff8000", false},
+		{"rgb(300, -20, 128)", "#ff0080", false}, // clamped
+		{"rgb(150%, 0%, 0%)", "#ff0000", false},  // clamped
+		{"rgba(0,0,0,0.5)", "#00000080", false},  // alpha
+		{"rgba(255 255 255 / 25%)", "#ffffff40", false},
+		{"rgba(0,0,0,2)", "#000000ff", false}, // alpha clamped
+		{"hsl(120,100%,50%)", "#00ff00", false},
+		{"hsl(0, 0%, 100%)", "#ffffff", false},
+		{"hsl(240deg 100% 25%)", "#000080", false},
+		{"hsl(-120, 100%, 50%)", "#0000ff", false}, // hue wraps
+		{"hsla(0,100%,50%,0)", "#ff000000", false},
+		{"rgb(", "", true},
+		{"rgb(1,2)", "", true},
+		{"rgb(a,b,c)", "", true},
+		{"cmyk(0,0,0,0)", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeColor(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeColor(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeColor(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestNormalizeColorTrimSpace(t *testing.T) {
 	got, err := NormalizeColor("  ffffff  ")
 	if err != nil {
@@ -320,3 +401,784 @@ func containsSubstr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDirectory(t *testing.T) {
+	inDir := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "out")
+
+	files := map[string]string{
+		"a.svg":          `<svg viewBox="0 0 100 100"><path fill="#ff0000" d="M 0 0 L 10 10"/></svg>`,
+		"nested/b.svg":   `<svg viewBox="0 0 100 100"><rect fill="#00ff00" width="10" height="10"/></svg>`,
+		"nested/c/d.svg": `<svg viewBox="0 0 100 100"><circle fill="blue" cx="5" cy="5" r="5"/></svg>`,
+		"nested/skip.md": `not an svg`,
+	}
+	for name, content := range files {
+		path := filepath.Join(inDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := Directory(inDir, outDir, Options{Color: "fff"})
+	if err != nil {
+		t.Fatalf("Directory error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	for _, name := range []string{"a.svg", "nested/b.svg", "nested/c/d.svg"} {
+		content, err := os.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Errorf("missing output %s: %v", name, err)
+			continue
+		}
+		if !contains(string(content), `fill="#ffffff"`) {
+			t.Errorf("%s: colors not converted: %s", name, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "nested", "skip.md")); err == nil {
+		t.Error("non-SVG files should not be copied")
+	}
+}
+
+func TestSVGKeywordTarget(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	svgContent := `<svg viewBox="0 0 100 100">
+  <path fill="#ff0000" d="M 10 10 L 90 10 L 90 90 Z"/>
+  <rect style="fill:#00ff00" width="10" height="10"/>
+</svg>`
+
+	if err := os.WriteFile(input, []byte(svgContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, Options{Color: "currentColor"})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.TargetColor != "currentColor" {
+		t.Errorf("TargetColor = %q, want %q", result.TargetColor, "currentColor")
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentStr := string(content)
+
+	if !contains(contentStr, `fill="currentColor"`) {
+		t.Error("output should contain fill=\"currentColor\"")
+	}
+	if !contains(contentStr, `fill:currentColor`) {
+		t.Error("output should contain fill:currentColor in style")
+	}
+}
+
+func TestSVGPreservesLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+
+	content := "<svg viewBox=\"0 0 100 100\" xmlns=\"http://www.w3.org/2000/svg\">\r\n" +
+		"  <rect x=\"0\" y=\"0\" width=\"100\" height=\"100\" fill=\"#000000\"/>\r\n" +
+		"  <path d=\"M 10 10 L 90 90\" fill=\"#ff0000\"/>\r\n" +
+		"</svg>\r\n"
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unchanged round trip is byte-identical
+	copied := filepath.Join(dir, "copied.svg")
+	if _, err := SVG(input, copied, Options{}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, err := os.ReadFile(copied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Errorf("round trip changed content:\n%q\nwant:\n%q", got, content)
+	}
+
+	// Removing the background drops a line but keeps CRLF and the trailing newline
+	converted := filepath.Join(dir, "converted.svg")
+	if _, err := SVG(input, converted, Options{Color: "ffffff", RemoveBackground: true}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, err = os.ReadFile(converted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(got), "</svg>\r\n") {
+		t.Errorf("trailing CRLF not preserved: %q", got)
+	}
+	if strings.Count(string(got), "\n") != strings.Count(string(got), "\r\n") {
+		t.Errorf("output has LF line endings: %q", got)
+	}
+}
+
+func TestSVGPalette(t *testing.T) {
+	RegisterPalette("test-dark", map[string]string{
+		"#1a73e8": "#8ab4f8",
+		"black":   "white",
+	})
+
+	colorMap, err := LookupPalette("test-dark")
+	if err != nil {
+		t.Fatalf("LookupPalette error: %v", err)
+	}
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 0 0 L 50 50" fill="#1A73E8"/>
+  <path d="M 50 50 L 100 100" style="fill:#000"/>
+  <path d="M 0 100 L 50 50" fill="#34a853"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SVG(input, output, Options{ColorMap: colorMap}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`fill="#8ab4f8"`, `fill:#ffffff`, `fill="#34a853"`} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("output missing %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestSVGColorMapMatchTolerance(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 0 0 L 10 10" fill="#ff0001"/>
+  <path d="M 0 0 L 20 20" fill="#fd0002"/>
+  <path d="M 0 0 L 30 30" fill="#fc0000"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{ColorMap: map[string]string{"#ff0000": "#ffffff"}, MatchTolerance: 2}
+	if _, err := SVG(input, output, opts); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(got)
+	// #ff0001 and #fd0002 are within 2 on every channel; #fc0000 is 3 away
+	if strings.Count(s, `fill="#ffffff"`) != 2 || !strings.Contains(s, `fill="#fc0000"`) {
+		t.Errorf("unexpected tolerance remap:\n%s", s)
+	}
+
+	opts.MatchTolerance = 0
+	if _, err := SVG(input, output, opts); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ = os.ReadFile(output)
+	if strings.Contains(string(got), "#ffffff") {
+		t.Errorf("near colors remapped without tolerance:\n%s", got)
+	}
+}
+
+func TestColorMapReplacerClosestKey(t *testing.T) {
+	replace := colorMapReplacer(map[string]string{"#100000": "#000001", "#140000": "#000002"}, 5)
+	if got, ok := replace("#130000"); !ok || got != "#000002" {
+		t.Errorf("replace(#130000) = %q, %v; want the closer key's target #000002", got, ok)
+	}
+	if got, ok := replace("#120000"); !ok || got != "#000001" {
+		t.Errorf("replace(#120000) = %q, %v; want the tie broken by the smaller key", got, ok)
+	}
+	if _, ok := replace("none"); ok {
+		t.Error("non-color value should not match")
+	}
+}
+
+func TestSVGColorMapSwapsOneColor(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 0 0 L 50 50" fill="#FF0000"/>
+  <path d="M 50 50 L 100 100" fill="#00ff00" stroke="red"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Color is ignored when ColorMap is set; both sides are normalized
+	result, err := SVG(input, output, Options{
+		Color:         "white",
+		ColorMap:      map[string]string{"red": "#00F"},
+		IncludeStroke: true,
+	})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.TargetColor != "" {
+		t.Errorf("TargetColor = %q, want empty with ColorMap", result.TargetColor)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(got)
+	for _, want := range []string{`fill="#0000ff"`, `fill="#00ff00"`, `stroke="#0000ff"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("output missing %s:\n%s", want, s)
+		}
+	}
+	if strings.Contains(s, "#ffffff") || strings.Contains(strings.ToLower(s), "#ff0000") {
+		t.Errorf("unexpected color in output:\n%s", s)
+	}
+}
+
+func TestLookupPaletteUnknown(t *testing.T) {
+	if _, err := LookupPalette("no-such-palette"); err == nil {
+		t.Error("expected error for unknown palette")
+	}
+}
+
+func TestSVGWarnInvisibleOn(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <mask id="m"><rect width="100" height="100" fill="#ffffff"/></mask>
+  <path d="M 10 10 L 90 90" fill="#1a73e8"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, Options{Color: "white", PreserveMasks: true, WarnInvisibleOn: "ffffff"})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if len(result.Warnings) != 1 {
+		t.Fatalf("got warnings %v, want 1", result.Warnings)
+	}
+	if !strings.Contains(result.Warnings[0], "fill color #ffffff") {
+		t.Errorf("warning = %q", result.Warnings[0])
+	}
+
+	// A visible target produces no warning
+	result, err = SVG(input, output, Options{Color: "black", PreserveMasks: true, WarnInvisibleOn: "ffffff"})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("got warnings %v, want none", result.Warnings)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" fill="#ff0000"/></svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	converted, result, err := Preview(input, Options{Color: "white"})
+	if err != nil {
+		t.Fatalf("Preview error: %v", err)
+	}
+	if !strings.Contains(string(converted), `fill="#ffffff"`) {
+		t.Errorf("Preview did not recolor: %s", converted)
+	}
+	if result.TargetColor != "#ffffff" || !result.Converted {
+		t.Errorf("result = %+v", result)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Preview wrote files: got %d entries, want only the input", len(entries))
+	}
+}
+
+func TestConvertTargetIDs(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <g id="logo-mark" fill="#ff0000"><path d="M 0 0 L 10 10" fill="#00ff00"/></g>
+  <path id="wordmark" d="M 20 20 L 90 90" fill="#0000ff"/>
+  <rect class="accent other" x="1" y="1" width="5" height="5" fill="#123456"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SVG(input, output, Options{Color: "white", TargetIDs: []string{"logo-mark"}}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(got)
+	if strings.Contains(s, "#ff0000") || strings.Contains(s, "#00ff00") {
+		t.Errorf("target element and descendants not recolored:\n%s", s)
+	}
+	if !strings.Contains(s, `fill="#0000ff"`) || !strings.Contains(s, `fill="#123456"`) {
+		t.Errorf("sibling colors changed:\n%s", s)
+	}
+
+	if _, err := SVG(input, output, Options{Color: "white", TargetClasses: []string{"accent"}}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ = os.ReadFile(output)
+	s = string(got)
+	if strings.Contains(s, "#123456") || !strings.Contains(s, "#ff0000") {
+		t.Errorf("class targeting wrong:\n%s", s)
+	}
+}
+
+func TestConvertPreserveDefs(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <defs>
+    <linearGradient id="g"><stop offset="0" style="stop-color:#ff0000;fill:#ff0000"/></linearGradient>
+    <symbol id="s"><path d="M 0 0 L 10 10" fill="#00ff00"/></symbol>
+  </defs>
+  <path d="M 10 10 L 90 90" fill="#0000ff"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SVG(input, output, Options{Color: "white", PreserveDefs: true}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(got)
+	if !strings.Contains(s, "fill:#ff0000") || !strings.Contains(s, `fill="#00ff00"`) {
+		t.Errorf("defs colors were converted:\n%s", s)
+	}
+	if !strings.Contains(s, `fill="#ffffff"`) || strings.Contains(s, "#0000ff") {
+		t.Errorf("content outside defs not converted:\n%s", s)
+	}
+}
+
+func TestBackgroundDecisions(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	// The rect is 5% short of the viewBox width, beyond the 1% tolerance
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="0" y="0" width="95" height="100" fill="#000000"/><circle cx="50" cy="50" r="50" fill="#ffffff"/></svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, Options{RemoveBackground: true, VerboseBackground: true})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if len(result.BackgroundDecisions) != 2 {
+		t.Fatalf("BackgroundDecisions = %q, want 2 entries", result.BackgroundDecisions)
+	}
+	if d := result.BackgroundDecisions[0]; !strings.Contains(d, "rect 1 kept") || !strings.Contains(d, "width 95 vs 100") || strings.Contains(d, "height") {
+		t.Errorf("rect decision = %q, want it kept for its width only", d)
+	}
+	if d := result.BackgroundDecisions[1]; !strings.Contains(d, "circle 1 removed") {
+		t.Errorf("circle decision = %q, want removed", d)
+	}
+
+	result, err = SVG(input, output, Options{RemoveBackground: true})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.BackgroundDecisions != nil {
+		t.Errorf("BackgroundDecisions recorded without VerboseBackground: %q", result.BackgroundDecisions)
+	}
+}
+
+func TestExtractColors(t *testing.T) {
+	content := `<svg><defs><linearGradient id="g"><stop stop-color="#F00"/></linearGradient></defs>
+<path fill="url(#g)" stroke="black"/><rect style="fill: #ff0000; stroke: none"/><circle fill="currentColor"/></svg>`
+
+	got := ExtractColors(content)
+	want := []string{"#000000", "#ff0000"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExtractColors() = %v, want %v", got, want)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <title>Logo</title>
+  <g>
+    <path d="M 10 10 L 90 90" fill="#ff0000"/>
+  </g>
+  <desc>Unrelated</desc>
+  <circle cx="5" cy="5" r="1" fill="none"/>
+</svg>
+`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	patch, _, err := Patch(input, Options{Color: "white"})
+	if err != nil {
+		t.Fatalf("Patch error: %v", err)
+	}
+
+	var changed []string
+	for _, line := range strings.Split(patch, "\n") {
+		if (strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")) &&
+			!strings.HasPrefix(line, "---") && !strings.HasPrefix(line, "+++") {
+			changed = append(changed, line)
+		}
+	}
+	want := []string{
+		`-    <path d="M 10 10 L 90 90" fill="#ff0000"/>`,
+		`+    <path d="M 10 10 L 90 90" fill="#ffffff"/>`,
+	}
+	if strings.Join(changed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changed lines = %q, want %q\npatch:\n%s", changed, want, patch)
+	}
+	if !strings.Contains(patch, "@@ -1,7 +1,7 @@") {
+		t.Errorf("unexpected hunk header:\n%s", patch)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Patch wrote files: got %d entries", len(entries))
+	}
+}
+
+func TestConvertAttributeStyleConflict(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	// The style fill (#00ff00) is the visible color; the attribute is dead
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 0 0 L 10 10" fill="#ff0000" style="fill:#00ff00"/></svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, Options{ColorMap: map[string]string{"#00ff00": "#0000ff", "#ff0000": "#000000"}})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	s := string(got)
+	if !strings.Contains(s, "fill:#0000ff") {
+		t.Errorf("visible color not converted:\n%s", s)
+	}
+	if strings.Contains(s, `fill="`) || strings.Contains(s, "#000000") {
+		t.Errorf("shadowed fill attribute not removed:\n%s", s)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %q, want one conflict warning", result.Warnings)
+	}
+}
+
+func TestConvertPreservesVarColors(t *testing.T) {
+	content := `<svg><path fill="var(--x,#f00)"/><rect style="fill: var(--bg, #00f)"/><circle fill="#0f0"/></svg>`
+
+	got := convertColors(content, singleColorReplacer("#ffffff", false, false), Options{})
+	want := `<svg><path fill="var(--x,#f00)"/><rect style="fill: var(--bg, #00f)"/><circle fill="#ffffff"/></svg>`
+	if got != want {
+		t.Errorf("convertColors() =\n%s\nwant\n%s", got, want)
+	}
+
+	got = convertColors(content, singleColorReplacer("#ffffff", true, false), Options{})
+	want = `<svg><path fill="#ffffff"/><rect style="fill: #ffffff"/><circle fill="#ffffff"/></svg>`
+	if got != want {
+		t.Errorf("convertColors() with FlattenVars =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSVGFlattenGradients(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <defs>
+    <linearGradient id="base"><stop offset="0" stop-color="#f00"/><stop offset="1" stop-color="#00f"/></linearGradient>
+    <linearGradient id="grad1" href="#base" x2="1"/>
+    <radialGradient id="kept"><stop offset="0" stop-color="#0f0"/></radialGradient>
+  </defs>
+  <path d="M 0 0 L 50 50" fill="url(#grad1)"/>
+  <path d="M 50 50 L 100 100" style="fill:url('#grad1')"/>
+  <mask id="m"><rect width="100" height="100" fill="url(#kept)"/></mask>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SVG(input, output, Options{Color: "white", PreserveMasks: true}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	if !strings.Contains(string(got), `fill="url(#grad1)"`) || !strings.Contains(string(got), `fill:url('#grad1')`) {
+		t.Errorf("gradient fills should be preserved by default:\n%s", got)
+	}
+
+	if _, err := SVG(input, output, Options{Color: "white", PreserveMasks: true, FlattenGradients: true}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ = os.ReadFile(output)
+	s := string(got)
+	for _, want := range []string{`<path d="M 0 0 L 50 50" fill="#ffffff"/>`, `style="fill:#ffffff"`, `id="kept"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("output missing %s:\n%s", want, s)
+		}
+	}
+	for _, unwanted := range []string{`id="grad1"`, `id="base"`} {
+		if strings.Contains(s, unwanted) {
+			t.Errorf("unused gradient %s not pruned:\n%s", unwanted, s)
+		}
+	}
+}
+
+func TestSVGColorProperty(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <defs><linearGradient id="g"><stop offset="0" stop-color="#00f" style="stop-color:#00f"/></linearGradient></defs>
+  <g style="color:#f00"><path d="M 0 0 L 50 50" fill="currentColor"/></g>
+  <g color="#0f0"><path d="M 50 50 L 100 100" stroke="currentColor"/></g>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, Options{Color: "white"})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	s := string(got)
+	for _, want := range []string{`style="color:#ffffff"`, `color="#ffffff"`, `fill="currentColor"`, `stop-color="#00f"`, `style="stop-color:#00f"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("output missing %s:\n%s", want, s)
+		}
+	}
+	if !result.ChangesMade {
+		t.Error("ChangesMade = false, want true")
+	}
+}
+
+func TestSVGPrecision(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M10.123456789,10.987654321L90.000000001,90.5" fill="#000"/><path d='M0 0a5 5 0 0110.004 0'/></svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SVG(input, output, Options{Precision: 2}); err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	want := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10.12 10.99 L 90 90.5" fill="#000"/><path d="M 0 0 a 5 5 0 0 1 10 0"/></svg>`
+	if string(got) != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSVGChangesMade(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <defs><linearGradient id="g"><stop offset="0" stop-color="#f00"/><stop offset="1" stop-color="#00f"/></linearGradient></defs>
+  <path d="M 0 0 L 100 100" fill="url(#g)"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, Options{Color: "white"})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.ChangesMade {
+		t.Error("ChangesMade = true for gradient-only icon, want false")
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "no colors were changed") {
+		t.Errorf("Warnings = %q, want a no-change warning", result.Warnings)
+	}
+
+	result, err = SVG(input, output, Options{Color: "white", FlattenGradients: true})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if !result.ChangesMade || len(result.Warnings) != 0 {
+		t.Errorf("flattened: ChangesMade = %v, Warnings = %q", result.ChangesMade, result.Warnings)
+	}
+}
+
+func TestPalette(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <style>.accent { fill: #00F; }</style>
+  <path d="M 0 0 L 10 10" fill="Red"/>
+  <path d="M 0 0 L 20 20" fill="#ff0000" stroke="#000"/>
+  <path d="M 0 0 L 30 30" style="stroke:#f00; fill:none"/>
+  <path d="M 0 0 L 40 40" fill="url(#g)" stroke="black"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Palette(input)
+	if err != nil {
+		t.Fatalf("Palette error: %v", err)
+	}
+	want := []PaletteEntry{
+		{Color: "#ff0000", Raw: "Red", Count: 3, Fill: true, Stroke: true, Style: true},
+		{Color: "#000000", Raw: "#000", Count: 2, Stroke: true},
+		{Color: "#0000ff", Raw: "#00F", Count: 1, Fill: true, Style: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Palette = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := Palette(filepath.Join(dir, "missing.svg")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestExtractColorsVarFallback(t *testing.T) {
+	content := `<svg><path fill="var(--x,#f00)"/><rect stroke="var(--a, var(--b, navy))"/><circle fill="var(--nofallback)"/></svg>`
+
+	got := ExtractColors(content)
+	want := []string{"#000080", "#ff0000"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExtractColors() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractColorsTspans(t *testing.T) {
+	content := `<svg><text fill="#000">Brand<tspan fill="#ff0000">Kit</tspan><tspan style="fill:#00f">!</tspan></text></svg>`
+
+	got := ExtractColors(content)
+	want := []string{"#000000", "#0000ff", "#ff0000"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExtractColors() = %v, want %v", got, want)
+	}
+}
+
+func TestConvertSkipsHiddenStrokes(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100">
+  <path fill="#ff0000" d="M 0 0 L 10 10"/>
+  <path fill="#ff0000" stroke="#00ff00" stroke-width="0" d="M 0 0 L 10 10"/>
+  <g stroke-width="0"><path stroke="#0000ff" d="M 0 0 L 10 10"/></g>
+  <g stroke="#00ffff" stroke-width="0"><path stroke-width="2" d="M 0 0 L 10 10"/></g>
+  <path style="stroke:#ffff00;stroke-width:0" d="M 0 0 L 10 10"/>
+</svg>`
+
+	got := convertColors(content, singleColorReplacer("#ffffff", false, false), Options{IncludeStroke: true})
+
+	if strings.Count(got, "stroke=") != 3 {
+		t.Errorf("stroke attributes were added or removed: %s", got)
+	}
+	for _, hidden := range []string{`stroke="#00ff00"`, `stroke="#0000ff"`, `stroke:#ffff00`} {
+		if !strings.Contains(got, hidden) {
+			t.Errorf("zero-width stroke %s should not be recolored: %s", hidden, got)
+		}
+	}
+	// A descendant with a visible width renders the inherited stroke
+	if strings.Contains(got, `stroke="#00ffff"`) {
+		t.Errorf("inherited visible stroke should be recolored: %s", got)
+	}
+}
+
+func TestStream(t *testing.T) {
+	input := `<svg viewBox="0 0 100 100"><path fill="#ff0000" d="M 0 0 L 10 10"/></svg>`
+
+	var out bytes.Buffer
+	result, err := Stream(strings.NewReader(input), &out, Options{Color: "white"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	if !result.Converted || result.TargetColor != "#ffffff" {
+		t.Errorf("Converted, TargetColor = %v, %q", result.Converted, result.TargetColor)
+	}
+	if want := `<path fill="#ffffff" d="M 0 0 L 10 10"/>`; !strings.Contains(out.String(), want) {
+		t.Errorf("output = %s, want it to contain %s", out.String(), want)
+	}
+}
+
+func TestSVGHashName(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	if err := os.WriteFile(input, []byte(`<svg viewBox="0 0 100 100"><path fill="#ff0000" d="M 0 0 L 10 10"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	result, err := SVG(input, filepath.Join(outDir, "ignored.svg"), Options{Color: "white", HashName: true})
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+
+	written, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", result.OutputPath, err)
+	}
+	sum := sha256.Sum256(written)
+	if want := filepath.Join(outDir, hex.EncodeToString(sum[:])+".svg"); result.OutputPath != want {
+		t.Errorf("OutputPath = %s, want %s", result.OutputPath, want)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "ignored.svg")); !os.IsNotExist(err) {
+		t.Error("literal output basename should not be written")
+	}
+}
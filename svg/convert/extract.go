@@ -0,0 +1,149 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// colorValueRe matches fill, stroke, and stop-color attributes and style
+// properties.
+var colorValueRe = regexp.MustCompile(`\b(fill|stroke|stop-color)\s*(?:=\s*["']|:\s*)([^;"']+)`)
+
+// ExtractColors returns the distinct colors used for fill, stroke, and
+// gradient stops, normalized to #rrggbb and sorted. Keywords such as none,
+// currentColor, and paint server references are not colors and are skipped.
+// For a CSS custom property such as var(--brand, #f00), the fallback color
+// is reported.
+func ExtractColors(content string) []string {
+	seen := map[string]bool{}
+	var colors []string
+	for _, m := range colorValueRe.FindAllStringSubmatch(content, -1) {
+		color, err := NormalizeColor(varFallback(m[2]))
+		if err != nil || !strings.HasPrefix(color, "#") || seen[color] {
+			continue
+		}
+		seen[color] = true
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+	return colors
+}
+
+// PaletteEntry describes one color found by Palette.
+type PaletteEntry struct {
+	Color  string // Normalized color, e.g. #ff0000
+	Raw    string // Value as first found, e.g. "Red" or "#F00"
+	Count  int    // Number of occurrences
+	Fill   bool   // Used as a fill
+	Stroke bool   // Used as a stroke
+	Style  bool   // Found in a style attribute or <style> block rather than a presentation attribute
+}
+
+// Palette returns the fill and stroke colors used in an SVG file, for
+// building an Options.ColorMap. Entries are aggregated by normalized color
+// and sorted by descending count, then by color. Values that are not
+// colors, such as none or url(#id), are skipped.
+func Palette(path string) ([]PaletteEntry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return paletteEntries(string(content)), nil
+}
+
+// PaletteDirectory returns the palette aggregated across all SVG files in
+// a directory tree. Counts are summed and usage flags combined; Raw is the
+// value as first found. It fails if any file or subdirectory is unreadable.
+func PaletteDirectory(dir string) ([]PaletteEntry, error) {
+	files, pathErrs, err := svg.WalkSVGFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	if len(pathErrs) > 0 {
+		return nil, fmt.Errorf("failed to read directory: %w", pathErrs[0])
+	}
+
+	var all []PaletteEntry
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		all = append(all, paletteEntries(string(content))...)
+	}
+	return mergePaletteEntries(all), nil
+}
+
+// paletteEntries aggregates the fill and stroke colors in content.
+func paletteEntries(content string) []PaletteEntry {
+	var entries []PaletteEntry
+	for _, m := range paintRe.FindAllStringSubmatch(content, -1) {
+		raw := strings.TrimSpace(m[2])
+		color, err := NormalizeColor(varFallback(raw))
+		if err != nil || !strings.HasPrefix(color, "#") {
+			continue
+		}
+		entries = append(entries, PaletteEntry{
+			Color:  color,
+			Raw:    raw,
+			Count:  1,
+			Fill:   m[1] == "fill",
+			Stroke: m[1] == "stroke",
+			Style:  !strings.Contains(m[0], "="),
+		})
+	}
+	return mergePaletteEntries(entries)
+}
+
+// mergePaletteEntries combines entries with the same color, keeping the
+// first Raw value, and sorts the result by descending count, then color.
+func mergePaletteEntries(entries []PaletteEntry) []PaletteEntry {
+	index := map[string]int{}
+	var merged []PaletteEntry
+	for _, e := range entries {
+		i, ok := index[e.Color]
+		if !ok {
+			index[e.Color] = len(merged)
+			merged = append(merged, e)
+			continue
+		}
+		m := &merged[i]
+		m.Count += e.Count
+		m.Fill = m.Fill || e.Fill
+		m.Stroke = m.Stroke || e.Stroke
+		m.Style = m.Style || e.Style
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Count != merged[j].Count {
+			return merged[i].Count > merged[j].Count
+		}
+		return merged[i].Color < merged[j].Color
+	})
+	return merged
+}
+
+// isVarColor reports whether a paint value is a CSS var() reference.
+func isVarColor(value string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(value)), "var(")
+}
+
+// varFallback returns the fallback of a var() reference, following nested
+// var() fallbacks, or value unchanged if it is not a var() reference. A
+// var() without a fallback yields "".
+func varFallback(value string) string {
+	for isVarColor(value) {
+		value = strings.TrimSpace(value)
+		inner := strings.TrimSuffix(value[len("var("):], ")")
+		_, fallback, ok := strings.Cut(inner, ",")
+		if !ok {
+			return ""
+		}
+		value = fallback
+	}
+	return strings.TrimSpace(value)
+}
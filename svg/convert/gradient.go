@@ -0,0 +1,57 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// paintServerRes match gradient and pattern definitions, which are
+	// referenced from fill and stroke as url(#id).
+	paintServerRes = []*regexp.Regexp{
+		regexp.MustCompile(`(?s)<linearGradient\b[^>]*?/>|<linearGradient\b[^>]*>.*?</linearGradient\s*>`),
+		regexp.MustCompile(`(?s)<radialGradient\b[^>]*?/>|<radialGradient\b[^>]*>.*?</radialGradient\s*>`),
+		regexp.MustCompile(`(?s)<pattern\b[^>]*?/>|<pattern\b[^>]*>.*?</pattern\s*>`),
+	}
+	elementIDRe = regexp.MustCompile(`^<[^>]*?\sid\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// isPaintServerRef reports whether a paint value references a gradient or
+// pattern, such as url(#grad1).
+func isPaintServerRef(value string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(value)), "url(")
+}
+
+// pruneUnusedPaintServers removes gradient and pattern definitions that are
+// no longer referenced by a url(#id) paint or an href, such as after
+// flattening gradient fills. Removal repeats until stable, since a gradient
+// may only be referenced by another gradient's href.
+func pruneUnusedPaintServers(content string) string {
+	for {
+		removed := false
+		for _, re := range paintServerRes {
+			content = re.ReplaceAllStringFunc(content, func(def string) string {
+				m := elementIDRe.FindStringSubmatch(def)
+				if m == nil || m[1]+m[2] == "" {
+					return def
+				}
+				if isReferenced(strings.Replace(content, def, "", 1), m[1]+m[2]) {
+					return def
+				}
+				removed = true
+				return ""
+			})
+		}
+		if !removed {
+			return content
+		}
+	}
+}
+
+// isReferenced reports whether content references id via url(#id) or an
+// href="#id" attribute.
+func isReferenced(content, id string) bool {
+	q := regexp.QuoteMeta(id)
+	re := regexp.MustCompile(`url\(\s*["']?#` + q + `["']?\s*\)|href\s*=\s*["']#` + q + `["']`)
+	return re.MatchString(content)
+}
@@ -0,0 +1,57 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	palettesMu sync.RWMutex
+	palettes   = map[string]map[string]string{}
+)
+
+// RegisterPalette registers a named palette mapping source colors to
+// target colors, for use as Options.ColorMap. Registering an existing
+// name replaces it. Palette names are case-insensitive.
+func RegisterPalette(name string, m map[string]string) {
+	colorMap := make(map[string]string, len(m))
+	for from, to := range m {
+		colorMap[from] = to
+	}
+
+	palettesMu.Lock()
+	defer palettesMu.Unlock()
+	palettes[strings.ToLower(name)] = colorMap
+}
+
+// LookupPalette returns a copy of the color map registered under name.
+// It returns an error for unknown palettes.
+func LookupPalette(name string) (map[string]string, error) {
+	palettesMu.RLock()
+	defer palettesMu.RUnlock()
+
+	m, ok := palettes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown palette: %s", name)
+	}
+	colorMap := make(map[string]string, len(m))
+	for from, to := range m {
+		colorMap[from] = to
+	}
+	return colorMap, nil
+}
+
+// PaletteNames returns the names of all registered palettes, sorted.
+func PaletteNames() []string {
+	palettesMu.RLock()
+	defer palettesMu.RUnlock()
+
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,114 @@
+package convert
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// hiddenStrokeTags returns the start offsets of elements that declare a
+// stroke color which never renders: the element's effective stroke-width,
+// including inherited values, is zero and no descendant that inherits the
+// stroke sets a non-zero width. Recoloring such strokes only adds noise and
+// would surface as outlines if a width were set later.
+func hiddenStrokeTags(content string) (map[int]bool, error) {
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	// Offsets are all that matter here, so accept any declared encoding.
+	dec.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+
+	type frame struct {
+		offset    int
+		hasStroke bool    // declares its own stroke color
+		width     float64 // effective stroke-width
+		widened   bool    // a descendant sets a non-zero stroke-width
+	}
+	stack := []frame{{width: 1}}
+	hidden := map[int]bool{}
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			f := frame{offset: offset, width: parent.width}
+			stroke := paintProperty(t.Attr, "stroke")
+			f.hasStroke = stroke != "" && stroke != "none" && stroke != "inherit"
+			if w := paintProperty(t.Attr, "stroke-width"); w != "" && w != "inherit" {
+				f.width = svg.ParseFloat(w, 1)
+				if f.width != 0 {
+					f.widened = true
+				}
+			}
+			stack = append(stack, f)
+		case xml.EndElement:
+			if len(stack) == 1 {
+				continue
+			}
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if f.hasStroke && f.width == 0 && !f.widened {
+				hidden[f.offset] = true
+			}
+			if f.widened {
+				stack[len(stack)-1].widened = true
+			}
+		}
+	}
+	return hidden, nil
+}
+
+// paintProperty returns the value of prop from an element's style
+// attribute, which takes precedence, or from its presentation attribute.
+func paintProperty(attrs []xml.Attr, prop string) string {
+	var value string
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case prop:
+			if value == "" {
+				value = strings.TrimSpace(a.Value)
+			}
+		case "style":
+			for _, decl := range strings.Split(a.Value, ";") {
+				name, v, ok := strings.Cut(decl, ":")
+				if ok && strings.EqualFold(strings.TrimSpace(name), prop) {
+					return strings.TrimSpace(v)
+				}
+			}
+		}
+	}
+	return value
+}
+
+// mapOutsideTags applies fn to the content outside the start tags
+// beginning at the given offsets, leaving those tags untouched.
+func mapOutsideTags(content string, skip map[int]bool, fn func(string) string) string {
+	if len(skip) == 0 {
+		return fn(content)
+	}
+	var sb strings.Builder
+	last := 0
+	for _, loc := range paintTagRe.FindAllStringIndex(content, -1) {
+		if !skip[loc[0]] {
+			continue
+		}
+		sb.WriteString(fn(content[last:loc[0]]))
+		sb.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(fn(content[last:]))
+	return sb.String()
+}
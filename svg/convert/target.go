@@ -0,0 +1,99 @@
+package convert
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// convertTargets converts colors only within elements whose id is in
+// opts.TargetIDs or whose class list contains one of opts.TargetClasses,
+// including their descendants.
+func convertTargets(content string, replace colorReplacer, opts Options) (string, error) {
+	regions, err := targetRegions(content, opts.TargetIDs, opts.TargetClasses)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate target elements: %w", err)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, r := range regions {
+		sb.WriteString(content[last:r[0]])
+		sb.WriteString(convertColors(content[r[0]:r[1]], replace, opts))
+		last = r[1]
+	}
+	sb.WriteString(content[last:])
+	return sb.String(), nil
+}
+
+// targetRegions returns the byte ranges of the outermost elements matching
+// ids or classes, from the start of the start tag to the end of the end tag.
+func targetRegions(content string, ids, classes []string) ([][2]int, error) {
+	idSet := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		idSet[id] = true
+	}
+	classSet := make(map[string]bool, len(classes))
+	for _, cls := range classes {
+		classSet[cls] = true
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	// Offsets are all that matter here, so accept any declared encoding.
+	dec.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+
+	var regions [][2]int
+	depth := 0
+	regionStart, regionDepth := -1, 0
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if regionStart < 0 && matchesTarget(t.Attr, idSet, classSet) {
+				regionStart, regionDepth = start, depth
+			}
+		case xml.EndElement:
+			if regionStart >= 0 && depth == regionDepth {
+				regions = append(regions, [2]int{regionStart, int(dec.InputOffset())})
+				regionStart = -1
+			}
+			depth--
+		}
+	}
+	return regions, nil
+}
+
+// matchesTarget reports whether an element's id or class attribute
+// matches one of the targets.
+func matchesTarget(attrs []xml.Attr, ids, classes map[string]bool) bool {
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case "id":
+			if ids[a.Value] {
+				return true
+			}
+		case "class":
+			for _, cls := range strings.Fields(a.Value) {
+				if classes[cls] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package svg
+
+import (
+	"bytes"
+	"regexp"
+)
+
+var lineBreakRe = regexp.MustCompile(`[ \t]*[\r\n][\s]*`)
+
+// MinifyForDataURI returns SVG content as a compact single line suitable
+// for data URI encoding. It strips comments and the XML declaration, joins
+// lines, and removes whitespace between tags. Whitespace inside <text>
+// elements is kept as single spaces since it is rendered.
+func MinifyForDataURI(content []byte) []byte {
+	content = StripXMLDeclaration(StripComments(content))
+	content = lineBreakRe.ReplaceAll(content, []byte(" "))
+	return CollapseWhitespace(bytes.TrimSpace(content))
+}
@@ -0,0 +1,47 @@
+package svg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMinifyForDataURI(t *testing.T) {
+	content := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generator: Example -->
+<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <g fill="#000000">
+    <path d="M 10 10
+             L 90 10
+             L 90 90 Z"/>
+    <circle cx="50" cy="50" r="10"/>
+  </g>
+  <text x="10" y="95">Hello  World</text>
+</svg>
+`)
+
+	minified := MinifyForDataURI(content)
+	if bytes.ContainsAny(minified, "\r\n") {
+		t.Errorf("minified output is not a single line: %q", minified)
+	}
+	if bytes.Contains(minified, []byte("<?xml")) || bytes.Contains(minified, []byte("<!--")) {
+		t.Errorf("declaration or comment not removed: %s", minified)
+	}
+	if !bytes.Contains(minified, []byte("Hello  World")) {
+		t.Errorf("text content changed: %s", minified)
+	}
+	if len(minified) >= len(content) {
+		t.Errorf("minified size %d not smaller than %d", len(minified), len(content))
+	}
+
+	orig, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse original error: %v", err)
+	}
+	min, err := Parse(minified)
+	if err != nil {
+		t.Fatalf("Parse minified error: %v", err)
+	}
+	if *ContentBounds(orig) != *ContentBounds(min) {
+		t.Errorf("bounds changed: %+v vs %+v", *ContentBounds(orig), *ContentBounds(min))
+	}
+}
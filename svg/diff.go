@@ -0,0 +1,186 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around changes.
+const diffContextLines = 3
+
+// diffOp is a line-level edit operation.
+type diffOp struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// UnifiedDiff returns a unified diff between from and to, labelled with
+// fromName and toName, or "" if the contents are equal.
+func UnifiedDiff(fromName, toName string, from, to []byte) string {
+	if string(from) == string(to) {
+		return ""
+	}
+	ops := diffLines(splitLines(string(from)), splitLines(string(to)))
+
+	var sb strings.Builder
+	sb.WriteString("--- " + fromName + "\n")
+	sb.WriteString("+++ " + toName + "\n")
+
+	// fromLine and toLine are the 1-based line numbers of ops[i]
+	fromLine, toLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			fromLine++
+			toLine++
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within 2*context lines
+		start := max(0, i-diffContextLines)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContextLines {
+				break
+			}
+		}
+		end = min(len(ops), end+diffContextLines)
+
+		hunkFrom := fromLine - (i - start)
+		hunkTo := toLine - (i - start)
+		var fromCount, toCount int
+		var body strings.Builder
+		for _, op := range ops[start:end] {
+			body.WriteString(string(op.kind) + op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				fromLine++
+			}
+			if op.kind != '-' {
+				toLine++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunkFrom, fromCount), hunkRange(hunkTo, toCount))
+		sb.WriteString(body.String())
+		i = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats a hunk range. An empty range refers to the line before.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits content into lines, each keeping its terminator so
+// that CRLF line endings survive into the diff. The last line has no
+// terminator if content does not end with a newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a minimal line diff of a and b with Myers' algorithm.
+// Memory grows with the square of the number of edits rather than with
+// the product of the file lengths.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+
+	// trace[d][k+d] is the furthest x reached on diagonal k = x-y with d edits
+	var trace [][]int
+	furthest := func(d, k int) int { return trace[d][k+d] }
+	for d := 0; d <= n+m; d++ {
+		v := make([]int, 2*d+1)
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			switch {
+			case d == 0:
+				x = 0
+			case k == -d || (k != d && furthest(d-1, k-1) < furthest(d-1, k+1)):
+				x = furthest(d-1, k+1) // insertion: move down from diagonal k+1
+			default:
+				x = furthest(d-1, k-1) + 1 // deletion: move right from diagonal k-1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+d] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		trace = append(trace, v)
+		if done {
+			break
+		}
+	}
+
+	// Walk back from (n, m), collecting operations in reverse
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && furthest(d-1, k-1) < furthest(d-1, k+1)) {
+			prevK = k + 1
+		}
+		prevX := furthest(d-1, prevK)
+		prevY := prevX - prevK
+
+		// The snake on diagonal k starts just after the edit from prevK
+		snakeX := prevX
+		if prevK == k-1 {
+			snakeX++
+		}
+		for x > snakeX {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if prevK == k+1 {
+			ops = append(ops, diffOp{'+', b[prevY]})
+		} else {
+			ops = append(ops, diffOp{'-', a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,67 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	if got := UnifiedDiff("a", "b", []byte("x\n"), []byte("x\n")); got != "" {
+		t.Errorf("UnifiedDiff(equal) = %q, want empty", got)
+	}
+
+	from := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	to := []byte("1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n")
+	want := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if got := UnifiedDiff("a", "b", from, to); got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiffLineEndings(t *testing.T) {
+	from := []byte("<svg>\r\n<rect/>\r\n</svg>\r\n")
+	to := []byte("<svg>\r\n<circle/>\r\n</svg>")
+	want := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n" +
+		" <svg>\r\n" +
+		"-<rect/>\r\n" +
+		"-</svg>\r\n" +
+		"+<circle/>\r\n" +
+		"+</svg>\n\\ No newline at end of file\n"
+	if got := UnifiedDiff("a", "b", from, to); got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedDiffLarge(t *testing.T) {
+	var from, to strings.Builder
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&from, "line %d\n", i)
+		if i == 25000 {
+			to.WriteString("changed\n")
+			continue
+		}
+		fmt.Fprintf(&to, "line %d\n", i)
+	}
+	want := "--- a\n+++ b\n@@ -24998,7 +24998,7 @@\n" +
+		" line 24997\n line 24998\n line 24999\n" +
+		"-line 25000\n+changed\n" +
+		" line 25001\n line 25002\n line 25003\n"
+	if got := UnifiedDiff("a", "b", []byte(from.String()), []byte(to.String())); got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JoshVarga/svgparser"
+)
+
+// equalPrecision is the number of fractional digits numbers are rounded to
+// when comparing SVGs with Equal.
+const equalPrecision = 6
+
+// equalNumberRe matches a number within an attribute value.
+var equalNumberRe = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// Equal reports whether two SVGs are structurally equal, ignoring
+// formatting: whitespace, attribute order, comments, path data separators,
+// and number formatting up to six fractional digits. Colors and other
+// values are compared as written, so #fff and #ffffff differ.
+func Equal(a, b []byte) (bool, error) {
+	ea, err := Parse(a)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse first SVG: %w", err)
+	}
+	eb, err := Parse(b)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse second SVG: %w", err)
+	}
+	return elementsEqual(ea, eb), nil
+}
+
+// elementsEqual compares two element trees after normalization.
+func elementsEqual(a, b *svgparser.Element) bool {
+	if a.Name != b.Name || len(a.Attributes) != len(b.Attributes) || len(a.Children) != len(b.Children) {
+		return false
+	}
+	if strings.Join(strings.Fields(a.Content), " ") != strings.Join(strings.Fields(b.Content), " ") {
+		return false
+	}
+	for name, va := range a.Attributes {
+		vb, ok := b.Attributes[name]
+		if !ok || normalizeAttrValue(name, va) != normalizeAttrValue(name, vb) {
+			return false
+		}
+	}
+	for i := range a.Children {
+		if !elementsEqual(a.Children[i], b.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeAttrValue returns a canonical form of an attribute value with
+// rounded numbers and single-space separators.
+func normalizeAttrValue(name, value string) string {
+	if name == "d" {
+		value = NormalizePathData(value)
+	}
+
+	scale := math.Pow10(equalPrecision)
+	var sb strings.Builder
+	last := 0
+	for _, loc := range equalNumberRe.FindAllStringIndex(value, -1) {
+		// Skip digits that are part of a name or hex color, e.g. #00ff00 or url(#g1)
+		if loc[0] > 0 && isNameByte(value[loc[0]-1]) {
+			continue
+		}
+		v, err := strconv.ParseFloat(value[loc[0]:loc[1]], 64)
+		if err != nil {
+			continue
+		}
+		sb.WriteString(value[last:loc[0]])
+		sb.WriteString(formatNumber(math.Round(v*scale) / scale))
+		last = loc[1]
+	}
+	sb.WriteString(value[last:])
+
+	return strings.Join(strings.Fields(strings.ReplaceAll(sb.String(), ",", " ")), " ")
+}
+
+// isNameByte reports whether c can precede digits within a name or hex
+// color rather than start a number.
+func isNameByte(c byte) bool {
+	return c == '#' || c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
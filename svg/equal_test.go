@@ -0,0 +1,50 @@
+package svg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	a := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" fill="#00ff00"/><text x="5" y="95">Brand Name</text></svg>`
+	b := `<?xml version="1.0"?>
+<!-- same icon, different formatting -->
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0,0,100.0,100">
+  <path fill="#00ff00" d="M10.000000001,10L90,90.0"/>
+  <text y="95.00" x="5">
+    Brand Name
+  </text>
+</svg>`
+
+	equal, err := Equal([]byte(a), []byte(b))
+	if err != nil {
+		t.Fatalf("Equal error: %v", err)
+	}
+	if !equal {
+		t.Error("Equal = false for icons differing only in formatting, want true")
+	}
+
+	tests := []struct {
+		name string
+		b    string
+	}{
+		{"color", `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" fill="#000ff0"/><text x="5" y="95">Brand Name</text></svg>`},
+		{"number", `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90.01" fill="#00ff00"/><text x="5" y="95">Brand Name</text></svg>`},
+		{"text", `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" fill="#00ff00"/><text x="5" y="95">Brand</text></svg>`},
+		{"extra attribute", `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" fill="#00ff00" stroke="none"/><text x="5" y="95">Brand Name</text></svg>`},
+		{"extra element", `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" fill="#00ff00"/><text x="5" y="95">Brand Name</text><g/></svg>`},
+	}
+	for _, tt := range tests {
+		equal, err := Equal([]byte(a), []byte(tt.b))
+		if err != nil {
+			t.Fatalf("%s: Equal error: %v", tt.name, err)
+		}
+		if equal {
+			t.Errorf("%s: Equal = true, want false", tt.name)
+		}
+	}
+
+	if _, err := Equal([]byte(a), []byte(" ")); !errors.Is(err, ErrEmptySVG) {
+		t.Errorf("error = %v, want ErrEmptySVG", err)
+	}
+}
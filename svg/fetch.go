@@ -0,0 +1,45 @@
+package svg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxFetchSize is the maximum response body size accepted by FetchSVG.
+const MaxFetchSize = 10 << 20 // 10 MiB
+
+// FetchSVG downloads an SVG from a URL and returns its decoded content.
+func FetchSVG(url string) ([]byte, error) {
+	return FetchSVGCtx(context.Background(), url)
+}
+
+// FetchSVGCtx downloads an SVG from a URL, aborting when ctx is cancelled
+// or its deadline passes. Responses larger than MaxFetchSize are rejected.
+func FetchSVGCtx(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, MaxFetchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(content) > MaxFetchSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", MaxFetchSize)
+	}
+
+	return Decode(content)
+}
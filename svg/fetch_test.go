@@ -0,0 +1,47 @@
+package svg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchSVG(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/icon.svg" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<svg viewBox="0 0 10 10"/>`))
+	}))
+	defer srv.Close()
+
+	content, err := FetchSVG(srv.URL + "/icon.svg")
+	if err != nil {
+		t.Fatalf("FetchSVG error: %v", err)
+	}
+	if string(content) != `<svg viewBox="0 0 10 10"/>` {
+		t.Errorf("content = %q", content)
+	}
+
+	if _, err := FetchSVG(srv.URL + "/missing.svg"); err == nil {
+		t.Error("expected error for 404 response")
+	}
+}
+
+func TestFetchSVGCtxCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<svg viewBox="0 0 10 10"/>`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := FetchSVGCtx(ctx, srv.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
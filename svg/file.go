@@ -1,6 +1,9 @@
 package svg
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -67,3 +70,80 @@ func ListSVGFilesRecursive(dirPath string) ([]string, error) {
 	}
 	return files, nil
 }
+
+// PathError records a path that could not be read while walking a
+// directory tree.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// WalkSVGFiles returns all SVG files in a directory tree. Unlike
+// ListSVGFilesRecursive, unreadable subdirectories do not abort the walk:
+// they are skipped and returned as path errors alongside the files found.
+// An error is returned only if the root directory itself cannot be read.
+func WalkSVGFiles(dirPath string) ([]string, []*PathError, error) {
+	return WalkSVGFilesContext(context.Background(), dirPath)
+}
+
+// WalkSVGFilesContext is like WalkSVGFiles but stops the walk and returns
+// ctx.Err() once ctx is cancelled.
+func WalkSVGFilesContext(ctx context.Context, dirPath string) ([]string, []*PathError, error) {
+	files, pathErrs, err := walkSVGFiles(ctx, os.DirFS(dirPath))
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, f := range files {
+		files[i] = filepath.Join(dirPath, filepath.FromSlash(f))
+	}
+	for _, pe := range pathErrs {
+		pe.Path = filepath.Join(dirPath, filepath.FromSlash(pe.Path))
+	}
+	return files, pathErrs, nil
+}
+
+func walkSVGFiles(ctx context.Context, fsys fs.FS) ([]string, []*PathError, error) {
+	var files []string
+	var pathErrs []*PathError
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == "." {
+				return err
+			}
+			pathErrs = append(pathErrs, &PathError{Path: path, Err: unwrapPathError(err)})
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && IsSVGFile(d.Name()) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, pathErrs, nil
+}
+
+// unwrapPathError strips an fs.PathError so the path is not repeated
+// when the error is wrapped in a PathError.
+func unwrapPathError(err error) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		return pe.Err
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package svg
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// unreadableDirFS is an fs.FS whose listed directories cannot be read.
+type unreadableDirFS struct {
+	fstest.MapFS
+	unreadable map[string]bool
+}
+
+func (f unreadableDirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if f.unreadable[name] {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestWalkSVGFilesPartial(t *testing.T) {
+	fsys := unreadableDirFS{
+		MapFS: fstest.MapFS{
+			"a.svg":          {Data: []byte("<svg/>")},
+			"locked/b.svg":   {Data: []byte("<svg/>")},
+			"nested/c.svg":   {Data: []byte("<svg/>")},
+			"nested/d.txt":   {Data: []byte("text")},
+			"nested/x/e.SVG": {Data: []byte("<svg/>")},
+		},
+		unreadable: map[string]bool{"locked": true},
+	}
+
+	files, pathErrs, err := walkSVGFiles(context.Background(), fsys)
+	if err != nil {
+		t.Fatalf("walkSVGFiles error: %v", err)
+	}
+
+	want := []string{"a.svg", "nested/c.svg", "nested/x/e.SVG"}
+	if len(files) != len(want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("files[%d] = %q, want %q", i, files[i], want[i])
+		}
+	}
+
+	if len(pathErrs) != 1 {
+		t.Fatalf("got %d path errors, want 1", len(pathErrs))
+	}
+	if pathErrs[0].Path != "locked" {
+		t.Errorf("Path = %q, want %q", pathErrs[0].Path, "locked")
+	}
+	if !errors.Is(pathErrs[0], fs.ErrPermission) {
+		t.Errorf("error = %v, want fs.ErrPermission", pathErrs[0])
+	}
+}
+
+func TestWalkSVGFilesMissingRoot(t *testing.T) {
+	if _, _, err := WalkSVGFiles("/nonexistent/dir"); err == nil {
+		t.Error("expected error for missing root directory")
+	}
+}
+
+// cancelingFS is an fs.FS that cancels a context after a number of
+// directory reads.
+type cancelingFS struct {
+	fstest.MapFS
+	cancel func()
+	after  int
+	reads  int
+}
+
+func (f *cancelingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f.reads++
+	if f.reads == f.after {
+		f.cancel()
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestWalkSVGFilesCancel(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	for _, dir := range []string{"a", "b", "c", "d", "e"} {
+		mapFS[dir+"/icon.svg"] = &fstest.MapFile{Data: []byte("<svg/>")}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fsys := &cancelingFS{MapFS: mapFS, cancel: cancel, after: 3}
+
+	files, _, err := walkSVGFiles(ctx, fsys)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if files != nil {
+		t.Errorf("expected nil files on cancellation, got %v", files)
+	}
+	if fsys.reads >= 6 {
+		t.Errorf("read %d directories, expected the walk to stop early", fsys.reads)
+	}
+}
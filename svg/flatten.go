@@ -0,0 +1,504 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// flattenPrecision is the number of fractional digits coordinates are
+// rounded to by FlattenTransforms, which hides floating-point noise such
+// as 9.999999999999998.
+const flattenPrecision = 9
+
+// FlattenTransforms bakes transform attributes into geometry, producing an
+// equivalent SVG whose shapes are in the root coordinate system. Transforms
+// are composed down the tree and applied to path, polygon, polyline, line,
+// rect, circle, and ellipse coordinates. Rects, circles, and ellipses are
+// converted to paths when the transform rotates or skews them; a circle
+// scaled non-uniformly becomes an ellipse. Stroke widths, including
+// inherited and default ones, are multiplied by the transform's average
+// scale.
+//
+// Elements that cannot be flattened keep their composed transform as a
+// matrix() attribute, with their subtree left as is. These are text, use,
+// image, and other non-shape elements, and elements that reference
+// resources laid out in user space: a clip-path, mask, or filter, or a
+// url() paint such as a gradient, set on the element or inherited. Contents
+// of defs, clipPath, mask, pattern, marker, and symbol elements are left
+// untouched. An invalid transform attribute is an error.
+func FlattenTransforms(content string) (string, error) {
+	doc, err := parseXMLDocument([]byte(content))
+	if err != nil {
+		return "", err
+	}
+	for _, n := range doc.children {
+		if n.kind == xmlElement {
+			width := 1.0
+			if w, ok := n.strokeWidth(); ok {
+				width = w
+			}
+			root := flattenState{ctm: IdentityMatrix(), strokeIn: width, strokeOut: width, urlPaint: n.referencesURLPaint()}
+			if err := flattenChildren(n, root); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, n := range doc.children {
+		switch n.kind {
+		case xmlProcInst:
+			buf.WriteString("<?" + n.name + " " + n.text + "?>")
+		case xmlDirective:
+			buf.WriteString("<!" + n.text + ">")
+		default:
+			writeInlineNode(&buf, n, PrettyOptions{})
+		}
+	}
+	return buf.String(), nil
+}
+
+// flattenState is the context an element is flattened in.
+type flattenState struct {
+	ctm       Matrix  // Maps the parent's user space to the root coordinate system
+	strokeIn  float64 // Stroke width inherited from the input, in the parent's user space
+	strokeOut float64 // Stroke width inherited in the output, after flattening
+	urlPaint  bool    // A url() paint is inherited, so geometry must keep its user space
+}
+
+// flattenChildren flattens the children of an element.
+func flattenChildren(parent *xmlNode, state flattenState) error {
+	for _, n := range parent.children {
+		if n.kind != xmlElement {
+			continue
+		}
+		switch n.name {
+		case "defs", "clipPath", "mask", "pattern", "marker", "symbol",
+			"linearGradient", "radialGradient", "filter", "style", "script",
+			"title", "desc", "metadata":
+			continue
+		}
+
+		m := state.ctm
+		if t, ok := n.attr("transform"); ok {
+			local, err := ParseTransform(t)
+			if err != nil {
+				return err
+			}
+			m = state.ctm.Multiply(local)
+		}
+
+		strokeIn, ownStroke := n.strokeWidth()
+		if !ownStroke {
+			strokeIn = state.strokeIn
+		}
+		urlPaint := state.urlPaint || n.referencesURLPaint()
+
+		keep := !m.isIdentity() && (urlPaint || n.referencesResources())
+		if keep || !flattenElement(n, m) {
+			// Keep the composed transform, which also scales the stroke, so
+			// the stroke width must not pick up a flattened parent's value
+			if m.isIdentity() {
+				n.removeAttr("transform")
+			} else {
+				n.setAttr("transform", m.String())
+			}
+			if !ownStroke && flatNumber(strokeIn) != flatNumber(state.strokeOut) {
+				n.setAttr("stroke-width", flatNumber(strokeIn))
+			}
+			continue
+		}
+		n.removeAttr("transform")
+
+		strokeOut := strokeIn * math.Sqrt(math.Abs(m.det()))
+		if ownStroke || flatNumber(strokeOut) != flatNumber(state.strokeOut) {
+			n.setAttr("stroke-width", flatNumber(strokeOut))
+		}
+		child := flattenState{ctm: m, strokeIn: strokeIn, strokeOut: strokeOut, urlPaint: urlPaint}
+		if err := flattenChildren(n, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// strokeWidth returns the element's stroke-width attribute as a number.
+// It returns false if the attribute is missing or not a plain number.
+func (n *xmlNode) strokeWidth() (float64, bool) {
+	w, ok := n.attr("stroke-width")
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(w), "px"), 64)
+	return v, err == nil
+}
+
+// referencesResources reports whether n is clipped, masked, or filtered.
+// These resources are laid out in the element's user space, so its
+// geometry cannot be moved out of it.
+func (n *xmlNode) referencesResources() bool {
+	for _, name := range []string{"clip-path", "mask", "filter"} {
+		if v, ok := n.styleProperty(name); ok && strings.TrimSpace(v) != "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesURLPaint reports whether n sets a url() fill or stroke, such
+// as a gradient, which is inherited by its descendants.
+func (n *xmlNode) referencesURLPaint() bool {
+	for _, name := range []string{"fill", "stroke"} {
+		if v, ok := n.styleProperty(name); ok && strings.Contains(v, "url(") {
+			return true
+		}
+	}
+	return false
+}
+
+// styleProperty returns a presentation attribute, or the property of the
+// same name in the style attribute.
+func (n *xmlNode) styleProperty(name string) (string, bool) {
+	if style, ok := n.attr("style"); ok {
+		for _, decl := range strings.Split(style, ";") {
+			if k, v, found := strings.Cut(decl, ":"); found && strings.TrimSpace(k) == name {
+				return strings.TrimSpace(v), true
+			}
+		}
+	}
+	return n.attr(name)
+}
+
+// flattenElement applies m to the geometry of n. It returns false for
+// elements whose geometry cannot be flattened.
+func flattenElement(n *xmlNode, m Matrix) bool {
+	switch n.name {
+	case "g", "a", "switch":
+		return true
+	case "path":
+		if d, ok := n.attr("d"); ok {
+			n.setAttr("d", transformPathData(d, m))
+		}
+		return true
+	case "polygon", "polyline":
+		if points, ok := n.attr("points"); ok {
+			nums := precisionNumberRe.FindAllString(points, -1)
+			var sb strings.Builder
+			for i := 0; i+1 < len(nums); i += 2 {
+				x, _ := strconv.ParseFloat(nums[i], 64)
+				y, _ := strconv.ParseFloat(nums[i+1], 64)
+				x, y = m.Apply(x, y)
+				if i > 0 {
+					sb.WriteByte(' ')
+				}
+				sb.WriteString(flatNumber(x) + "," + flatNumber(y))
+			}
+			n.setAttr("points", sb.String())
+		}
+		return true
+	}
+
+	lengths, ok := n.lengthAttrs()
+	if !ok {
+		return false
+	}
+	axisAligned := m.B == 0 && m.C == 0
+	switch n.name {
+	case "line":
+		x1, y1 := m.Apply(lengths["x1"], lengths["y1"])
+		x2, y2 := m.Apply(lengths["x2"], lengths["y2"])
+		n.setAttrs(map[string]float64{"x1": x1, "y1": y1, "x2": x2, "y2": y2})
+	case "rect":
+		x, y, w, h := lengths["x"], lengths["y"], lengths["width"], lengths["height"]
+		rx, ry := rectRadii(lengths)
+		if !axisAligned {
+			n.replaceWithPath(transformPathData(rectPathData(x, y, w, h, rx, ry), m), "x", "y", "width", "height", "rx", "ry")
+			return true
+		}
+		x0, y0 := m.Apply(x, y)
+		x1, y1 := m.Apply(x+w, y+h)
+		attrs := map[string]float64{"x": math.Min(x0, x1), "y": math.Min(y0, y1), "width": math.Abs(x1 - x0), "height": math.Abs(y1 - y0)}
+		if rx > 0 || ry > 0 {
+			attrs["rx"], attrs["ry"] = rx*math.Abs(m.A), ry*math.Abs(m.D)
+		}
+		n.setAttrs(attrs)
+	case "circle", "ellipse":
+		cx, cy := lengths["cx"], lengths["cy"]
+		rx, ry := lengths["rx"], lengths["ry"]
+		if n.name == "circle" {
+			rx, ry = lengths["r"], lengths["r"]
+		}
+		if !axisAligned {
+			n.replaceWithPath(transformPathData(ellipsePathData(cx, cy, rx, ry), m), "cx", "cy", "r", "rx", "ry")
+			return true
+		}
+		cx, cy = m.Apply(cx, cy)
+		rx, ry = rx*math.Abs(m.A), ry*math.Abs(m.D)
+		if n.name == "circle" && rx == ry {
+			n.setAttrs(map[string]float64{"cx": cx, "cy": cy, "r": rx})
+			return true
+		}
+		n.name = "ellipse"
+		n.removeAttr("r")
+		n.setAttrs(map[string]float64{"cx": cx, "cy": cy, "rx": rx, "ry": ry})
+	default:
+		return false
+	}
+	return true
+}
+
+// shapeLengthAttrs lists the geometry attributes of basic shapes.
+var shapeLengthAttrs = map[string][]string{
+	"line":    {"x1", "y1", "x2", "y2"},
+	"rect":    {"x", "y", "width", "height", "rx", "ry"},
+	"circle":  {"cx", "cy", "r"},
+	"ellipse": {"cx", "cy", "rx", "ry"},
+}
+
+// lengthAttrs returns the geometry attributes of a basic shape as numbers,
+// with missing attributes as 0. It returns false for other elements and
+// for lengths with units or percentages, which need a viewport to resolve.
+func (n *xmlNode) lengthAttrs() (map[string]float64, bool) {
+	names, ok := shapeLengthAttrs[n.name]
+	if !ok {
+		return nil, false
+	}
+	lengths := map[string]float64{}
+	for _, name := range names {
+		s, ok := n.attr(name)
+		if !ok || strings.TrimSpace(s) == "auto" {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "px"), 64)
+		if err != nil {
+			return nil, false
+		}
+		lengths[name] = v
+	}
+	return lengths, true
+}
+
+// rectRadii returns the corner radii of a rect, where a missing rx or ry
+// defaults to the other, clamped to half the width and height.
+func rectRadii(lengths map[string]float64) (float64, float64) {
+	rx, ry := lengths["rx"], lengths["ry"]
+	_, hasRx := lengths["rx"]
+	_, hasRy := lengths["ry"]
+	if !hasRx {
+		rx = ry
+	}
+	if !hasRy {
+		ry = rx
+	}
+	return math.Min(rx, lengths["width"]/2), math.Min(ry, lengths["height"]/2)
+}
+
+// rectPathData returns path data drawing a rect with optional rounded
+// corners.
+func rectPathData(x, y, w, h, rx, ry float64) string {
+	f := formatNumber
+	if rx <= 0 || ry <= 0 {
+		return fmt.Sprintf("M %s %s H %s V %s H %s Z", f(x), f(y), f(x+w), f(y+h), f(x))
+	}
+	arc := func(ex, ey float64) string {
+		return fmt.Sprintf(" A %s %s 0 0 1 %s %s", f(rx), f(ry), f(ex), f(ey))
+	}
+	return fmt.Sprintf("M %s %s H %s", f(x+rx), f(y), f(x+w-rx)) + arc(x+w, y+ry) +
+		fmt.Sprintf(" V %s", f(y+h-ry)) + arc(x+w-rx, y+h) +
+		fmt.Sprintf(" H %s", f(x+rx)) + arc(x, y+h-ry) +
+		fmt.Sprintf(" V %s", f(y+ry)) + arc(x+rx, y) + " Z"
+}
+
+// ellipsePathData returns path data drawing an ellipse as two arcs.
+func ellipsePathData(cx, cy, rx, ry float64) string {
+	f := formatNumber
+	return fmt.Sprintf("M %s %s A %s %s 0 1 0 %s %s A %s %s 0 1 0 %s %s Z",
+		f(cx-rx), f(cy), f(rx), f(ry), f(cx+rx), f(cy), f(rx), f(ry), f(cx-rx), f(cy))
+}
+
+// transformPathData applies m to path data, returning absolute commands.
+// H and V become L, since a rotation no longer keeps them axis-aligned,
+// and arcs have their radii, rotation, and sweep adjusted.
+func transformPathData(d string, m Matrix) string {
+	var out []PathCommand
+	var curX, curY, startX, startY float64
+	emit := func(cmd byte, points ...float64) {
+		params := make([]float64, 0, len(points))
+		for i := 0; i+1 < len(points); i += 2 {
+			x, y := m.Apply(points[i], points[i+1])
+			params = append(params, roundFlat(x), roundFlat(y))
+		}
+		out = append(out, PathCommand{Command: cmd, Params: params})
+	}
+
+	for _, cmd := range ParsePath(d) {
+		arity := pathCommandArity(cmd.Command)
+		relative := cmd.Command >= 'a' && cmd.Command <= 'z'
+		if arity == 0 {
+			out = append(out, PathCommand{Command: 'Z'})
+			curX, curY = startX, startY
+			continue
+		}
+		for i := 0; arity > 0 && i+arity <= len(cmd.Params); i += arity {
+			p := cmd.Params[i : i+arity]
+			ox, oy := 0.0, 0.0
+			if relative {
+				ox, oy = curX, curY
+			}
+			switch cmd.Command {
+			case 'M', 'm':
+				curX, curY = ox+p[0], oy+p[1]
+				if i == 0 {
+					startX, startY = curX, curY
+					emit('M', curX, curY)
+				} else {
+					emit('L', curX, curY)
+				}
+			case 'L', 'l', 'T', 't':
+				curX, curY = ox+p[0], oy+p[1]
+				emit(upperCommand(cmd.Command), curX, curY)
+			case 'H', 'h':
+				curX = ox + p[0]
+				emit('L', curX, curY)
+			case 'V', 'v':
+				curY = oy + p[0]
+				emit('L', curX, curY)
+			case 'C', 'c':
+				emit('C', ox+p[0], oy+p[1], ox+p[2], oy+p[3], ox+p[4], oy+p[5])
+				curX, curY = ox+p[4], oy+p[5]
+			case 'S', 's', 'Q', 'q':
+				emit(upperCommand(cmd.Command), ox+p[0], oy+p[1], ox+p[2], oy+p[3])
+				curX, curY = ox+p[2], oy+p[3]
+			case 'A', 'a':
+				curX, curY = ox+p[5], oy+p[6]
+				out = append(out, transformArc(p, curX, curY, m))
+			}
+		}
+	}
+	return PathCommandsToString(out)
+}
+
+// transformArc applies m to an arc segment with parameters p ending at
+// (x, y). The arc's ellipse is mapped by m and decomposed back into radii
+// and a rotation; a reflecting transform reverses the sweep direction.
+func transformArc(p []float64, x, y float64, m Matrix) PathCommand {
+	ex, ey := m.Apply(x, y)
+	rx, ry := math.Abs(p[0]), math.Abs(p[1])
+	if rx == 0 || ry == 0 {
+		return PathCommand{Command: 'L', Params: []float64{roundFlat(ex), roundFlat(ey)}}
+	}
+
+	// Columns of the transformed ellipse matrix m * rotate(phi) * scale(rx, ry)
+	phi := p[2] * math.Pi / 180
+	cos, sin := math.Cos(phi), math.Sin(phi)
+	ax, ay := m.A*rx*cos+m.C*rx*sin, m.B*rx*cos+m.D*rx*sin
+	bx, by := -m.A*ry*sin+m.C*ry*cos, -m.B*ry*sin+m.D*ry*cos
+
+	// Its radii and rotation follow from the eigen decomposition of N*Nᵀ
+	pp := ax*ax + bx*bx
+	rr := ay*ay + by*by
+	qq := ax*ay + bx*by
+	mean := (pp + rr) / 2
+	spread := math.Hypot((pp-rr)/2, qq)
+	newRx := math.Sqrt(mean + spread)
+	newRy := math.Sqrt(math.Max(mean-spread, 0))
+	newPhi := math.Atan2(2*qq, pp-rr) / 2 * 180 / math.Pi
+
+	sweep := p[4]
+	if m.det() < 0 {
+		sweep = 1 - sweep
+	}
+	return PathCommand{Command: 'A', Params: []float64{
+		roundFlat(newRx), roundFlat(newRy), roundFlat(newPhi), p[3], sweep, roundFlat(ex), roundFlat(ey),
+	}}
+}
+
+// upperCommand returns the absolute form of a path command letter.
+func upperCommand(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// roundFlat rounds v to flattenPrecision fractional digits.
+func roundFlat(v float64) float64 {
+	scale := math.Pow10(flattenPrecision)
+	return math.Round(v*scale) / scale
+}
+
+// flatNumber formats v rounded to flattenPrecision fractional digits.
+func flatNumber(v float64) string {
+	return formatNumber(roundFlat(v))
+}
+
+// isIdentity reports whether m leaves points unchanged.
+func (m Matrix) isIdentity() bool {
+	return m == IdentityMatrix()
+}
+
+// det returns the determinant of the linear part of m.
+func (m Matrix) det() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// String returns m as an SVG matrix() transform.
+func (m Matrix) String() string {
+	return "matrix(" + strings.Join([]string{
+		flatNumber(m.A), flatNumber(m.B), flatNumber(m.C), flatNumber(m.D), flatNumber(m.E), flatNumber(m.F),
+	}, " ") + ")"
+}
+
+// attr returns the value of an unprefixed attribute.
+func (n *xmlNode) attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// setAttr sets an unprefixed attribute, appending it if missing.
+func (n *xmlNode) setAttr(name, value string) {
+	for i, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			n.attrs[i].Value = value
+			return
+		}
+	}
+	n.attrs = append(n.attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+// setAttrs sets numeric attributes in a stable order.
+func (n *xmlNode) setAttrs(values map[string]float64) {
+	for _, name := range []string{"x", "y", "x1", "y1", "x2", "y2", "cx", "cy", "r", "rx", "ry", "width", "height"} {
+		if v, ok := values[name]; ok {
+			n.setAttr(name, flatNumber(v))
+		}
+	}
+}
+
+// removeAttr removes an unprefixed attribute.
+func (n *xmlNode) removeAttr(name string) {
+	for i, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			n.attrs = append(n.attrs[:i], n.attrs[i+1:]...)
+			return
+		}
+	}
+}
+
+// replaceWithPath turns a basic shape into a path with data d, removing
+// its geometry attributes.
+func (n *xmlNode) replaceWithPath(d string, geometry ...string) {
+	for _, name := range geometry {
+		n.removeAttr(name)
+	}
+	n.name = "path"
+	n.setAttr("d", d)
+}
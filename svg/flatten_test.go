@@ -0,0 +1,143 @@
+package svg
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// flattenBounds returns the content bounds of an SVG document.
+func flattenBounds(t *testing.T, content string) *BoundingBox {
+	t.Helper()
+	root, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	return ContentBounds(root)
+}
+
+func assertBoundsClose(t *testing.T, got, want *BoundingBox) {
+	t.Helper()
+	const tolerance = 1e-6
+	if math.Abs(got.MinX-want.MinX) > tolerance || math.Abs(got.MinY-want.MinY) > tolerance ||
+		math.Abs(got.MaxX-want.MaxX) > tolerance || math.Abs(got.MaxY-want.MaxY) > tolerance {
+		t.Errorf("bounds after flattening = %+v, want %+v", *got, *want)
+	}
+}
+
+func TestFlattenTransformsRotatedGroup(t *testing.T) {
+	content := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100">
+  <g transform="rotate(45 50 50)" stroke-width="2">
+    <rect x="30" y="30" width="40" height="20"/>
+    <path d="m 20 20 h 10 v 10 l -5 5 z"/>
+    <g transform="translate(5 0)">
+      <polygon points="60,60 80,60 70,75"/>
+      <line x1="10" y1="80" x2="40" y2="90"/>
+    </g>
+  </g>
+</svg>`
+
+	got, err := FlattenTransforms(content)
+	if err != nil {
+		t.Fatalf("FlattenTransforms error: %v", err)
+	}
+	if strings.Contains(got, "transform=") {
+		t.Errorf("transform attribute remains:\n%s", got)
+	}
+	if !strings.Contains(got, `<path d="M`) || strings.Contains(got, "<rect") {
+		t.Errorf("rotated rect not converted to a path:\n%s", got)
+	}
+	if !strings.Contains(got, `stroke-width="2"`) {
+		t.Errorf("stroke-width changed by a rotation:\n%s", got)
+	}
+	assertBoundsClose(t, flattenBounds(t, got), flattenBounds(t, content))
+}
+
+func TestFlattenTransformsScale(t *testing.T) {
+	content := `<svg viewBox="0 0 200 200"><g transform="translate(10 20) scale(2 3)" stroke-width="1"><circle cx="10" cy="10" r="5"/><rect x="0" y="0" width="10" height="10" rx="2"/></g></svg>`
+
+	got, err := FlattenTransforms(content)
+	if err != nil {
+		t.Fatalf("FlattenTransforms error: %v", err)
+	}
+	want := `<svg viewBox="0 0 200 200"><g stroke-width="2.449489743"><ellipse cx="30" cy="50" rx="10" ry="15"/><rect x="10" y="20" width="20" height="30" rx="4" ry="6"/></g></svg>`
+	if got != want {
+		t.Errorf("FlattenTransforms =\n%s\nwant\n%s", got, want)
+	}
+	assertBoundsClose(t, flattenBounds(t, got), flattenBounds(t, content))
+}
+
+func TestFlattenTransformsRotatedCircle(t *testing.T) {
+	got, err := FlattenTransforms(`<svg><circle cx="50" cy="50" r="10" transform="rotate(90 50 50)"/></svg>`)
+	if err != nil {
+		t.Fatalf("FlattenTransforms error: %v", err)
+	}
+	want := `<svg><path d="M 50 40 A 10 10 0 1 0 50 60 A 10 10 0 1 0 50 40 Z"/></svg>`
+	if got != want {
+		t.Errorf("FlattenTransforms =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTransformPathDataArc(t *testing.T) {
+	// Mirroring reverses the sweep; rotating a non-circular arc turns its axes
+	got := transformPathData("M 0 0 A 20 10 0 0 1 40 0", Matrix{A: -1, D: 1})
+	if want := "M 0 0 A 20 10 0 0 0 -40 0"; got != want {
+		t.Errorf("mirrored arc = %q, want %q", got, want)
+	}
+	got = transformPathData("M 0 0 A 20 10 0 0 1 40 0", Matrix{A: 0, B: 1, C: -1, D: 0})
+	if want := "M 0 0 A 20 10 90 0 1 0 40"; got != want {
+		t.Errorf("rotated arc = %q, want %q", got, want)
+	}
+}
+
+func TestFlattenTransformsKeepsOpaqueElements(t *testing.T) {
+	got, err := FlattenTransforms(`<svg><g transform="translate(10 0)"><text x="0" y="10" transform="scale(2)">Hi</text></g></svg>`)
+	if err != nil {
+		t.Fatalf("FlattenTransforms error: %v", err)
+	}
+	want := `<svg><g><text x="0" y="10" transform="matrix(2 0 0 2 10 0)">Hi</text></g></svg>`
+	if got != want {
+		t.Errorf("FlattenTransforms =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFlattenTransformsKeepsClippedGroup(t *testing.T) {
+	content := `<svg viewBox="0 0 200 100"><defs><clipPath id="c"><rect width="50" height="50"/></clipPath></defs>` +
+		`<g transform="translate(100,0)"><g clip-path="url(#c)"><rect width="50" height="50" transform="scale(2)"/></g>` +
+		`<path d="M 0 0 L 10 10" style="fill:url(#grad)"/></g></svg>`
+
+	got, err := FlattenTransforms(content)
+	if err != nil {
+		t.Fatalf("FlattenTransforms error: %v", err)
+	}
+	want := `<svg viewBox="0 0 200 100"><defs><clipPath id="c"><rect width="50" height="50"/></clipPath></defs>` +
+		`<g><g clip-path="url(#c)" transform="matrix(1 0 0 1 100 0)"><rect width="50" height="50" transform="scale(2)"/></g>` +
+		`<path d="M 0 0 L 10 10" style="fill:url(#grad)" transform="matrix(1 0 0 1 100 0)"/></g></svg>`
+	if got != want {
+		t.Errorf("FlattenTransforms =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFlattenTransformsInheritedStroke(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100" stroke="#000"><g transform="scale(2)">` +
+		`<path d="M 0 0 L 10 10"/><g transform="scale(0.5)"><path d="M 0 0 L 5 5"/></g><text>A</text></g></svg>`
+
+	got, err := FlattenTransforms(content)
+	if err != nil {
+		t.Fatalf("FlattenTransforms error: %v", err)
+	}
+	// The default width of 1 doubles under scale(2) and is restored under
+	// the nested scale(0.5); text keeps its transform and so its width
+	want := `<svg viewBox="0 0 100 100" stroke="#000"><g stroke-width="2">` +
+		`<path d="M 0 0 L 20 20"/><g stroke-width="1"><path d="M 0 0 L 5 5"/></g>` +
+		`<text transform="matrix(2 0 0 2 0 0)" stroke-width="1">A</text></g></svg>`
+	if got != want {
+		t.Errorf("FlattenTransforms =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFlattenTransformsInvalid(t *testing.T) {
+	if _, err := FlattenTransforms(`<svg><path d="M0 0L1 1" transform="rotate(oops)"/></svg>`); err == nil {
+		t.Error("expected error for invalid transform")
+	}
+}
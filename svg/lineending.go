@@ -0,0 +1,42 @@
+package svg
+
+import "bytes"
+
+// LineEndings describes the line-ending style of a file.
+type LineEndings struct {
+	CRLF            bool // All line breaks are CRLF
+	TrailingNewline bool // Content ends with a line break
+}
+
+// DetectLineEndings returns the line-ending style of content. Content with
+// mixed line endings is reported as LF so that Apply leaves it unchanged.
+func DetectLineEndings(content []byte) LineEndings {
+	lf := bytes.Count(content, []byte("\n"))
+	crlf := bytes.Count(content, []byte("\r\n"))
+	return LineEndings{
+		CRLF:            crlf > 0 && crlf == lf,
+		TrailingNewline: bytes.HasSuffix(content, []byte("\n")),
+	}
+}
+
+// Apply rewrites content to use the line-ending style, converting LF line
+// breaks to CRLF if needed and adding or removing the trailing newline.
+func (le LineEndings) Apply(content []byte) []byte {
+	if le.CRLF {
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+		content = bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n"))
+	}
+
+	hasTrailing := bytes.HasSuffix(content, []byte("\n"))
+	switch {
+	case le.TrailingNewline && !hasTrailing:
+		if le.CRLF {
+			content = append(content, '\r', '\n')
+		} else {
+			content = append(content, '\n')
+		}
+	case !le.TrailingNewline && hasTrailing:
+		content = bytes.TrimRight(content, "\r\n")
+	}
+	return content
+}
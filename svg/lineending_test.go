@@ -0,0 +1,41 @@
+package svg
+
+import "testing"
+
+func TestDetectLineEndings(t *testing.T) {
+	tests := []struct {
+		content string
+		want    LineEndings
+	}{
+		{"<svg>\n</svg>\n", LineEndings{CRLF: false, TrailingNewline: true}},
+		{"<svg>\r\n</svg>\r\n", LineEndings{CRLF: true, TrailingNewline: true}},
+		{"<svg>\r\n</svg>", LineEndings{CRLF: true, TrailingNewline: false}},
+		{"<svg>\r\n<g/>\n</svg>", LineEndings{CRLF: false, TrailingNewline: false}}, // mixed
+		{"<svg/>", LineEndings{}},
+	}
+
+	for _, tt := range tests {
+		if got := DetectLineEndings([]byte(tt.content)); got != tt.want {
+			t.Errorf("DetectLineEndings(%q) = %+v, want %+v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestLineEndingsApply(t *testing.T) {
+	tests := []struct {
+		le      LineEndings
+		content string
+		want    string
+	}{
+		{LineEndings{CRLF: true, TrailingNewline: true}, "<svg>\n<g/>\r\n</svg>", "<svg>\r\n<g/>\r\n</svg>\r\n"},
+		{LineEndings{CRLF: false, TrailingNewline: true}, "<svg>\n</svg>", "<svg>\n</svg>\n"},
+		{LineEndings{CRLF: false, TrailingNewline: false}, "<svg>\n</svg>\n\n", "<svg>\n</svg>"},
+		{LineEndings{CRLF: true, TrailingNewline: false}, "<svg>\r\n</svg>\r\n", "<svg>\r\n</svg>"},
+	}
+
+	for _, tt := range tests {
+		if got := string(tt.le.Apply([]byte(tt.content))); got != tt.want {
+			t.Errorf("%+v.Apply(%q) = %q, want %q", tt.le, tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package lint provides repository health checks for SVG files.
+package lint
+
+import (
+	"fmt"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// DefaultMaxPrecision is the default number of fractional digits allowed in
+// coordinates. Icons rarely need more than this at typical display sizes.
+const DefaultMaxPrecision = 3
+
+// Options configures lint checks.
+type Options struct {
+	MaxPrecision int // Maximum fractional digits allowed in coordinates
+}
+
+// DefaultOptions returns the default lint options.
+func DefaultOptions() Options {
+	return Options{
+		MaxPrecision: DefaultMaxPrecision,
+	}
+}
+
+// Result contains the lint findings for an SVG file.
+type Result struct {
+	FilePath           string
+	ExcessivePrecision bool     // True if any coordinate exceeds Options.MaxPrecision
+	PrecisionCount     int      // Number of coordinates exceeding Options.MaxPrecision
+	MaxPrecision       int      // Largest number of fractional digits found
+	Warnings           []string // Human-readable findings
+}
+
+// HasWarnings returns true if any check failed.
+func (r *Result) HasWarnings() bool {
+	return len(r.Warnings) > 0
+}
+
+// SVG lints an SVG file.
+func SVG(filePath string, opts Options) (*Result, error) {
+	content, err := svg.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	result := Content(content, opts)
+	result.FilePath = filePath
+	return result, nil
+}
+
+// Content lints SVG content in memory.
+func Content(content []byte, opts Options) *Result {
+	result := &Result{
+		MaxPrecision:   svg.MaxCoordinatePrecision(content),
+		PrecisionCount: svg.CountExcessivePrecision(content, opts.MaxPrecision),
+	}
+	if result.PrecisionCount > 0 {
+		result.ExcessivePrecision = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"excessive precision: %d numbers with more than %d decimal places (max %d)",
+			result.PrecisionCount, opts.MaxPrecision, result.MaxPrecision))
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package lint
+
+import (
+	"testing"
+)
+
+func TestContentExcessivePrecision(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 24 24"><path d="M 1.123456 2.5 L 10.98765432 3"/><circle cx="12" cy="12" r="4.25"/></svg>`)
+
+	result := Content(content, DefaultOptions())
+	if !result.ExcessivePrecision {
+		t.Error("expected ExcessivePrecision")
+	}
+	if result.PrecisionCount != 2 {
+		t.Errorf("PrecisionCount = %d, want 2", result.PrecisionCount)
+	}
+	if result.MaxPrecision != 8 {
+		t.Errorf("MaxPrecision = %d, want 8", result.MaxPrecision)
+	}
+
+	// A looser threshold passes the same icon
+	if result := Content(content, Options{MaxPrecision: 8}); result.HasWarnings() {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+}
+
+func TestContentClean(t *testing.T) {
+	result := Content([]byte(`<svg viewBox="0 0 24 24"><path d="M 1.5 2.25 L 10 3.125"/></svg>`), DefaultOptions())
+	if result.ExcessivePrecision || result.HasWarnings() {
+		t.Errorf("clean icon flagged: %+v", result)
+	}
+}
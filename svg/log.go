@@ -0,0 +1,15 @@
+package svg
+
+import "log/slog"
+
+// discardLogger drops all records.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// LoggerOrDiscard returns l, or a logger that discards all records if l
+// is nil. Options structs accept a nil Logger to disable logging.
+func LoggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return discardLogger
+	}
+	return l
+}
@@ -0,0 +1,144 @@
+// Package minify reduces the size of SVG files without changing how they
+// render.
+package minify
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grokify/mogo/os/osutil"
+
+	"github.com/grokify/brandkit/svg"
+	"github.com/grokify/brandkit/svg/optimize"
+)
+
+// MinifyOptions configures the minification steps to apply.
+type MinifyOptions struct {
+	StripComments       bool // Remove XML comments
+	CollapseWhitespace  bool // Remove insignificant whitespace between tags; <text> content is untouched
+	StripXMLDeclaration bool // Remove the <?xml?> declaration when it declares no encoding other than UTF-8
+	TrimNumbers         bool // Trim trailing zeros in numeric attributes, e.g. 1.500 to 1.5
+}
+
+// DefaultOptions returns options that apply all minification steps.
+func DefaultOptions() MinifyOptions {
+	return MinifyOptions{
+		StripComments:       true,
+		CollapseWhitespace:  true,
+		StripXMLDeclaration: true,
+		TrimNumbers:         true,
+	}
+}
+
+// Result contains the result of minifying an SVG file.
+type Result struct {
+	InputPath    string
+	OutputPath   string
+	OriginalSize int // Size of the input in bytes, after decompression
+	MinifiedSize int // Size of the output in bytes
+	Error        error
+}
+
+var (
+	startTagRe     = regexp.MustCompile(`<[A-Za-z][^<>]*>`)
+	numericAttrRe  = regexp.MustCompile(`(\s(?:` + strings.Join(numericAttrs, "|") + `)\s*=\s*)("[^"]*"|'[^']*')`)
+	fractionNumRe  = regexp.MustCompile(`\d*\.\d+`)
+	trailingZeroRe = regexp.MustCompile(`\.?0+$`)
+)
+
+// numericAttrs lists attributes whose values are numbers, lengths, or
+// number lists, where trailing zeros can be trimmed safely.
+var numericAttrs = []string{
+	"d", "points", "viewBox", "transform",
+	"x", "y", "x1", "y1", "x2", "y2", "dx", "dy",
+	"cx", "cy", "r", "rx", "ry", "fx", "fy",
+	"width", "height", "offset",
+	"opacity", "fill-opacity", "stroke-opacity", "stop-opacity",
+	"stroke-width", "stroke-miterlimit", "stroke-dashoffset", "stroke-dasharray",
+	"font-size",
+}
+
+// Minify returns content with the steps in opts applied. It returns
+// svg.ErrEmptySVG if content has no SVG markup.
+func Minify(content string, opts MinifyOptions) (string, error) {
+	if err := svg.CheckEmpty([]byte(content)); err != nil {
+		return "", err
+	}
+	content = optimize.Content(content, optimize.Options{
+		StripComments:      opts.StripComments,
+		CollapseWhitespace: opts.CollapseWhitespace,
+	})
+	if opts.StripXMLDeclaration {
+		content = string(svg.StripXMLDeclaration([]byte(content)))
+	}
+	if opts.TrimNumbers {
+		content = trimNumbers(content)
+	}
+	return content, nil
+}
+
+// MinifyFile minifies an SVG file and writes the result to outputPath.
+// Compressed (.svgz) input is decompressed; the output is uncompressed.
+func MinifyFile(inputPath, outputPath string, opts MinifyOptions) (*Result, error) {
+	result := &Result{
+		InputPath:  inputPath,
+		OutputPath: outputPath,
+	}
+
+	content, err := svg.ReadFile(inputPath)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return result, result.Error
+	}
+	result.OriginalSize = len(content)
+
+	minified, err := Minify(string(content), opts)
+	if err != nil {
+		result.Error = err
+		return result, err
+	}
+	result.MinifiedSize = len(minified)
+
+	if err := osutil.WriteFileSecure(outputPath, []byte(minified), 0600); err != nil {
+		result.Error = fmt.Errorf("failed to write file: %w", err)
+		return result, result.Error
+	}
+
+	return result, nil
+}
+
+// trimNumbers trims trailing fractional zeros from numbers in numeric
+// attributes, e.g. 1.500 to 1.5 and 2.0 to 2.
+func trimNumbers(content string) string {
+	return startTagRe.ReplaceAllStringFunc(content, func(tag string) string {
+		return numericAttrRe.ReplaceAllStringFunc(tag, func(attr string) string {
+			m := numericAttrRe.FindStringSubmatch(attr)
+			quote := m[2][:1]
+			value := m[2][1 : len(m[2])-1]
+			return m[1] + quote + trimValueNumbers(value) + quote
+		})
+	})
+}
+
+// trimValueNumbers trims trailing zeros from each decimal number in value.
+// When a number loses its decimal point and the next number starts with
+// one, as in the path data "1.0.5", a space is inserted to keep them apart.
+func trimValueNumbers(value string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range fractionNumRe.FindAllStringIndex(value, -1) {
+		num := trailingZeroRe.ReplaceAllString(value[loc[0]:loc[1]], "")
+		if num == "" {
+			num = "0"
+		}
+		sb.WriteString(value[last:loc[0]])
+		sb.WriteString(num)
+		if !strings.Contains(num, ".") && loc[1] < len(value) && value[loc[1]] == '.' {
+			sb.WriteString(" ")
+		}
+		last = loc[1]
+	}
+	sb.WriteString(value[last:])
+	return sb.String()
+}
@@ -0,0 +1,105 @@
+package minify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/brandkit/svg"
+	"github.com/grokify/brandkit/svg/verify"
+)
+
+const testIcon = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generated by a design tool -->
+<svg viewBox="0.0 0.0 100.0 100.0" xmlns="http://www.w3.org/2000/svg">
+  <g transform="translate(10.500, 0)">
+    <path d="M 10.000 10.0 L 90.50 90.0" stroke-width="2.0"/>
+    <circle cx="50.0" cy="50" r="25.250"/>
+  </g>
+  <text x="5.0" y="95">Brand  Name</text>
+</svg>
+`
+
+func TestMinify(t *testing.T) {
+	got, err := Minify(testIcon, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Minify error: %v", err)
+	}
+
+	want := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><g transform="translate(10.5, 0)"><path d="M 10 10 L 90.5 90" stroke-width="2"/><circle cx="50" cy="50" r="25.25"/></g><text x="5" y="95">Brand  Name</text></svg>`
+	if got != want {
+		t.Errorf("Minify =\n%s\nwant\n%s", got, want)
+	}
+	if len(got) >= len(testIcon) {
+		t.Errorf("minified size %d not smaller than original %d", len(got), len(testIcon))
+	}
+	if _, err := svg.Parse([]byte(got)); err != nil {
+		t.Errorf("minified SVG does not parse: %v", err)
+	}
+}
+
+func TestMinifyKeepsNonUTF8Declaration(t *testing.T) {
+	content := `<?xml version="1.0" encoding="ISO-8859-1"?><svg xmlns="http://www.w3.org/2000/svg"/>`
+	got, err := Minify(content, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Minify error: %v", err)
+	}
+	if !strings.HasPrefix(got, `<?xml version="1.0" encoding="ISO-8859-1"?>`) {
+		t.Errorf("non-UTF-8 declaration removed: %s", got)
+	}
+}
+
+func TestMinifyEmpty(t *testing.T) {
+	if _, err := Minify("  ", DefaultOptions()); !errors.Is(err, svg.ErrEmptySVG) {
+		t.Errorf("error = %v, want svg.ErrEmptySVG", err)
+	}
+}
+
+func TestTrimValueNumbers(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.500", "1.5"},
+		{"10.00", "10"},
+		{".0", "0"},
+		{"-0.0", "-0"},
+		{"1.0500", "1.05"},
+		{"M1.0.5", "M1 .5"},
+		{"1.50e3", "1.5e3"},
+		{"100", "100"},
+		{"100.0%", "100%"},
+	}
+	for _, tt := range tests {
+		if got := trimValueNumbers(tt.in); got != tt.want {
+			t.Errorf("trimValueNumbers(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMinifyFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+	if err := os.WriteFile(input, []byte(testIcon), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MinifyFile(input, output, DefaultOptions())
+	if err != nil {
+		t.Fatalf("MinifyFile error: %v", err)
+	}
+	if result.MinifiedSize >= result.OriginalSize {
+		t.Errorf("MinifiedSize = %d, want less than OriginalSize %d", result.MinifiedSize, result.OriginalSize)
+	}
+
+	verified, err := verify.SVG(output)
+	if err != nil {
+		t.Fatalf("verify error: %v", err)
+	}
+	if !verified.IsSuccess() {
+		t.Errorf("minified SVG does not verify as pure vector: %v", verified.Errors)
+	}
+}
@@ -0,0 +1,111 @@
+package optimize
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// editorNamespacePrefixes are URI prefixes of namespaces written by design
+// tools (Inkscape, Illustrator, Sketch) and their RDF metadata. Elements
+// and attributes in these namespaces do not affect rendering.
+var editorNamespacePrefixes = []string{
+	"http://sodipodi.sourceforge.net/",
+	"http://www.inkscape.org/namespaces/",
+	"http://ns.adobe.com/",
+	"adobe:ns:meta/",
+	"http://www.bohemiancoding.com/sketch/ns",
+	"http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"http://creativecommons.org/ns#",
+	"http://purl.org/dc/elements/1.1/",
+}
+
+// defaultEditorPrefixes are stripped even when undeclared, since some
+// exports use them without a namespace declaration.
+var defaultEditorPrefixes = []string{"sodipodi", "inkscape"}
+
+var (
+	metadataElementRe = regexp.MustCompile(`(?s)<metadata\b[^>]*/>|<metadata\b[^>]*>.*?</metadata\s*>`)
+	xmlnsPrefixRe     = regexp.MustCompile(`\sxmlns:([\w.-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// StripEditorData removes <metadata> elements and the elements, attributes,
+// and namespace declarations of known design-tool namespaces such as
+// sodipodi:, inkscape:, and Adobe's. Returns the updated content and the
+// number of items removed.
+func StripEditorData(content string) (string, int) {
+	removed := 0
+
+	content = metadataElementRe.ReplaceAllStringFunc(content, func(string) string {
+		removed++
+		return ""
+	})
+
+	prefixes := map[string]bool{}
+	for _, p := range defaultEditorPrefixes {
+		prefixes[p] = true
+	}
+	for _, m := range xmlnsPrefixRe.FindAllStringSubmatch(content, -1) {
+		if isEditorNamespace(m[2] + m[3]) {
+			prefixes[m[1]] = true
+		}
+	}
+
+	names := make([]string, 0, len(prefixes))
+	for p := range prefixes {
+		names = append(names, regexp.QuoteMeta(p))
+	}
+	sort.Strings(names)
+	alternation := strings.Join(names, "|")
+
+	var n int
+	content, n = removePrefixedElements(content, alternation)
+	removed += n
+
+	// Only xmlns:<prefix> and <prefix>:<local> match; a plain attribute such
+	// as x or d shares its name with a prefix but is not in the namespace.
+	attrRe := regexp.MustCompile(`\s(?:xmlns:(?:` + alternation + `)|(?:` + alternation + `):[\w.-]+)\s*=\s*(?:"[^"]*"|'[^']*')`)
+	content = startTagRe.ReplaceAllStringFunc(content, func(tag string) string {
+		return attrRe.ReplaceAllStringFunc(tag, func(string) string {
+			removed++
+			return ""
+		})
+	})
+
+	return content, removed
+}
+
+// isEditorNamespace reports whether uri is a design-tool namespace.
+func isEditorNamespace(uri string) bool {
+	for _, p := range editorNamespacePrefixes {
+		if strings.HasPrefix(uri, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// removePrefixedElements removes elements whose prefix matches the
+// alternation of prefixes, including their content. Returns the updated
+// content and the number of (outermost) elements removed.
+func removePrefixedElements(content, alternation string) (string, int) {
+	startRe := regexp.MustCompile(`<((?:` + alternation + `):[\w.-]+)\b[^>]*?(/?)>`)
+	removed := 0
+	for {
+		loc := startRe.FindStringSubmatchIndex(content)
+		if loc == nil {
+			return content, removed
+		}
+		end := loc[1]
+		if loc[5] == loc[4] { // not self-closing
+			closeTag := "</" + content[loc[2]:loc[3]] + ">"
+			i := strings.Index(content[loc[1]:], closeTag)
+			if i < 0 {
+				return content, removed // malformed; leave the rest untouched
+			}
+			end = loc[1] + i + len(closeTag)
+		}
+		content = content[:loc[0]] + content[end:]
+		removed++
+	}
+}
@@ -0,0 +1,49 @@
+package optimize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// xmlnsAttrRe matches a namespace declaration attribute.
+var xmlnsAttrRe = regexp.MustCompile(`\s+(xmlns(?::[\w.-]+)?)\s*=\s*("[^"]*"|'[^']*')`)
+
+// DedupeNamespaces removes duplicate xmlns and xmlns:* declarations from
+// the root <svg> element, keeping the first of each, and removes
+// declarations on descendants that repeat the value declared on the root.
+// Returns the updated content and the number of declarations removed.
+func DedupeNamespaces(content string) (string, int) {
+	removed := 0
+	var rootNS map[string]string
+
+	content = startTagRe.ReplaceAllStringFunc(content, func(tag string) string {
+		isRoot := rootNS == nil && strings.HasPrefix(tag, "<svg")
+		seen := map[string]bool{}
+		tag = xmlnsAttrRe.ReplaceAllStringFunc(tag, func(attr string) string {
+			m := xmlnsAttrRe.FindStringSubmatch(attr)
+			name, value := m[1], m[2][1:len(m[2])-1]
+			if seen[name] {
+				removed++
+				return ""
+			}
+			seen[name] = true
+			if isRoot {
+				return attr
+			}
+			if inherited, ok := rootNS[name]; ok && inherited == value {
+				removed++
+				return ""
+			}
+			return attr
+		})
+		if isRoot {
+			rootNS = map[string]string{}
+			for _, m := range xmlnsAttrRe.FindAllStringSubmatch(tag, -1) {
+				rootNS[m[1]] = m[2][1 : len(m[2])-1]
+			}
+		}
+		return tag
+	})
+
+	return content, removed
+}
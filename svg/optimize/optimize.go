@@ -0,0 +1,100 @@
+// Package optimize provides SVG cleanup and size optimization.
+package optimize
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grokify/mogo/os/osutil"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// Options configures the optimization steps to apply.
+type Options struct {
+	CollapseWhitespace bool // Remove insignificant whitespace between tags
+	InlineStyles       bool // Inline simple class rules from <style> into presentation attributes
+	StripComments      bool // Remove XML comments
+	DedupeNamespaces   bool // Remove duplicate and redundant xmlns declarations
+	StripEditorData    bool // Remove <metadata> and Inkscape/Illustrator/Sketch editor data
+}
+
+// DefaultOptions returns options that apply all optimization steps.
+func DefaultOptions() Options {
+	return Options{
+		CollapseWhitespace: true,
+		InlineStyles:       true,
+		StripComments:      true,
+		DedupeNamespaces:   true,
+		StripEditorData:    true,
+	}
+}
+
+// Result contains the result of optimizing an SVG file.
+type Result struct {
+	InputPath         string
+	OutputPath        string
+	StylesInlined     int // Number of class rules inlined into attributes
+	NamespacesRemoved int // Number of duplicate or redundant xmlns declarations removed
+	EditorDataRemoved int // Number of editor elements, attributes, and metadata blocks removed
+	Error             error
+}
+
+// SVG optimizes an SVG file and writes the result.
+func SVG(inputPath, outputPath string, opts Options) (*Result, error) {
+	result := &Result{
+		InputPath:  inputPath,
+		OutputPath: outputPath,
+	}
+
+	content, err := os.ReadFile(inputPath)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read file: %w", err)
+		return result, result.Error
+	}
+
+	optimized, stats := optimizeContent(string(content), opts)
+	result.StylesInlined = stats.stylesInlined
+	result.NamespacesRemoved = stats.namespacesRemoved
+	result.EditorDataRemoved = stats.editorDataRemoved
+
+	if err := osutil.WriteFileSecure(outputPath, []byte(optimized), 0600); err != nil {
+		result.Error = fmt.Errorf("failed to write file: %w", err)
+		return result, result.Error
+	}
+
+	return result, nil
+}
+
+// Content optimizes SVG content in memory.
+func Content(content string, opts Options) string {
+	optimized, _ := optimizeContent(content, opts)
+	return optimized
+}
+
+// optimizeStats counts changes made by optimizeContent.
+type optimizeStats struct {
+	stylesInlined     int
+	namespacesRemoved int
+	editorDataRemoved int
+}
+
+func optimizeContent(content string, opts Options) (string, optimizeStats) {
+	var stats optimizeStats
+	if opts.StripComments {
+		content = string(svg.StripComments([]byte(content)))
+	}
+	if opts.StripEditorData {
+		content, stats.editorDataRemoved = StripEditorData(content)
+	}
+	if opts.DedupeNamespaces {
+		content, stats.namespacesRemoved = DedupeNamespaces(content)
+	}
+	if opts.InlineStyles {
+		content, stats.stylesInlined = InlineStyles(content)
+	}
+	if opts.CollapseWhitespace {
+		content = string(svg.CollapseWhitespace([]byte(content)))
+	}
+	return content, stats
+}
@@ -0,0 +1,209 @@
+package optimize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+func TestInlineStyles(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <style>.cls-1 { fill: red }</style>
+  <rect class="cls-1" x="10" y="10" width="80" height="80"/>
+</svg>`
+
+	got, inlined := InlineStyles(content)
+	if inlined != 1 {
+		t.Errorf("inlined = %d, want 1", inlined)
+	}
+	if strings.Contains(got, "<style") {
+		t.Error("empty style block should be removed")
+	}
+	if !strings.Contains(got, `<rect class="cls-1" x="10" y="10" width="80" height="80" fill="red"/>`) {
+		t.Errorf("rect should have inlined fill, got:\n%s", got)
+	}
+	if _, err := svg.Parse([]byte(got)); err != nil {
+		t.Errorf("output should parse: %v", err)
+	}
+}
+
+func TestInlineStylesOverridesAttribute(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><style><![CDATA[.a{fill:#00f;stroke:#000}]]></style><path class="a" fill="#f00" d="M0 0L10 10"/></svg>`
+
+	got, _ := InlineStyles(content)
+	if !strings.Contains(got, `fill="#00f"`) || strings.Contains(got, `fill="#f00"`) {
+		t.Errorf("class rule should override fill attribute, got: %s", got)
+	}
+	if !strings.Contains(got, `stroke="#000"`) {
+		t.Errorf("stroke should be inlined, got: %s", got)
+	}
+}
+
+func TestInlineStylesRespectsInlineStyle(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><style>.a{fill:#00f}</style><path class="a" style="fill:#0f0" d="M0 0L10 10"/></svg>`
+
+	got, _ := InlineStyles(content)
+	if strings.Contains(got, `fill="#00f"`) {
+		t.Errorf("inline style should take precedence over class rule, got: %s", got)
+	}
+}
+
+func TestInlineStylesSourceOrder(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><style>.a{fill:red}.b{fill:blue}</style><rect class="b a" width="10" height="10"/></svg>`
+
+	// The later rule wins regardless of the order of the class attribute
+	got, _ := InlineStyles(content)
+	if !strings.Contains(got, `fill="blue"`) || strings.Contains(got, `fill="red"`) {
+		t.Errorf("later rule should win, got: %s", got)
+	}
+}
+
+func TestInlineStylesLeavesComplexCSS(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><style>.a{fill:red} g > .b{fill:blue} .c{filter:blur(2px)}</style><path class="a b c" d="M0 0L10 10"/></svg>`
+
+	got, inlined := InlineStyles(content)
+	if inlined != 1 {
+		t.Errorf("inlined = %d, want 1", inlined)
+	}
+	if !strings.Contains(got, "g > .b{fill:blue}") || !strings.Contains(got, ".c{filter:blur(2px)}") {
+		t.Errorf("complex rules should remain in style block, got: %s", got)
+	}
+	if !strings.Contains(got, `fill="red"`) {
+		t.Errorf("simple rule should be inlined, got: %s", got)
+	}
+}
+
+func TestContentCollapseWhitespace(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10">
+  <path d="M0 0L10 10"/>
+  <text x="0" y="5"><tspan>a</tspan> <tspan>b</tspan></text>
+</svg>`
+
+	got := Content(content, Options{CollapseWhitespace: true})
+	if strings.Contains(got, "\n") {
+		t.Errorf("whitespace between tags should be removed, got: %s", got)
+	}
+	if !strings.Contains(got, "<tspan>a</tspan> <tspan>b</tspan>") {
+		t.Errorf("whitespace inside text should be preserved, got: %s", got)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100">
+  <style>.cls-1 { fill: #ff0000 }</style>
+  <rect class="cls-1" width="80" height="80"/>
+</svg>`
+
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(input, output, DefaultOptions())
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.StylesInlined != 1 {
+		t.Errorf("StylesInlined = %d, want 1", result.StylesInlined)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<svg viewBox="0 0 100 100"><rect class="cls-1" width="80" height="80" fill="#ff0000"/></svg>`
+	if string(got) != want {
+		t.Errorf("output = %s, want %s", got, want)
+	}
+}
+
+func TestContentStripComments(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><!-- Generator: Example 1.0 --><path d="M0 0L10 10"/></svg>`
+
+	got := Content(content, Options{StripComments: true})
+	want := `<svg viewBox="0 0 10 10"><path d="M0 0L10 10"/></svg>`
+	if got != want {
+		t.Errorf("Content() = %q, want %q", got, want)
+	}
+}
+
+func TestDedupeNamespaces(t *testing.T) {
+	content := `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10">
+  <g xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/></g>
+  <foreignObject><div xmlns="http://www.w3.org/1999/xhtml">x</div></foreignObject>
+</svg>`
+
+	got, removed := DedupeNamespaces(content)
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	if n := strings.Count(got, `xmlns="http://www.w3.org/2000/svg"`); n != 1 {
+		t.Errorf("got %d svg namespace declarations, want 1:\n%s", n, got)
+	}
+	if !strings.Contains(got, `xmlns:xlink="http://www.w3.org/1999/xlink"`) {
+		t.Errorf("xlink declaration removed:\n%s", got)
+	}
+	// A different namespace on a descendant is kept
+	if !strings.Contains(got, `<div xmlns="http://www.w3.org/1999/xhtml">`) {
+		t.Errorf("xhtml declaration removed:\n%s", got)
+	}
+	if _, err := svg.Parse([]byte(got)); err != nil {
+		t.Errorf("Parse error: %v", err)
+	}
+}
+
+func TestStripEditorData(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"
+  xmlns:sodipodi="http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd"
+  xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape"
+  xmlns:i="http://ns.adobe.com/AdobeIllustrator/10.0/"
+  inkscape:version="1.3" sodipodi:docname="icon.svg">
+  <metadata><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><rdf:Description/></rdf:RDF></metadata>
+  <sodipodi:namedview id="nv" inkscape:zoom="2"><inkscape:page x="0" y="0"/></sodipodi:namedview>
+  <g inkscape:label="Layer 1" inkscape:groupmode="layer" i:extraneous="self">
+    <path id="p" d="M 10 10 L 90 90"/>
+  </g>
+</svg>`
+
+	got, removed := StripEditorData(content)
+	for _, unwanted := range []string{"inkscape", "sodipodi", "metadata", "rdf:", "i:extraneous", "xmlns:i="} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("output still contains %q:\n%s", unwanted, got)
+		}
+	}
+	if removed != 10 {
+		t.Errorf("removed = %d, want 10", removed)
+	}
+
+	before, err := svg.Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := svg.Parse([]byte(got))
+	if err != nil {
+		t.Fatalf("output should parse: %v\n%s", err, got)
+	}
+	if *svg.ContentBounds(before) != *svg.ContentBounds(after) {
+		t.Errorf("bounds changed: %+v -> %+v", svg.ContentBounds(before), svg.ContentBounds(after))
+	}
+}
+
+func TestStripEditorDataKeepsUnprefixedAttributes(t *testing.T) {
+	content := `<svg xmlns="http://www.w3.org/2000/svg" xmlns:x="adobe:ns:meta/" xmlns:d="http://ns.adobe.com/SaveForWeb/1.0/">` +
+		`<rect x="10" y="10" width="5" height="5" x:foo="1"/><path d="M 0 0 L 1 1" d:bar="2"/></svg>`
+
+	got, removed := StripEditorData(content)
+	want := `<svg xmlns="http://www.w3.org/2000/svg"><rect x="10" y="10" width="5" height="5"/><path d="M 0 0 L 1 1"/></svg>`
+	if got != want {
+		t.Errorf("StripEditorData =\n%s\nwant\n%s", got, want)
+	}
+	if removed != 4 {
+		t.Errorf("removed = %d, want 4", removed)
+	}
+}
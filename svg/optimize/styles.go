@@ -0,0 +1,187 @@
+package optimize
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// styleElementRe matches a <style> element without attributes (e.g. media)
+	// that would make inlining unsafe.
+	styleElementRe = regexp.MustCompile(`(?is)<style(\s+type\s*=\s*["']text/css["'])?\s*>(.*?)</style>`)
+	cdataRe        = regexp.MustCompile(`(?s)^\s*<!\[CDATA\[(.*)\]\]>\s*$`)
+	cssRuleRe      = regexp.MustCompile(`(?s)([^{}]+)\{([^{}]*)\}`)
+	classSelRe     = regexp.MustCompile(`^\.([A-Za-z_][\w-]*)$`)
+	startTagRe     = regexp.MustCompile(`<[A-Za-z][^<>]*>`)
+	classAttrRe    = regexp.MustCompile(`\sclass\s*=\s*["']([^"']*)["']`)
+	styleAttrRe    = regexp.MustCompile(`\sstyle\s*=\s*["']([^"']*)["']`)
+)
+
+// inlineableProperties lists CSS properties that have an equivalent
+// SVG presentation attribute.
+var inlineableProperties = map[string]bool{
+	"fill":              true,
+	"fill-opacity":      true,
+	"fill-rule":         true,
+	"stroke":            true,
+	"stroke-width":      true,
+	"stroke-opacity":    true,
+	"stroke-linecap":    true,
+	"stroke-linejoin":   true,
+	"stroke-miterlimit": true,
+	"opacity":           true,
+	"clip-rule":         true,
+}
+
+// declaration is a single CSS property/value pair.
+type declaration struct {
+	prop  string
+	value string
+	order int // index of the rule in source order, for the cascade
+}
+
+// InlineStyles resolves simple class rules (`.cls { fill: red }`) from
+// internal <style> blocks into presentation attributes on matching elements.
+// Inlined rules are removed from the style block, and the block itself is
+// removed once empty. Complex CSS (other selectors, at-rules, unsupported
+// properties) is left untouched. Returns the updated content and the number
+// of rules inlined.
+func InlineStyles(content string) (string, int) {
+	rules := map[string][]declaration{}
+	inlined := 0
+	order := 0
+
+	content = styleElementRe.ReplaceAllStringFunc(content, func(match string) string {
+		parts := styleElementRe.FindStringSubmatch(match)
+		css := parts[2]
+		isCDATA := false
+		if m := cdataRe.FindStringSubmatch(css); m != nil {
+			css = m[1]
+			isCDATA = true
+		}
+		if strings.Contains(css, "@") || strings.Contains(css, "/*") {
+			return match
+		}
+
+		remaining := cssRuleRe.ReplaceAllStringFunc(css, func(rule string) string {
+			m := cssRuleRe.FindStringSubmatch(rule)
+			classes, ok := parseClassSelectors(m[1])
+			if !ok {
+				return rule
+			}
+			decls, ok := parseDeclarations(m[2])
+			if !ok {
+				return rule
+			}
+			for i := range decls {
+				decls[i].order = order
+			}
+			order++
+			for _, cls := range classes {
+				rules[cls] = append(rules[cls], decls...)
+			}
+			inlined++
+			return ""
+		})
+
+		if strings.TrimSpace(remaining) == "" {
+			return ""
+		}
+		if isCDATA {
+			remaining = "<![CDATA[" + remaining + "]]>"
+		}
+		return strings.Replace(match, parts[2], remaining, 1)
+	})
+
+	if len(rules) == 0 {
+		return content, 0
+	}
+
+	content = startTagRe.ReplaceAllStringFunc(content, func(tag string) string {
+		m := classAttrRe.FindStringSubmatch(tag)
+		if m == nil {
+			return tag
+		}
+		inlineStyle := ""
+		if sm := styleAttrRe.FindStringSubmatch(tag); sm != nil {
+			inlineStyle = sm[1]
+		}
+		// Later rules win, whatever the order of the class attribute
+		var decls []declaration
+		for _, cls := range strings.Fields(m[1]) {
+			decls = append(decls, rules[cls]...)
+		}
+		sort.SliceStable(decls, func(i, j int) bool { return decls[i].order < decls[j].order })
+		for _, d := range decls {
+			// Inline style declarations take precedence over class rules
+			if hasStyleProperty(inlineStyle, d.prop) {
+				continue
+			}
+			tag = setAttribute(tag, d.prop, d.value)
+		}
+		return tag
+	})
+
+	return content, inlined
+}
+
+// parseClassSelectors parses a selector list made only of single class
+// selectors, e.g. ".a, .b".
+func parseClassSelectors(selector string) ([]string, bool) {
+	var classes []string
+	for _, sel := range strings.Split(selector, ",") {
+		m := classSelRe.FindStringSubmatch(strings.TrimSpace(sel))
+		if m == nil {
+			return nil, false
+		}
+		classes = append(classes, m[1])
+	}
+	return classes, len(classes) > 0
+}
+
+// parseDeclarations parses a declaration block containing only inlineable properties.
+func parseDeclarations(block string) ([]declaration, bool) {
+	var decls []declaration
+	for _, part := range strings.Split(block, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		prop, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, false
+		}
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.TrimSpace(value)
+		if !inlineableProperties[prop] || value == "" ||
+			strings.ContainsAny(value, `"'<>`) || strings.Contains(value, "!important") {
+			return nil, false
+		}
+		decls = append(decls, declaration{prop: prop, value: value})
+	}
+	return decls, len(decls) > 0
+}
+
+// hasStyleProperty reports whether an inline style attribute sets a property.
+func hasStyleProperty(style, prop string) bool {
+	for _, part := range strings.Split(style, ";") {
+		p, _, ok := strings.Cut(part, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(p), prop) {
+			return true
+		}
+	}
+	return false
+}
+
+// setAttribute sets an attribute on a start tag, replacing any existing value.
+func setAttribute(tag, name, value string) string {
+	attrRe := regexp.MustCompile(`(\s` + regexp.QuoteMeta(name) + `\s*=\s*)(["'])[^"']*["']`)
+	if attrRe.MatchString(tag) {
+		return attrRe.ReplaceAllString(tag, `${1}"`+strings.ReplaceAll(value, "$", "$$")+`"`)
+	}
+	insert := ` ` + name + `="` + value + `"`
+	if strings.HasSuffix(tag, "/>") {
+		return strings.TrimSuffix(tag, "/>") + insert + "/>"
+	}
+	return strings.TrimSuffix(tag, ">") + insert + ">"
+}
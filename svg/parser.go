@@ -1,8 +1,10 @@
 package svg
 
 import (
+	"math"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/JoshVarga/svgparser"
 )
@@ -28,6 +30,10 @@ func ParsePath(d string) []PathCommand {
 	matches := cmdRe.FindAllStringSubmatch(d, -1)
 	for _, match := range matches {
 		cmd := match[1][0]
+		if cmd == 'A' || cmd == 'a' {
+			commands = append(commands, PathCommand{Command: cmd, Params: parseArcParams(match[2], numRe)})
+			continue
+		}
 		params := numRe.FindAllString(match[2], -1)
 
 		var floatParams []float64
@@ -43,15 +49,140 @@ func ParsePath(d string) []PathCommand {
 	return commands
 }
 
+// parseArcParams parses arc command parameters. The large-arc and sweep
+// flags (the 4th and 5th of every 7) are single characters that may be
+// written without separators, as in "a10 10 0 0110 10".
+func parseArcParams(s string, numRe *regexp.Regexp) []float64 {
+	var params []float64
+	for i := 0; ; i++ {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		if s == "" {
+			return params
+		}
+		if n := i % 7; n == 3 || n == 4 {
+			if s[0] != '0' && s[0] != '1' {
+				return params
+			}
+			params = append(params, float64(s[0]-'0'))
+			s = s[1:]
+			continue
+		}
+		loc := numRe.FindStringIndex(s)
+		if loc == nil || loc[0] != 0 {
+			return params
+		}
+		v, err := strconv.ParseFloat(s[:loc[1]], 64)
+		if err != nil {
+			return params
+		}
+		params = append(params, v)
+		s = s[loc[1]:]
+	}
+}
+
+// NormalizePathData returns a canonical form of path data so that paths
+// differing only in separators or number formatting compare equal.
+// For example, "M0,0L10,10" and "M 0 0 L 10 10" both normalize to
+// "M 0 0 L 10 10".
+func NormalizePathData(d string) string {
+	return PathCommandsToString(ParsePath(d))
+}
+
+// pathCommandArity returns the number of parameters a path command takes,
+// or -1 for an unknown command. A command may repeat its parameters, e.g.
+// "L 0 0 10 10".
+func pathCommandArity(c byte) int {
+	switch c {
+	case 'Z', 'z':
+		return 0
+	case 'H', 'h', 'V', 'v':
+		return 1
+	case 'M', 'm', 'L', 'l', 'T', 't':
+		return 2
+	case 'S', 's', 'Q', 'q':
+		return 4
+	case 'C', 'c':
+		return 6
+	case 'A', 'a':
+		return 7
+	default:
+		return -1
+	}
+}
+
+// PathCommandsToString serializes path commands, e.g. from ParsePath, back
+// into path data with single-space separators and the shortest exact
+// number formatting. Trailing parameters that do not complete a repetition
+// of the command, and parameters given to Z, are dropped.
+func PathCommandsToString(cmds []PathCommand) string {
+	var sb strings.Builder
+	for i, cmd := range cmds {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte(cmd.Command)
+		params := cmd.Params
+		switch arity := pathCommandArity(cmd.Command); {
+		case arity == 0:
+			params = nil
+		case arity > 0:
+			params = params[:len(params)-len(params)%arity]
+		}
+		for _, p := range params {
+			sb.WriteByte(' ')
+			sb.WriteString(formatNumber(p))
+		}
+	}
+	return sb.String()
+}
+
+// formatNumber formats a float using the shortest exact representation.
+func formatNumber(v float64) string {
+	if v == 0 {
+		return "0" // Avoid "-0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
 // CalculatePathBounds calculates the bounding box from path commands.
 func CalculatePathBounds(d string) *BoundingBox {
+	return calculatePathBounds(d, IdentityMatrix())
+}
+
+// calculatePathBounds calculates the bounding box of path commands after
+// transforming them by m. Since an affine transform of a Bézier curve is
+// the curve of its transformed control points, curve extrema are found in
+// the transformed coordinate system and the bounds stay exact.
+func calculatePathBounds(d string, m Matrix) *BoundingBox {
 	box := NewBoundingBox()
 	commands := ParsePath(d)
+	expand := func(x, y float64) { box.Expand(m.Apply(x, y)) }
 
 	var curX, curY float64
 	var startX, startY float64
 
+	// Control points of the previous segment, reflected by S/s and T/t
+	var cubicCtrlX, cubicCtrlY, quadCtrlX, quadCtrlY float64
+	var hasCubicCtrl, hasQuadCtrl bool
+
+	// origin returns the point that command parameters are relative to
+	origin := func(relative bool) (float64, float64) {
+		if relative {
+			return curX, curY
+		}
+		return 0, 0
+	}
+
 	for _, cmd := range commands {
+		switch cmd.Command {
+		case 'C', 'c', 'S', 's':
+			hasQuadCtrl = false
+		case 'Q', 'q', 'T', 't':
+			hasCubicCtrl = false
+		default:
+			hasCubicCtrl, hasQuadCtrl = false, false
+		}
+
 		switch cmd.Command {
 		case 'M': // moveto absolute
 			for i := 0; i+1 < len(cmd.Params); i += 2 {
@@ -59,7 +190,7 @@ func CalculatePathBounds(d string) *BoundingBox {
 				if i == 0 {
 					startX, startY = curX, curY
 				}
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'm': // moveto relative
 			for i := 0; i+1 < len(cmd.Params); i += 2 {
@@ -68,101 +199,93 @@ func CalculatePathBounds(d string) *BoundingBox {
 				if i == 0 {
 					startX, startY = curX, curY
 				}
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'L': // lineto absolute
 			for i := 0; i+1 < len(cmd.Params); i += 2 {
 				curX, curY = cmd.Params[i], cmd.Params[i+1]
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'l': // lineto relative
 			for i := 0; i+1 < len(cmd.Params); i += 2 {
 				curX += cmd.Params[i]
 				curY += cmd.Params[i+1]
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'H': // horizontal absolute
 			for _, x := range cmd.Params {
 				curX = x
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'h': // horizontal relative
 			for _, dx := range cmd.Params {
 				curX += dx
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'V': // vertical absolute
 			for _, y := range cmd.Params {
 				curY = y
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'v': // vertical relative
 			for _, dy := range cmd.Params {
 				curY += dy
-				box.Expand(curX, curY)
-			}
-		case 'C': // cubic bezier absolute
-			for i := 0; i+5 < len(cmd.Params); i += 6 {
-				box.Expand(cmd.Params[i], cmd.Params[i+1])
-				box.Expand(cmd.Params[i+2], cmd.Params[i+3])
-				curX, curY = cmd.Params[i+4], cmd.Params[i+5]
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
-		case 'c': // cubic bezier relative
+		case 'C', 'c': // cubic bezier
 			for i := 0; i+5 < len(cmd.Params); i += 6 {
-				box.Expand(curX+cmd.Params[i], curY+cmd.Params[i+1])
-				box.Expand(curX+cmd.Params[i+2], curY+cmd.Params[i+3])
-				curX += cmd.Params[i+4]
-				curY += cmd.Params[i+5]
-				box.Expand(curX, curY)
-			}
-		case 'S': // smooth cubic absolute
-			for i := 0; i+3 < len(cmd.Params); i += 4 {
-				box.Expand(cmd.Params[i], cmd.Params[i+1])
-				curX, curY = cmd.Params[i+2], cmd.Params[i+3]
-				box.Expand(curX, curY)
-			}
-		case 's': // smooth cubic relative
-			for i := 0; i+3 < len(cmd.Params); i += 4 {
-				box.Expand(curX+cmd.Params[i], curY+cmd.Params[i+1])
-				curX += cmd.Params[i+2]
-				curY += cmd.Params[i+3]
-				box.Expand(curX, curY)
+				ox, oy := origin(cmd.Command == 'c')
+				x1, y1 := ox+cmd.Params[i], oy+cmd.Params[i+1]
+				x2, y2 := ox+cmd.Params[i+2], oy+cmd.Params[i+3]
+				x, y := ox+cmd.Params[i+4], oy+cmd.Params[i+5]
+				expandCubic(box, m, curX, curY, x1, y1, x2, y2, x, y)
+				curX, curY = x, y
+				cubicCtrlX, cubicCtrlY, hasCubicCtrl = x2, y2, true
 			}
-		case 'Q': // quadratic bezier absolute
+		case 'S', 's': // smooth cubic
 			for i := 0; i+3 < len(cmd.Params); i += 4 {
-				box.Expand(cmd.Params[i], cmd.Params[i+1])
-				curX, curY = cmd.Params[i+2], cmd.Params[i+3]
-				box.Expand(curX, curY)
+				ox, oy := origin(cmd.Command == 's')
+				x1, y1 := curX, curY
+				if hasCubicCtrl {
+					x1, y1 = 2*curX-cubicCtrlX, 2*curY-cubicCtrlY
+				}
+				x2, y2 := ox+cmd.Params[i], oy+cmd.Params[i+1]
+				x, y := ox+cmd.Params[i+2], oy+cmd.Params[i+3]
+				expandCubic(box, m, curX, curY, x1, y1, x2, y2, x, y)
+				curX, curY = x, y
+				cubicCtrlX, cubicCtrlY, hasCubicCtrl = x2, y2, true
 			}
-		case 'q': // quadratic bezier relative
+		case 'Q', 'q': // quadratic bezier
 			for i := 0; i+3 < len(cmd.Params); i += 4 {
-				box.Expand(curX+cmd.Params[i], curY+cmd.Params[i+1])
-				curX += cmd.Params[i+2]
-				curY += cmd.Params[i+3]
-				box.Expand(curX, curY)
-			}
-		case 'T': // smooth quadratic absolute
-			for i := 0; i+1 < len(cmd.Params); i += 2 {
-				curX, curY = cmd.Params[i], cmd.Params[i+1]
-				box.Expand(curX, curY)
+				ox, oy := origin(cmd.Command == 'q')
+				x1, y1 := ox+cmd.Params[i], oy+cmd.Params[i+1]
+				x, y := ox+cmd.Params[i+2], oy+cmd.Params[i+3]
+				expandQuadratic(box, m, curX, curY, x1, y1, x, y)
+				curX, curY = x, y
+				quadCtrlX, quadCtrlY, hasQuadCtrl = x1, y1, true
 			}
-		case 't': // smooth quadratic relative
+		case 'T', 't': // smooth quadratic
 			for i := 0; i+1 < len(cmd.Params); i += 2 {
-				curX += cmd.Params[i]
-				curY += cmd.Params[i+1]
-				box.Expand(curX, curY)
+				ox, oy := origin(cmd.Command == 't')
+				x1, y1 := curX, curY
+				if hasQuadCtrl {
+					x1, y1 = 2*curX-quadCtrlX, 2*curY-quadCtrlY
+				}
+				x, y := ox+cmd.Params[i], oy+cmd.Params[i+1]
+				expandQuadratic(box, m, curX, curY, x1, y1, x, y)
+				curX, curY = x, y
+				quadCtrlX, quadCtrlY, hasQuadCtrl = x1, y1, true
 			}
 		case 'A': // arc absolute
 			for i := 0; i+6 < len(cmd.Params); i += 7 {
 				curX, curY = cmd.Params[i+5], cmd.Params[i+6]
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'a': // arc relative
 			for i := 0; i+6 < len(cmd.Params); i += 7 {
 				curX += cmd.Params[i+5]
 				curY += cmd.Params[i+6]
-				box.Expand(curX, curY)
+				expand(curX, curY)
 			}
 		case 'Z', 'z': // closepath
 			curX, curY = startX, startY
@@ -172,46 +295,64 @@ func CalculatePathBounds(d string) *BoundingBox {
 	return box
 }
 
-// GetElementBounds calculates bounds for an SVG element.
+// GetElementBounds calculates bounds for an SVG element. Percentage
+// lengths resolve to 0; use GetElementBoundsInViewBox to resolve them.
 func GetElementBounds(elem *svgparser.Element) *BoundingBox {
+	return GetElementBoundsInViewBox(elem, ViewBox{})
+}
+
+// GetElementBoundsInViewBox calculates bounds for an SVG element, resolving
+// percentage lengths (e.g. width="50%") against vb. A nested <svg> with its
+// own viewBox resolves its children against that viewBox instead.
+// Transform attributes on the element and its descendants are applied, so
+// the bounds are in the coordinate system of the element's parent.
+func GetElementBoundsInViewBox(elem *svgparser.Element, vb ViewBox) *BoundingBox {
+	return elementBounds(elem, vb, IdentityMatrix())
+}
+
+// elementBounds calculates bounds for an element whose parent's coordinate
+// system maps to the result by ctm.
+func elementBounds(elem *svgparser.Element, vb ViewBox, ctm Matrix) *BoundingBox {
 	box := NewBoundingBox()
+	m := ctm.Multiply(elementTransform(elem.Attributes))
+
+	w, h := vb.Width, vb.Height
+	diag := math.Sqrt((w*w + h*h) / 2)
+	attr := func(name string, ref float64) float64 {
+		return resolveLength(elem.Attributes[name], ref)
+	}
 
 	switch elem.Name {
+	case "svg":
+		if nested, err := ParseViewBox(elem.Attributes["viewBox"]); err == nil {
+			vb = nested
+		}
 	case "path":
 		if d, ok := elem.Attributes["d"]; ok {
-			box.Merge(CalculatePathBounds(d))
+			box.Merge(calculatePathBounds(d, m))
 		}
 	case "circle":
-		cx := ParseFloat(elem.Attributes["cx"], 0)
-		cy := ParseFloat(elem.Attributes["cy"], 0)
-		r := ParseFloat(elem.Attributes["r"], 0)
-		box.Expand(cx-r, cy-r)
-		box.Expand(cx+r, cy+r)
+		r := attr("r", diag)
+		expandEllipse(box, m, attr("cx", w), attr("cy", h), r, r)
 	case "ellipse":
-		cx := ParseFloat(elem.Attributes["cx"], 0)
-		cy := ParseFloat(elem.Attributes["cy"], 0)
-		rx := ParseFloat(elem.Attributes["rx"], 0)
-		ry := ParseFloat(elem.Attributes["ry"], 0)
-		box.Expand(cx-rx, cy-ry)
-		box.Expand(cx+rx, cy+ry)
+		expandEllipse(box, m, attr("cx", w), attr("cy", h), attr("rx", w), attr("ry", h))
 	case "rect":
-		x := ParseFloat(elem.Attributes["x"], 0)
-		y := ParseFloat(elem.Attributes["y"], 0)
-		w := ParseFloat(elem.Attributes["width"], 0)
-		h := ParseFloat(elem.Attributes["height"], 0)
-		box.Expand(x, y)
-		box.Expand(x+w, y+h)
+		x := attr("x", w)
+		y := attr("y", h)
+		expandRect(box, m, x, y, x+attr("width", w), y+attr("height", h))
 	case "line":
-		x1 := ParseFloat(elem.Attributes["x1"], 0)
-		y1 := ParseFloat(elem.Attributes["y1"], 0)
-		x2 := ParseFloat(elem.Attributes["x2"], 0)
-		y2 := ParseFloat(elem.Attributes["y2"], 0)
-		box.Expand(x1, y1)
-		box.Expand(x2, y2)
+		box.Expand(m.Apply(attr("x1", w), attr("y1", h)))
+		box.Expand(m.Apply(attr("x2", w), attr("y2", h)))
 	case "polygon", "polyline":
 		if points, ok := elem.Attributes["points"]; ok {
-			box.Merge(parsePoints(points))
+			box.Merge(parsePoints(points, m))
+		}
+	case "text":
+		// Text children (tspans) are positioned relative to each other
+		if text := TextBounds(elem); text.IsValid() {
+			expandRect(box, m, text.MinX, text.MinY, text.MaxX, text.MaxY)
 		}
+		return box
 	}
 
 	// Recursively process children
@@ -220,15 +361,45 @@ func GetElementBounds(elem *svgparser.Element) *BoundingBox {
 		if child.Name == "mask" || child.Name == "clipPath" || child.Name == "defs" {
 			continue
 		}
-		childBox := GetElementBounds(child)
+		childBox := elementBounds(child, vb, m)
 		box.Merge(childBox)
 	}
 
 	return box
 }
 
-// parsePoints parses polygon/polyline points attribute.
-func parsePoints(points string) *BoundingBox {
+// expandRect expands box by the four corners of a rectangle transformed
+// by m.
+func expandRect(box *BoundingBox, m Matrix, x0, y0, x1, y1 float64) {
+	box.Expand(m.Apply(x0, y0))
+	box.Expand(m.Apply(x1, y0))
+	box.Expand(m.Apply(x0, y1))
+	box.Expand(m.Apply(x1, y1))
+}
+
+// expandEllipse expands box by an axis-aligned ellipse transformed by m.
+// The transformed ellipse's extent along each axis follows from the
+// columns of m scaled by the radii.
+func expandEllipse(box *BoundingBox, m Matrix, cx, cy, rx, ry float64) {
+	x, y := m.Apply(cx, cy)
+	dx := math.Hypot(m.A*rx, m.C*ry)
+	dy := math.Hypot(m.B*rx, m.D*ry)
+	box.Expand(x-dx, y-dy)
+	box.Expand(x+dx, y+dy)
+}
+
+// resolveLength parses a length, resolving a percentage against ref.
+func resolveLength(s string, ref float64) float64 {
+	s = strings.TrimSpace(s)
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		return ParseFloat(pct, 0) / 100 * ref
+	}
+	return ParseFloat(s, 0)
+}
+
+// parsePoints parses polygon/polyline points attribute, transforming each
+// point by m.
+func parsePoints(points string, m Matrix) *BoundingBox {
 	box := NewBoundingBox()
 	re := regexp.MustCompile(`-?[\d]+\.?[\d]*`)
 	matches := re.FindAllString(points, -1)
@@ -236,7 +407,7 @@ func parsePoints(points string) *BoundingBox {
 	for i := 0; i+1 < len(matches); i += 2 {
 		x, _ := strconv.ParseFloat(matches[i], 64)
 		y, _ := strconv.ParseFloat(matches[i+1], 64)
-		box.Expand(x, y)
+		box.Expand(m.Apply(x, y))
 	}
 
 	return box
@@ -1,6 +1,9 @@
 package svg
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -136,7 +139,7 @@ func TestCalculatePathBoundsRelativeMoveTo(t *testing.T) {
 }
 
 func TestCalculatePathBoundsCubicBezier(t *testing.T) {
-	// Control points extend the bounding box
+	// The curve bulges past its endpoints toward the control points
 	box := CalculatePathBounds("M 0 0 C 50 -20 80 120 100 100")
 	if box.MinY >= 0 {
 		t.Errorf("MinY = %v, should be negative (control point at y=-20)", box.MinY)
@@ -146,6 +149,59 @@ func TestCalculatePathBoundsCubicBezier(t *testing.T) {
 	}
 }
 
+func TestCalculatePathBoundsCircleApproximation(t *testing.T) {
+	// A circle of radius 50 centered at (50, 50), drawn as four cubic arcs
+	// starting at 45 degrees so that the control points lie well outside
+	// the circle's bounds
+	const cx, cy, r = 50.0, 50.0, 50.0
+	k := 4.0 / 3.0 * (math.Sqrt2 - 1)
+	var sb strings.Builder
+	for i := 0; i < 4; i++ {
+		a0 := math.Pi/4 + float64(i)*math.Pi/2
+		a1 := a0 + math.Pi/2
+		x0, y0 := cx+r*math.Cos(a0), cy+r*math.Sin(a0)
+		x3, y3 := cx+r*math.Cos(a1), cy+r*math.Sin(a1)
+		if i == 0 {
+			fmt.Fprintf(&sb, "M %g %g ", x0, y0)
+		}
+		fmt.Fprintf(&sb, "C %g %g %g %g %g %g ",
+			x0-k*r*math.Sin(a0), y0+k*r*math.Cos(a0),
+			x3+k*r*math.Sin(a1), y3-k*r*math.Cos(a1),
+			x3, y3)
+	}
+	sb.WriteString("Z")
+
+	box := CalculatePathBounds(sb.String())
+	const tolerance = 0.05
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"MinX", box.MinX, 0},
+		{"MinY", box.MinY, 0},
+		{"MaxX", box.MaxX, 100},
+		{"MaxY", box.MaxY, 100},
+	} {
+		if math.Abs(c.got-c.want) > tolerance {
+			t.Errorf("%s = %v, want %v within %v", c.name, c.got, c.want, tolerance)
+		}
+	}
+}
+
+func TestCalculatePathBoundsQuadratic(t *testing.T) {
+	// The apex of the parabola is halfway to the control point
+	box := CalculatePathBounds("M 0 0 Q 50 100 100 0")
+	if box.MaxY != 50 {
+		t.Errorf("MaxY = %v, want 50", box.MaxY)
+	}
+
+	// T reflects the previous control point: the second hump dips to -50
+	box = CalculatePathBounds("M 0 0 Q 50 100 100 0 T 200 0")
+	if box.MinY != -50 || box.MaxX != 200 {
+		t.Errorf("MinY, MaxX = %v, %v, want -50, 200", box.MinY, box.MaxX)
+	}
+}
+
 func TestCalculatePathBoundsArc(t *testing.T) {
 	box := CalculatePathBounds("M 0 0 A 25 25 0 1 1 50 50")
 	if box.MaxX != 50 || box.MaxY != 50 {
@@ -163,7 +219,7 @@ func TestCalculatePathBoundsClosePath(t *testing.T) {
 }
 
 func TestParsePointsPolygon(t *testing.T) {
-	box := parsePoints("10,20 30,40 50,60")
+	box := parsePoints("10,20 30,40 50,60", IdentityMatrix())
 	if box.MinX != 10 || box.MinY != 20 {
 		t.Errorf("min = (%v, %v), want (10, 20)", box.MinX, box.MinY)
 	}
@@ -171,3 +227,107 @@ func TestParsePointsPolygon(t *testing.T) {
 		t.Errorf("max = (%v, %v), want (50, 60)", box.MaxX, box.MaxY)
 	}
 }
+
+func TestNormalizePathData(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"M0,0L10,10", "M 0 0 L 10 10"},
+		{"M 0.50 -0 l10.0,20", "M.5,0l10 20"},
+		{"M10,10 C20,20,30,30,40,40z", "M 10 10 C 20 20 30 30 40 40 z"},
+	}
+
+	for _, tt := range tests {
+		na := NormalizePathData(tt.a)
+		nb := NormalizePathData(tt.b)
+		if na != nb {
+			t.Errorf("NormalizePathData(%q) = %q, NormalizePathData(%q) = %q; want equal", tt.a, na, tt.b, nb)
+		}
+	}
+
+	if got := NormalizePathData("M0,0L10,10"); got != "M 0 0 L 10 10" {
+		t.Errorf("NormalizePathData = %q, want %q", got, "M 0 0 L 10 10")
+	}
+
+	// Relative and absolute commands are not equivalent
+	if NormalizePathData("M10 10h5") == NormalizePathData("M10 10H5") {
+		t.Error("relative and absolute commands should normalize differently")
+	}
+}
+
+func TestPathCommandsToStringRoundTrip(t *testing.T) {
+	paths := []string{
+		"M10,20L30,40H50V60Z",
+		"m10 10l20 0 0 20-20 0z",
+		"M0 0C10 -10 20 10 30 0S50 -10 60 0Q70 10 80 0T100 0",
+		"M10 50a40 40 0 1 1 80 0A40 40 0 0 1 10 50",
+		"M0 0a5 5 0 0110 0",
+		"M.5-.5h10.25v-3.125e1",
+	}
+	for _, d := range paths {
+		got := PathCommandsToString(ParsePath(d))
+		before := CalculatePathBounds(d)
+		after := CalculatePathBounds(got)
+		if *before != *after {
+			t.Errorf("PathCommandsToString(ParsePath(%q)) = %q: bounds %+v, want %+v", d, got, *after, *before)
+		}
+		if again := PathCommandsToString(ParsePath(got)); again != got {
+			t.Errorf("second round trip of %q = %q, want %q", d, again, got)
+		}
+	}
+}
+
+func TestPathCommandsToStringParamCounts(t *testing.T) {
+	cmds := []PathCommand{
+		{Command: 'M', Params: []float64{0, 0}},
+		{Command: 'L', Params: []float64{10, 10, 20}},
+		{Command: 'c', Params: []float64{1, 2, 3, 4, 5, 6, 7}},
+		{Command: 'Z', Params: []float64{1}},
+	}
+	want := "M 0 0 L 10 10 c 1 2 3 4 5 6 Z"
+	if got := PathCommandsToString(cmds); got != want {
+		t.Errorf("PathCommandsToString = %q, want %q", got, want)
+	}
+}
+
+func TestContentBoundsPercentageLengths(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="10%" y="10" width="50%" height="50%"/></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	box := ContentBounds(root)
+	if box.MinX != 10 || box.MinY != 10 || box.Width() != 50 || box.Height() != 50 {
+		t.Errorf("bounds = %+v, want 10,10 50x50", box)
+	}
+}
+
+func TestSplitSubpaths(t *testing.T) {
+	// Two glyphs: a square at the origin and a triangle placed with a
+	// relative move from the square's start point after Z.
+	d := "M0,0 L10,0 L10,10 L0,10 Z m20 0 l10 0 l-5 10 z"
+	got := SplitSubpaths(d)
+	want := []string{"M 0 0 L 10 0 L 10 10 L 0 10 Z", "M 20 0 l 10 0 l -5 10 z"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("SplitSubpaths = %q, want %q", got, want)
+	}
+
+	first, second := CalculatePathBounds(got[0]), CalculatePathBounds(got[1])
+	if first.MaxX >= second.MinX {
+		t.Errorf("subpath bounds overlap: %+v and %+v", first, second)
+	}
+	if second.MinX != 20 || second.MaxX != 30 {
+		t.Errorf("second subpath bounds = %+v, want x in [20, 30]", second)
+	}
+}
+
+func TestSplitSubpathsImplicitLineTo(t *testing.T) {
+	got := SplitSubpaths("M 0 0 H 5 m 1 1 2 2 3 3")
+	want := []string{"M 0 0 H 5", "M 6 1 l 2 2 3 3"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("SplitSubpaths = %q, want %q", got, want)
+	}
+	if got := SplitSubpaths(""); len(got) != 0 {
+		t.Errorf("SplitSubpaths(\"\") = %q, want none", got)
+	}
+}
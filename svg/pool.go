@@ -0,0 +1,51 @@
+package svg
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// MapFiles calls fn for each file using a pool of workers goroutines
+// (0 = GOMAXPROCS) and returns the results in the order of files. When ctx
+// is cancelled, workers stop picking up files and ctx.Err() is returned.
+func MapFiles[T any](ctx context.Context, files []string, workers int, fn func(filePath string) T) ([]T, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	results := make([]T, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i] = fn(files[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
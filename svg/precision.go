@@ -0,0 +1,78 @@
+package svg
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// coordinateAttrRe matches attributes that hold path data or coordinates.
+	coordinateAttrRe = regexp.MustCompile(`\s(d|points|x|y|x1|y1|x2|y2|cx|cy|r|rx|ry|width|height|viewBox)\s*=\s*["']([^"']*)["']`)
+	// precisionNumberRe matches a number, capturing its fraction and exponent.
+	precisionNumberRe = regexp.MustCompile(`[+-]?(?:\d+(?:\.(\d*))?|\.(\d+))(?:[eE]([+-]?\d+))?`)
+)
+
+// MaxCoordinatePrecision returns the maximum number of fractional digits
+// used by any number in path data or coordinate attributes. Exponents are
+// taken into account, so "1.5e-3" counts as four digits. Returns 0 for
+// content with only integer coordinates.
+func MaxCoordinatePrecision(content []byte) int {
+	maxDigits := 0
+	for _, attr := range coordinateAttrRe.FindAllSubmatch(content, -1) {
+		for _, m := range precisionNumberRe.FindAllSubmatch(attr[2], -1) {
+			if d := fractionalDigits(m); d > maxDigits {
+				maxDigits = d
+			}
+		}
+	}
+	return maxDigits
+}
+
+// CountExcessivePrecision returns the number of numbers in path data or
+// coordinate attributes with more than maxDigits fractional digits.
+func CountExcessivePrecision(content []byte, maxDigits int) int {
+	count := 0
+	for _, attr := range coordinateAttrRe.FindAllSubmatch(content, -1) {
+		for _, m := range precisionNumberRe.FindAllSubmatch(attr[2], -1) {
+			if fractionalDigits(m) > maxDigits {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// fractionalDigits returns the effective number of fractional digits for
+// a precisionNumberRe match.
+func fractionalDigits(m [][]byte) int {
+	digits := len(m[1]) + len(m[2])
+	if len(m[3]) > 0 {
+		exp, err := strconv.Atoi(string(m[3]))
+		if err == nil {
+			digits -= exp
+		}
+	}
+	if digits < 0 {
+		return 0
+	}
+	return digits
+}
+
+// RoundPathPrecision rounds the coordinates in path data to decimals
+// fractional digits, dropping trailing zeros, and re-serializes it with
+// single-space separators. Arc flags are kept as 0 or 1. A negative
+// decimals is treated as 0.
+func RoundPathPrecision(d string, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	scale := math.Pow10(decimals)
+	cmds := ParsePath(d)
+	for i := range cmds {
+		for j, v := range cmds[i].Params {
+			cmds[i].Params[j] = math.Round(v*scale) / scale
+		}
+	}
+	return PathCommandsToString(cmds)
+}
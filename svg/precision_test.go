@@ -0,0 +1,93 @@
+package svg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaxCoordinatePrecision(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{
+			name:    "integers only",
+			content: `<svg viewBox="0 0 100 100"><path d="M 10 10 L 90 90"/></svg>`,
+			want:    0,
+		},
+		{
+			name: "mixed precision",
+			content: `<svg viewBox="0 0 24 24">
+  <path d="M12.5 3.25L20.125 18 4 18z"/>
+  <circle cx="12" cy="12.3333" r="2.5"/>
+</svg>`,
+			want: 4,
+		},
+		{
+			name:    "compact path data",
+			content: `<svg viewBox="0 0 24 24"><path d="M.5.25l1.123-.5z"/></svg>`,
+			want:    3,
+		},
+		{
+			name:    "points attribute",
+			content: `<svg viewBox="0 0 24 24"><polygon points="1,2.12345 3,4"/></svg>`,
+			want:    5,
+		},
+		{
+			name:    "exponent",
+			content: `<svg viewBox="0 0 24 24"><path d="M1.5e-3 0L2e2 1"/></svg>`,
+			want:    4,
+		},
+		{
+			name:    "non-coordinate attributes ignored",
+			content: `<svg viewBox="0 0 24 24"><path opacity="0.123456" d="M1 1L2 2"/></svg>`,
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxCoordinatePrecision([]byte(tt.content)); got != tt.want {
+				t.Errorf("MaxCoordinatePrecision() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundPathPrecision(t *testing.T) {
+	d := "M10.123456789,20.987654321L30.000000001 40.499999999C50.111111111 60.222222222 70.333333333 80.444444444 90.555555555 10.666666666Z"
+	got := RoundPathPrecision(d, 2)
+	want := "M 10.12 20.99 L 30 40.5 C 50.11 60.22 70.33 80.44 90.56 10.67 Z"
+	if got != want {
+		t.Errorf("RoundPathPrecision = %q, want %q", got, want)
+	}
+	if len(got) >= len(d) {
+		t.Errorf("rounded path (%d bytes) not smaller than original (%d bytes)", len(got), len(d))
+	}
+
+	before := CalculatePathBounds(d)
+	after := CalculatePathBounds(got)
+	for _, diff := range []float64{before.MinX - after.MinX, before.MinY - after.MinY, before.MaxX - after.MaxX, before.MaxY - after.MaxY} {
+		if math.Abs(diff) > 0.01 {
+			t.Errorf("bounds changed by %v: before %+v, after %+v", diff, before, after)
+		}
+	}
+}
+
+func TestRoundPathPrecisionArcFlags(t *testing.T) {
+	tests := []struct {
+		d        string
+		decimals int
+		want     string
+	}{
+		{"M0 0A10.004 10.004 0 0 1 20.0049 0", 0, "M 0 0 A 10 10 0 0 1 20 0"},
+		{"M0 0a10 10 30.25 0110 10", 1, "M 0 0 a 10 10 30.3 0 1 10 10"},
+		{"M0 0a5,5,0,1,0,5.55,5 5 5 0 00-5.55-5", 1, "M 0 0 a 5 5 0 1 0 5.6 5 5 5 0 0 0 -5.6 -5"},
+	}
+	for _, tt := range tests {
+		if got := RoundPathPrecision(tt.d, tt.decimals); got != tt.want {
+			t.Errorf("RoundPathPrecision(%q, %d) = %q, want %q", tt.d, tt.decimals, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// PrettyOptions configures the pretty-printer.
+type PrettyOptions struct {
+	Indent         string // Indentation per nesting level (default two spaces)
+	SortAttributes bool   // Sort attributes, keeping xmlns and viewBox first
+}
+
+// Pretty re-indents SVG content with one element per line.
+// Elements containing text (e.g. <text>, <title>, <style>) are written on
+// a single line with their text preserved. Comments, the XML declaration,
+// and DOCTYPE are kept.
+func Pretty(content []byte, opts PrettyOptions) ([]byte, error) {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+
+	doc, err := parseXMLDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, n := range doc.children {
+		if n.kind == xmlText {
+			continue // whitespace between top-level nodes
+		}
+		writePrettyNode(&buf, n, 0, opts)
+	}
+	return buf.Bytes(), nil
+}
+
+// xmlNodeKind identifies the type of an xmlNode.
+type xmlNodeKind int
+
+const (
+	xmlElement xmlNodeKind = iota
+	xmlText
+	xmlComment
+	xmlProcInst
+	xmlDirective
+)
+
+// xmlNode is a minimal order-preserving XML tree used for re-serialization.
+// Names keep their original namespace prefix.
+type xmlNode struct {
+	kind     xmlNodeKind
+	name     string     // element name or processing instruction target
+	attrs    []xml.Attr // element attributes in document order
+	children []*xmlNode
+	text     string // text, comment, directive, or processing instruction content
+}
+
+// parseXMLDocument parses content into an xmlNode tree. The returned node
+// is a synthetic document root.
+func parseXMLDocument(content []byte) (*xmlNode, error) {
+	decoded, err := Decode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(decoded))
+	doc := &xmlNode{kind: xmlElement}
+	stack := []*xmlNode{doc}
+
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XML: %w", err)
+		}
+		parent := stack[len(stack)-1]
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{kind: xmlElement, name: qualifiedName(t.Name), attrs: t.Attr}
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("failed to parse XML: unexpected end element </%s>", qualifiedName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.children = append(parent.children, &xmlNode{kind: xmlText, text: string(t)})
+		case xml.Comment:
+			parent.children = append(parent.children, &xmlNode{kind: xmlComment, text: string(t)})
+		case xml.ProcInst:
+			parent.children = append(parent.children, &xmlNode{kind: xmlProcInst, name: t.Target, text: string(t.Inst)})
+		case xml.Directive:
+			parent.children = append(parent.children, &xmlNode{kind: xmlDirective, text: string(t)})
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("failed to parse XML: unclosed element <%s>", stack[len(stack)-1].name)
+	}
+	return doc, nil
+}
+
+// qualifiedName returns the name with its namespace prefix, if any.
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// hasText returns true if the element has non-whitespace text children.
+func (n *xmlNode) hasText() bool {
+	for _, c := range n.children {
+		if c.kind == xmlText && strings.TrimSpace(c.text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func writePrettyNode(buf *bytes.Buffer, n *xmlNode, depth int, opts PrettyOptions) {
+	indent := strings.Repeat(opts.Indent, depth)
+
+	switch n.kind {
+	case xmlText:
+		if strings.TrimSpace(n.text) != "" {
+			buf.WriteString(indent)
+			buf.WriteString(escapeText(strings.TrimSpace(n.text)))
+			buf.WriteByte('\n')
+		}
+	case xmlComment:
+		buf.WriteString(indent + "<!--" + n.text + "-->\n")
+	case xmlProcInst:
+		buf.WriteString(indent + "<?" + n.name + " " + n.text + "?>\n")
+	case xmlDirective:
+		buf.WriteString(indent + "<!" + n.text + ">\n")
+	case xmlElement:
+		buf.WriteString(indent)
+		if n.hasText() {
+			writeInlineNode(buf, n, opts)
+			buf.WriteByte('\n')
+			return
+		}
+		writeStartTag(buf, n, opts)
+		if len(elementChildren(n)) == 0 {
+			buf.WriteString("/>\n")
+			return
+		}
+		buf.WriteString(">\n")
+		for _, c := range n.children {
+			writePrettyNode(buf, c, depth+1, opts)
+		}
+		buf.WriteString(indent + "</" + n.name + ">\n")
+	}
+}
+
+// elementChildren returns children other than whitespace-only text.
+func elementChildren(n *xmlNode) []*xmlNode {
+	var children []*xmlNode
+	for _, c := range n.children {
+		if c.kind == xmlText && strings.TrimSpace(c.text) == "" {
+			continue
+		}
+		children = append(children, c)
+	}
+	return children
+}
+
+// writeInlineNode writes an element and its descendants without adding
+// or removing whitespace, preserving text content exactly.
+func writeInlineNode(buf *bytes.Buffer, n *xmlNode, opts PrettyOptions) {
+	switch n.kind {
+	case xmlText:
+		buf.WriteString(escapeText(n.text))
+	case xmlComment:
+		buf.WriteString("<!--" + n.text + "-->")
+	case xmlElement:
+		writeStartTag(buf, n, opts)
+		if len(n.children) == 0 {
+			buf.WriteString("/>")
+			return
+		}
+		buf.WriteByte('>')
+		for _, c := range n.children {
+			writeInlineNode(buf, c, opts)
+		}
+		buf.WriteString("</" + n.name + ">")
+	}
+}
+
+// writeStartTag writes "<name attr=...", without the closing bracket.
+func writeStartTag(buf *bytes.Buffer, n *xmlNode, opts PrettyOptions) {
+	attrs := n.attrs
+	if opts.SortAttributes {
+		attrs = sortedAttrs(attrs)
+	}
+	buf.WriteString("<" + n.name)
+	for _, a := range attrs {
+		buf.WriteString(" " + qualifiedName(a.Name) + `="` + escapeAttr(a.Value) + `"`)
+	}
+}
+
+// sortedAttrs returns attributes sorted alphabetically, with xmlns
+// declarations first and viewBox next.
+func sortedAttrs(attrs []xml.Attr) []xml.Attr {
+	sorted := make([]xml.Attr, len(attrs))
+	copy(sorted, attrs)
+	rank := func(a xml.Attr) int {
+		switch {
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			return 0
+		case a.Name.Space == "xmlns":
+			return 1
+		case a.Name.Space == "" && a.Name.Local == "viewBox":
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank(sorted[i]), rank(sorted[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return qualifiedName(sorted[i].Name) < qualifiedName(sorted[j].Name)
+	})
+	return sorted
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\n", "&#xA;", "\t", "&#x9;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
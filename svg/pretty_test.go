@@ -0,0 +1,50 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrettySortAttributes(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<svg width="24" viewBox="0 0 24 24" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns="http://www.w3.org/2000/svg"><g fill="#000" id="a"><path d="M 0 0 L 24 24" fill="#fff"/></g><text x="1" y="2">Hello <tspan>World</tspan></text></svg>`
+
+	got, err := Pretty([]byte(content), PrettyOptions{Indent: "  ", SortAttributes: true})
+	if err != nil {
+		t.Fatalf("Pretty error: %v", err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 24 24" width="24">
+  <g fill="#000" id="a">
+    <path d="M 0 0 L 24 24" fill="#fff"/>
+  </g>
+  <text x="1" y="2">Hello <tspan>World</tspan></text>
+</svg>
+`
+	if string(got) != want {
+		t.Errorf("Pretty() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := Parse(got); err != nil {
+		t.Errorf("pretty output should parse: %v", err)
+	}
+}
+
+func TestPrettyKeepsAttributeOrder(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10"><rect width="5" x="1" height="5"/></svg>`
+
+	got, err := Pretty([]byte(content), PrettyOptions{Indent: "\t"})
+	if err != nil {
+		t.Fatalf("Pretty error: %v", err)
+	}
+	if !strings.Contains(string(got), "\t<rect width=\"5\" x=\"1\" height=\"5\"/>\n") {
+		t.Errorf("expected tab-indented rect with original attribute order, got:\n%s", got)
+	}
+}
+
+func TestPrettyInvalidXML(t *testing.T) {
+	if _, err := Pretty([]byte(`<svg><g></svg>`), PrettyOptions{}); err == nil {
+		t.Error("expected error for malformed XML")
+	}
+}
@@ -0,0 +1,144 @@
+package svg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JoshVarga/svgparser"
+)
+
+// utf8BOM is the UTF-8 byte order mark some editors prepend to SVG files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// gzipMagic is the header prefix of gzip-compressed (svgz) content.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Default limits applied by Parse to guard against element bombs.
+const (
+	DefaultMaxElements = 100000
+	DefaultMaxDepth    = 256
+)
+
+// ErrLimitExceeded is returned when content exceeds the element count or
+// nesting depth limits.
+var ErrLimitExceeded = errors.New("SVG exceeds parse limits")
+
+// ErrEmptySVG is returned for content that is empty or only whitespace.
+var ErrEmptySVG = errors.New("empty SVG file")
+
+// CheckEmpty returns ErrEmptySVG if content is empty or only whitespace.
+func CheckEmpty(content []byte) error {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return ErrEmptySVG
+	}
+	return nil
+}
+
+// ParseOptions configures how SVG content is parsed.
+type ParseOptions struct {
+	Validate    bool // Passed through to svgparser validation
+	MaxElements int  // Maximum number of elements (0 = no limit)
+	MaxDepth    int  // Maximum element nesting depth (0 = no limit)
+}
+
+// DefaultParseOptions returns the parse options used by Parse.
+func DefaultParseOptions() ParseOptions {
+	return ParseOptions{
+		Validate:    false,
+		MaxElements: DefaultMaxElements,
+		MaxDepth:    DefaultMaxDepth,
+	}
+}
+
+// Decode normalizes raw SVG bytes for parsing.
+// It decompresses gzip (svgz) content and strips a leading UTF-8 BOM.
+// Content that is empty once decoded returns ErrEmptySVG.
+func Decode(content []byte) ([]byte, error) {
+	if bytes.HasPrefix(content, gzipMagic) {
+		zr, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open svgz content: %w", err)
+		}
+		defer func() { _ = zr.Close() }()
+		content, err = io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress svgz content: %w", err)
+		}
+	}
+	content = bytes.TrimPrefix(content, utf8BOM)
+	if err := CheckEmpty(content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// ReadFile reads an SVG or svgz file and returns its decoded content.
+func ReadFile(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(content)
+}
+
+// Parse parses SVG content into an svgparser element tree.
+// This is the canonical parse entry point used by the analysis packages.
+func Parse(content []byte) (*svgparser.Element, error) {
+	return ParseWithOptions(content, DefaultParseOptions())
+}
+
+// ParseWithOptions parses SVG content into an svgparser element tree
+// using the given options.
+func ParseWithOptions(content []byte, opts ParseOptions) (*svgparser.Element, error) {
+	decoded, err := Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckLimits(decoded, opts.MaxElements, opts.MaxDepth); err != nil {
+		return nil, err
+	}
+	return svgparser.Parse(bytes.NewReader(decoded), opts.Validate)
+}
+
+// CheckLimits streams through content and returns an error wrapping
+// ErrLimitExceeded if it has more than maxElements elements or nests them
+// deeper than maxDepth. A limit of 0 disables that check. Undefined
+// entities, bare ampersands and unquoted attributes are tolerated so that
+// counting continues past them; XML too malformed to tokenize returns an
+// error, since the limits cannot be verified past it.
+func CheckLimits(content []byte, maxElements, maxDepth int) error {
+	if maxElements <= 0 && maxDepth <= 0 {
+		return nil
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	dec.Strict = false
+	elements, depth := 0, 0
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check parse limits: %w", err)
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			elements++
+			depth++
+			if maxElements > 0 && elements > maxElements {
+				return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, maxElements)
+			}
+			if maxDepth > 0 && depth > maxDepth {
+				return fmt.Errorf("%w: nesting deeper than %d levels", ErrLimitExceeded, maxDepth)
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package svg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseIconViewBox(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("..", "brands", "aws", "icon_orig.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if root.Name != "svg" {
+		t.Errorf("root.Name = %q, want svg", root.Name)
+	}
+	if vb := root.Attributes["viewBox"]; vb != "0 0 304 182" {
+		t.Errorf("viewBox = %q, want %q", vb, "0 0 304 182")
+	}
+}
+
+func TestParseWithBOM(t *testing.T) {
+	content := append([]byte{0xEF, 0xBB, 0xBF}, []byte(`<svg viewBox="0 0 10 10"><rect width="5" height="5"/></svg>`)...)
+
+	root, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if len(root.Children) != 1 || root.Children[0].Name != "rect" {
+		t.Errorf("unexpected children: %v", root.Children)
+	}
+}
+
+func TestReadFileSVGZ(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "icon.svgz")
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(`<svg viewBox="0 0 24 24"><path d="M 0 0 L 24 24"/></svg>`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	root, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if vb := root.Attributes["viewBox"]; vb != "0 0 24 24" {
+		t.Errorf("viewBox = %q, want %q", vb, "0 0 24 24")
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	deep := `<svg viewBox="0 0 10 10">` + strings.Repeat("<g>", 300) + strings.Repeat("</g>", 300) + `</svg>`
+	if _, err := Parse([]byte(deep)); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("deep nesting: error = %v, want ErrLimitExceeded", err)
+	}
+
+	wide := `<svg viewBox="0 0 10 10">` + strings.Repeat(`<path d="M0 0"/>`, 50) + `</svg>`
+	opts := DefaultParseOptions()
+	opts.MaxElements = 20
+	if _, err := ParseWithOptions([]byte(wide), opts); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("element bomb: error = %v, want ErrLimitExceeded", err)
+	}
+
+	// Within the default limits
+	if _, err := Parse([]byte(wide)); err != nil {
+		t.Errorf("Parse error: %v", err)
+	}
+}
+
+func TestCheckLimitsMalformed(t *testing.T) {
+	// A malformed token ahead of the bomb must not end the check early
+	bomb := `<svg viewBox="0 0 10 10"><g a=1>` + strings.Repeat(`<g/>`, DefaultMaxElements+1) + `</g></svg>`
+	if err := CheckLimits([]byte(bomb), DefaultMaxElements, DefaultMaxDepth); err == nil {
+		t.Error("expected error for malformed element bomb")
+	}
+	if err := CheckLimits([]byte(`<svg viewBox="0 0 10 10"/>`), DefaultMaxElements, DefaultMaxDepth); err != nil {
+		t.Errorf("CheckLimits error: %v", err)
+	}
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	for _, content := range []string{"", "  \n\t ", "\xEF\xBB\xBF\n"} {
+		if _, err := Decode([]byte(content)); !errors.Is(err, ErrEmptySVG) {
+			t.Errorf("Decode(%q) error = %v, want ErrEmptySVG", content, err)
+		}
+	}
+	if _, err := Parse([]byte(" ")); !errors.Is(err, ErrEmptySVG) {
+		t.Errorf("Parse error = %v, want ErrEmptySVG", err)
+	}
+}
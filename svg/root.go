@@ -0,0 +1,168 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	rootStartTagRe = regexp.MustCompile(`<svg\b[^>]*>`)
+	rootEndTagRe   = regexp.MustCompile(`</svg\s*>\s*$`)
+	viewBoxAttrRe  = regexp.MustCompile(`(?i)\sviewbox\s*=\s*(?:"[^"]*"|'[^']*')`)
+)
+
+// SetViewBox sets the viewBox attribute of the root <svg> element, leaving
+// the rest of content untouched. An existing attribute is matched
+// case-insensitively, in either quote style, and rewritten as viewBox;
+// if the root element has none, one is inserted.
+func SetViewBox(content string, vb ViewBox) (string, error) {
+	loc := rootStartTagRe.FindStringIndex(content)
+	if loc == nil {
+		return "", fmt.Errorf("no <svg> root element found")
+	}
+	tag := content[loc[0]:loc[1]]
+	value := vb.String()
+	if m := viewBoxAttrRe.FindStringIndex(tag); m != nil {
+		// Keep the whitespace preceding the attribute.
+		tag = tag[:m[0]+1] + `viewBox="` + value + `"` + tag[m[1]:]
+	} else {
+		tag = setTagAttr(tag, "viewBox", value)
+	}
+	return content[:loc[0]] + tag + content[loc[1]:], nil
+}
+
+// RootStartTag returns the start tag of the root <svg> element, or "" if
+// content has none.
+func RootStartTag(content string) string {
+	return rootStartTagRe.FindString(content)
+}
+
+// SetTitle sets the <title> of the root <svg> element, leaving the rest of
+// content untouched. An existing root-level title, including an empty one,
+// is replaced; otherwise the title is inserted as the first child, and a
+// self-closing root is expanded to hold it. The title text is XML-escaped.
+func SetTitle(content, title string) (string, error) {
+	loc := rootStartTagRe.FindStringIndex(content)
+	if loc == nil {
+		return "", fmt.Errorf("no <svg> root element found")
+	}
+
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(title))
+	element := "<title>" + escaped.String() + "</title>"
+
+	tag := content[loc[0]:loc[1]]
+	if strings.HasSuffix(tag, "/>") {
+		tag = strings.TrimRight(strings.TrimSuffix(tag, "/>"), " \t\r\n")
+		return content[:loc[0]] + tag + ">" + element + "</svg>" + content[loc[1]:], nil
+	}
+	if start, end, ok := rootTitleSpan(content[loc[1]:]); ok {
+		return content[:loc[1]+start] + element + content[loc[1]+end:], nil
+	}
+	return content[:loc[1]] + element + content[loc[1]:], nil
+}
+
+// rootTitleSpan returns the byte span of the first <title> element at the
+// top level of inner, the content following the root start tag.
+func rootTitleSpan(inner string) (start, end int, ok bool) {
+	dec := xml.NewDecoder(strings.NewReader(inner))
+	depth := 0
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err != nil {
+			return 0, 0, false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 && t.Name.Space == "" && t.Name.Local == "title" {
+				start, ok = offset, true
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth < 0 {
+				return 0, 0, false // end of the root element
+			}
+			if depth == 0 && ok {
+				return start, int(dec.InputOffset()), true
+			}
+		}
+	}
+}
+
+// rootViewBox returns the viewBox of the root <svg> start tag, falling back
+// to "0 0 width height". It returns false if neither is usable.
+func rootViewBox(startTag string) (ViewBox, bool) {
+	if v := tagAttr(startTag, "viewBox"); v != "" {
+		vb, err := ParseViewBox(v)
+		return vb, err == nil
+	}
+	w := ParseFloat(tagAttr(startTag, "width"), 0)
+	h := ParseFloat(tagAttr(startTag, "height"), 0)
+	if w <= 0 || h <= 0 {
+		return ViewBox{}, false
+	}
+	return ViewBox{Width: w, Height: h}, true
+}
+
+// tagAttr returns the value of an attribute in a start tag, or "".
+func tagAttr(tag, name string) string {
+	re := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	m := re.FindStringSubmatch(tag)
+	if m == nil {
+		return ""
+	}
+	return m[1] + m[2]
+}
+
+// setTagAttr sets an attribute in a start tag, replacing any existing value.
+func setTagAttr(tag, name, value string) string {
+	re := regexp.MustCompile(`(\s` + regexp.QuoteMeta(name) + `\s*=\s*)(?:"[^"]*"|'[^']*')`)
+	if loc := re.FindStringSubmatchIndex(tag); loc != nil {
+		return tag[:loc[3]] + `"` + value + `"` + tag[loc[1]:]
+	}
+	insert := ` ` + name + `="` + value + `"`
+	if strings.HasSuffix(tag, "/>") {
+		return strings.TrimSuffix(tag, "/>") + insert + "/>"
+	}
+	return strings.TrimSuffix(tag, ">") + insert + ">"
+}
+
+// formatViewBox formats a viewBox attribute value at full precision.
+func formatViewBox(vb ViewBox) string {
+	return formatNumber(vb.X) + " " + formatNumber(vb.Y) + " " +
+		formatNumber(vb.Width) + " " + formatNumber(vb.Height)
+}
+
+// wrapRootContent sets the root viewBox to vb and wraps the children of the
+// root element in <g transform="...">.
+func wrapRootContent(content []byte, vb ViewBox, transform string) ([]byte, error) {
+	s := string(content)
+	loc := rootStartTagRe.FindStringIndex(s)
+	if loc == nil {
+		return nil, fmt.Errorf("no <svg> root element found")
+	}
+	startTag := setTagAttr(s[loc[0]:loc[1]], "viewBox", formatViewBox(vb))
+	if strings.HasSuffix(startTag, "/>") {
+		return []byte(s[:loc[0]] + startTag + s[loc[1]:]), nil
+	}
+
+	rest := s[loc[1]:]
+	end := rootEndTagRe.FindStringIndex(rest)
+	if end == nil {
+		return nil, fmt.Errorf("no closing </svg> tag found")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s[:loc[0]])
+	sb.WriteString(startTag)
+	sb.WriteString(`<g transform="` + transform + `">`)
+	sb.WriteString(rest[:end[0]])
+	sb.WriteString("</g>")
+	sb.WriteString(rest[end[0]:])
+	return []byte(sb.String()), nil
+}
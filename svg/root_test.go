@@ -0,0 +1,82 @@
+package svg
+
+import "testing"
+
+func TestSetViewBox(t *testing.T) {
+	vb := ViewBox{X: 1, Y: 2, Width: 30, Height: 40}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "double quotes",
+			content: `<svg viewBox="0 0 10 10"><rect/></svg>`,
+			want:    `<svg viewBox="1.0 2.0 30.0 40.0"><rect/></svg>`,
+		},
+		{
+			name:    "single quotes",
+			content: `<svg viewBox='0 0 10 10'><rect/></svg>`,
+			want:    `<svg viewBox="1.0 2.0 30.0 40.0"><rect/></svg>`,
+		},
+		{
+			name:    "uppercase attribute",
+			content: `<svg VIEWBOX="0 0 10 10" width="10"><rect/></svg>`,
+			want:    `<svg viewBox="1.0 2.0 30.0 40.0" width="10"><rect/></svg>`,
+		},
+		{
+			name:    "attribute on its own line",
+			content: "<svg\n  width=\"10\"\n  viewBox =\n \"0 0 10 10\">\n<rect/></svg>",
+			want:    "<svg\n  width=\"10\"\n  viewBox=\"1.0 2.0 30.0 40.0\">\n<rect/></svg>",
+		},
+		{
+			name:    "insert when missing",
+			content: `<svg width="10" height="10"><rect/></svg>`,
+			want:    `<svg width="10" height="10" viewBox="1.0 2.0 30.0 40.0"><rect/></svg>`,
+		},
+		{
+			name:    "only root element",
+			content: `<svg><svg viewBox="0 0 5 5"/><text>viewBox="0 0 5 5"</text></svg>`,
+			want:    `<svg viewBox="1.0 2.0 30.0 40.0"><svg viewBox="0 0 5 5"/><text>viewBox="0 0 5 5"</text></svg>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetViewBox(tt.content, vb)
+			if err != nil {
+				t.Fatalf("SetViewBox error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SetViewBox =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SetViewBox(`<html></html>`, vb); err == nil {
+		t.Error("expected error without a root <svg> element")
+	}
+}
+
+func TestSetTitle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`<svg viewBox="0 0 10 10"><path d="M0 0"/></svg>`, `<svg viewBox="0 0 10 10"><title>A&amp;B</title><path d="M0 0"/></svg>`},
+		{`<svg viewBox="0 0 10 10"><desc>d</desc><title>Old</title></svg>`, `<svg viewBox="0 0 10 10"><desc>d</desc><title>A&amp;B</title></svg>`},
+		{`<svg viewBox="0 0 10 10"><g><title>Part</title></g></svg>`, `<svg viewBox="0 0 10 10"><title>A&amp;B</title><g><title>Part</title></g></svg>`},
+		{`<svg viewBox="0 0 10 10"/>`, `<svg viewBox="0 0 10 10"><title>A&amp;B</title></svg>`},
+	}
+	for _, tt := range tests {
+		got, err := SetTitle(tt.in, "A&B")
+		if err != nil {
+			t.Fatalf("SetTitle(%s) error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("SetTitle(%s) =\n%s\nwant\n%s", tt.in, got, tt.want)
+		}
+	}
+	if _, err := SetTitle("<html/>", "A"); err == nil {
+		t.Error("expected error without a root <svg> element")
+	}
+}
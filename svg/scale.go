@@ -0,0 +1,48 @@
+package svg
+
+import (
+	"fmt"
+)
+
+// Scale uniformly scales an icon by factor. The root children are wrapped
+// in <g transform="scale(factor)"> and the viewBox is multiplied by factor,
+// so the rendered appearance is unchanged. Stroke widths scale with the
+// geometry through the transform. An SVG without a viewBox gets one derived
+// from its width and height.
+func Scale(content []byte, factor float64) ([]byte, error) {
+	if factor <= 0 {
+		return nil, fmt.Errorf("invalid scale factor: %g", factor)
+	}
+
+	loc := rootStartTagRe.FindIndex(content)
+	if loc == nil {
+		return nil, fmt.Errorf("no <svg> root element found")
+	}
+	vb, ok := rootViewBox(string(content[loc[0]:loc[1]]))
+	if !ok {
+		return nil, fmt.Errorf("no viewBox or width/height found")
+	}
+
+	scaled := ViewBox{
+		X:      vb.X * factor,
+		Y:      vb.Y * factor,
+		Width:  vb.Width * factor,
+		Height: vb.Height * factor,
+	}
+	return wrapRootContent(content, scaled, "scale("+formatNumber(factor)+")")
+}
+
+// Translate shifts an icon's content by (dx, dy) without changing the
+// viewBox. The root children are wrapped in <g transform="translate(dx,dy)">.
+// An SVG without a viewBox gets one derived from its width and height.
+func Translate(content []byte, dx, dy float64) ([]byte, error) {
+	loc := rootStartTagRe.FindIndex(content)
+	if loc == nil {
+		return nil, fmt.Errorf("no <svg> root element found")
+	}
+	vb, ok := rootViewBox(string(content[loc[0]:loc[1]]))
+	if !ok {
+		return nil, fmt.Errorf("no viewBox or width/height found")
+	}
+	return wrapRootContent(content, vb, "translate("+formatNumber(dx)+","+formatNumber(dy)+")")
+}
@@ -0,0 +1,74 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90" stroke-width="2"/></svg>`)
+
+	scaled, err := Scale(content, 2)
+	if err != nil {
+		t.Fatalf("Scale error: %v", err)
+	}
+
+	root, err := Parse(scaled)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if vb := root.Attributes["viewBox"]; vb != "0 0 200 200" {
+		t.Errorf("viewBox = %q, want %q", vb, "0 0 200 200")
+	}
+
+	// The point (10, 10) renders at (20, 20) in the scaled coordinate system
+	if len(root.Children) != 1 || root.Children[0].Attributes["transform"] != "scale(2)" {
+		t.Fatalf("content not wrapped in scale group: %s", scaled)
+	}
+	box := GetElementBounds(root.Children[0].Children[0])
+	if x, y := box.MinX*2, box.MinY*2; x != 20 || y != 20 {
+		t.Errorf("scaled point = (%g, %g), want (20, 20)", x, y)
+	}
+}
+
+func TestScaleWidthHeightOnly(t *testing.T) {
+	scaled, err := Scale([]byte(`<svg width="24" height="24"><rect width="24" height="24"/></svg>`), 0.5)
+	if err != nil {
+		t.Fatalf("Scale error: %v", err)
+	}
+	if !strings.Contains(string(scaled), `viewBox="0 0 12 12"`) {
+		t.Errorf("viewBox not derived from width/height: %s", scaled)
+	}
+}
+
+func TestScaleInvalidFactor(t *testing.T) {
+	if _, err := Scale([]byte(`<svg viewBox="0 0 10 10"/>`), 0); err == nil {
+		t.Error("expected error for zero factor")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><rect x="10" y="20" width="30" height="30"/></svg>`)
+
+	moved, err := Translate(content, 15, -5.5)
+	if err != nil {
+		t.Fatalf("Translate error: %v", err)
+	}
+
+	root, err := Parse(moved)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if vb := root.Attributes["viewBox"]; vb != "0 0 100 100" {
+		t.Errorf("viewBox = %q, want unchanged", vb)
+	}
+
+	// The point (10, 20) renders at (25, 14.5)
+	if len(root.Children) != 1 || root.Children[0].Attributes["transform"] != "translate(15,-5.5)" {
+		t.Fatalf("content not wrapped in translate group: %s", moved)
+	}
+	box := GetElementBounds(root.Children[0].Children[0])
+	if x, y := box.MinX+15, box.MinY-5.5; x != 25 || y != 14.5 {
+		t.Errorf("translated point = (%g, %g), want (25, 14.5)", x, y)
+	}
+}
@@ -0,0 +1,144 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// DirectoryScanOptions configures ScanDirectoryCtx.
+type DirectoryScanOptions struct {
+	Level       ScanLevel    // Scan level applied to each file
+	Recursive   bool         // Scan the whole directory tree
+	Workers     int          // Number of concurrent workers (0 = GOMAXPROCS)
+	MaxElements int          // Per-file element limit, as in ScanOptions
+	MaxDepth    int          // Per-file nesting depth limit, as in ScanOptions
+	Logger      *slog.Logger // Receives structured events; nil discards them
+}
+
+// ScanReader scans SVG content read from r, e.g. an HTTP request body.
+func ScanReader(r io.Reader, level ScanLevel) (*Result, error) {
+	return ScanReaderCtx(context.Background(), r, level)
+}
+
+// ScanReaderCtx scans SVG content read from r, aborting when ctx is
+// cancelled while reading or scanning.
+func ScanReaderCtx(ctx context.Context, r io.Reader, level ScanLevel) (*Result, error) {
+	content, err := io.ReadAll(&ctxReader{ctx: ctx, r: r})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	return scanContent(ctx, string(content), nil, ScanOptions{Level: level})
+}
+
+// ctxReader is an io.Reader that fails once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// scanFileFunc scans a single file.
+type scanFileFunc func(ctx context.Context, filePath string, opts ScanOptions) (*Result, error)
+
+// scanFileCtx scans a single file.
+func scanFileCtx(ctx context.Context, filePath string, opts ScanOptions) (*Result, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	result := &Result{
+		FilePath:     filePath,
+		IsSecure:     true,
+		Threats:      []Threat{},
+		ThreatCounts: make(map[ThreatType]int),
+		Errors:       []string{},
+	}
+	return scanContent(ctx, string(content), result, opts)
+}
+
+// ScanDirectoryCtx scans the SVG files in a directory concurrently. Results
+// are sorted by FilePath. Per-file errors are recorded in their Result. When
+// ctx is cancelled, workers stop picking up files and ctx.Err() is returned.
+func ScanDirectoryCtx(ctx context.Context, dirPath string, opts DirectoryScanOptions) ([]*Result, error) {
+	return scanDirectory(ctx, dirPath, opts, scanFileCtx)
+}
+
+// scanDirectory implements ScanDirectoryCtx, scanning each file with scanFile.
+func scanDirectory(ctx context.Context, dirPath string, opts DirectoryScanOptions, scanFile scanFileFunc) ([]*Result, error) {
+	var files []string
+	var results []*Result
+	if opts.Recursive {
+		var pathErrs []*svg.PathError
+		var err error
+		files, pathErrs, err = svg.WalkSVGFilesContext(ctx, dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+		for _, pe := range pathErrs {
+			results = append(results, &Result{
+				FilePath:     pe.Path,
+				IsSecure:     false,
+				ThreatCounts: make(map[ThreatType]int),
+				Errors:       []string{pe.Err.Error()},
+			})
+		}
+	} else {
+		var err error
+		files, err = svg.ListSVGFiles(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+	}
+
+	log := svg.LoggerOrDiscard(opts.Logger)
+	fileResults, err := svg.MapFiles(ctx, files, opts.Workers, func(filePath string) *Result {
+		result, err := scanFile(ctx, filePath, ScanOptions{
+			Level:       opts.Level,
+			MaxElements: opts.MaxElements,
+			MaxDepth:    opts.MaxDepth,
+		})
+		logScanResult(log, filePath, result, err)
+		if err != nil {
+			return &Result{
+				FilePath:     filePath,
+				IsSecure:     false,
+				ThreatCounts: make(map[ThreatType]int),
+				Errors:       []string{err.Error()},
+			}
+		}
+		return result
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results = append(results, fileResults...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].FilePath < results[j].FilePath
+	})
+	return results, nil
+}
+
+// logScanResult logs the outcome of scanning a single file.
+func logScanResult(log *slog.Logger, filePath string, result *Result, err error) {
+	switch {
+	case err != nil:
+		log.Error("scan failed", "file", filePath, "action", "scan", "error", err)
+	case !result.IsSuccess():
+		log.Warn("threats found", "file", filePath, "action", "scan", "outcome", "insecure", "threats", len(result.Threats))
+	default:
+		log.Info("scanned", "file", filePath, "action", "scan", "outcome", "secure")
+	}
+}
@@ -0,0 +1,141 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func writeTestIcons(t *testing.T, dir string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		content := `<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/></svg>`
+		if i%10 == 0 {
+			content = `<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`
+		}
+		name := filepath.Join(dir, fmt.Sprintf("icon%03d.svg", i))
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanDirectoryCtx(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIcons(t, dir, 50)
+
+	results, err := ScanDirectoryCtx(context.Background(), dir, DirectoryScanOptions{Workers: 4})
+	if err != nil {
+		t.Fatalf("ScanDirectoryCtx error: %v", err)
+	}
+	if len(results) != 50 {
+		t.Fatalf("got %d results, want 50", len(results))
+	}
+
+	insecure := 0
+	for i, r := range results {
+		if i > 0 && results[i-1].FilePath > r.FilePath {
+			t.Errorf("results not sorted at %d", i)
+		}
+		if !r.IsSuccess() {
+			insecure++
+		}
+	}
+	if insecure != 5 {
+		t.Errorf("got %d insecure files, want 5", insecure)
+	}
+}
+
+func TestScanDirectoryCtxCancel(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIcons(t, dir, 500)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel partway through the scan
+	var scanned atomic.Int32
+	scanFile := func(ctx context.Context, filePath string, opts ScanOptions) (*Result, error) {
+		if scanned.Add(1) == 20 {
+			cancel()
+		}
+		return scanFileCtx(ctx, filePath, opts)
+	}
+
+	results, err := scanDirectory(ctx, dir, DirectoryScanOptions{Workers: 2}, scanFile)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if results != nil {
+		t.Errorf("expected nil results on cancellation, got %d", len(results))
+	}
+	if n := scanned.Load(); n >= 500 {
+		t.Errorf("scanned %d files, expected the scan to stop early", n)
+	}
+}
+
+func TestDirectoryRecursiveContextCancel(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"a", "b", "c"} {
+		subDir := filepath.Join(dir, sub)
+		if err := os.Mkdir(subDir, 0750); err != nil {
+			t.Fatal(err)
+		}
+		writeTestIcons(t, subDir, 100)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var scanned atomic.Int32
+	scanFile := func(ctx context.Context, filePath string, opts ScanOptions) (*Result, error) {
+		if scanned.Add(1) == 20 {
+			cancel()
+		}
+		return scanFileCtx(ctx, filePath, opts)
+	}
+
+	opts := DirectoryScanOptions{Level: ScanLevelStrict, Recursive: true}
+	if _, err := scanDirectory(ctx, dir, opts, scanFile); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if n := scanned.Load(); n >= 300 {
+		t.Errorf("scanned %d files, expected the scan to stop early", n)
+	}
+
+	// A context cancelled before the walk stops it immediately
+	if _, err := DirectoryRecursiveContext(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestScanReaderCtx(t *testing.T) {
+	result, err := ScanReaderCtx(context.Background(), strings.NewReader(`<svg><script>alert(1)</script></svg>`), ScanLevelStrict)
+	if err != nil {
+		t.Fatalf("ScanReaderCtx error: %v", err)
+	}
+	if result.IsSuccess() {
+		t.Error("expected script to be detected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ScanReaderCtx(ctx, strings.NewReader(`<svg/>`), ScanLevelStrict); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestScanReader(t *testing.T) {
+	result, err := ScanReader(strings.NewReader(`<svg><script>alert(1)</script></svg>`), ScanLevelStrict)
+	if err != nil {
+		t.Fatalf("ScanReader error: %v", err)
+	}
+	if result.IsSecure {
+		t.Error("expected script to be detected")
+	}
+}
@@ -73,8 +73,67 @@ type ListItem struct {
 	Status Status `json:"status,omitempty"`
 }
 
-// GenerateReport creates a TeamReport from scan results.
+// ReportOptions configures how scan results are mapped to report statuses.
+type ReportOptions struct {
+	// SeverityStatus maps a threat severity ("critical", "high", "medium",
+	// "low", "info") to the status reported when threats of that severity
+	// are found. Severities missing from the map use the default mapping.
+	SeverityStatus map[string]Status
+}
+
+// DefaultReportOptions returns options with the default severity mapping:
+// critical/high threats are NO-GO and medium/low threats are WARN.
+func DefaultReportOptions() ReportOptions {
+	return ReportOptions{
+		SeverityStatus: defaultSeverityStatus(),
+	}
+}
+
+func defaultSeverityStatus() map[string]Status {
+	return map[string]Status{
+		"critical": StatusNoGo,
+		"high":     StatusNoGo,
+		"medium":   StatusWarn,
+		"low":      StatusWarn,
+		"info":     StatusWarn,
+	}
+}
+
+// statusFor returns the configured status for a severity.
+func (o ReportOptions) statusFor(severity string) Status {
+	if status, ok := o.SeverityStatus[severity]; ok {
+		return status
+	}
+	if status, ok := defaultSeverityStatus()[severity]; ok {
+		return status
+	}
+	return StatusWarn
+}
+
+// statusRank orders statuses from least to most severe.
+func statusRank(s Status) int {
+	switch s {
+	case StatusNoGo:
+		return 3
+	case StatusWarn:
+		return 2
+	case StatusGo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// GenerateReport creates a TeamReport from scan results using the default
+// severity-to-status mapping.
 func GenerateReport(results []*Result, project, version string) *TeamReport {
+	return GenerateReportWithOptions(results, project, version, DefaultReportOptions())
+}
+
+// GenerateReportWithOptions creates a TeamReport from scan results using
+// the given options to determine the GO/NO-GO status. Any scan error makes
+// the report NO-GO.
+func GenerateReportWithOptions(results []*Result, project, version string, opts ReportOptions) *TeamReport {
 	report := &TeamReport{
 		Schema:      "https://raw.githubusercontent.com/agentplexus/multi-agent-spec/main/schema/report/team-report.schema.json",
 		Title:       "SVG SECURITY SCAN REPORT",
@@ -87,15 +146,10 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 	}
 
 	// Count totals
-	totalFiles := len(results)
-	secureFiles := 0
 	threatsByType := make(map[ThreatType]int)
 	var allThreats []Threat
 
 	for _, r := range results {
-		if r.IsSuccess() {
-			secureFiles++
-		}
 		for _, t := range r.Threats {
 			threatsByType[t.Type]++
 			allThreats = append(allThreats, t)
@@ -104,30 +158,41 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 
 	// Determine overall status
 	report.Status = StatusGo
-	if len(allThreats) > 0 {
-		// Check if any critical/high threats
-		hasCritical := threatsByType[ThreatScript] > 0 || threatsByType[ThreatEventHandler] > 0
-		hasHigh := threatsByType[ThreatExternalRef] > 0 || threatsByType[ThreatXMLEntity] > 0
-
-		if hasCritical || hasHigh {
-			report.Status = StatusNoGo
-		} else {
-			report.Status = StatusWarn
+	for threatType, count := range threatsByType {
+		if count == 0 {
+			continue
+		}
+		if status := opts.statusFor(threatType.Severity()); statusRank(status) > statusRank(report.Status) {
+			report.Status = status
 		}
 	}
 
-	// Summary block
-	report.SummaryBlocks = []ContentBlock{
-		{
-			Type: "kv_pairs",
-			Pairs: []KVPair{
-				{Key: "Files Scanned", Value: formatInt(totalFiles)},
-				{Key: "Secure Files", Value: formatInt(secureFiles)},
-				{Key: "Files with Threats", Value: formatInt(totalFiles - secureFiles)},
-				{Key: "Total Threats", Value: formatInt(len(allThreats))},
-			},
-		},
+	report.SummaryBlocks = summaryBlocks(results)
+
+	// Files that could not be scanned cannot be reported as safe
+	errorSection := TeamSection{
+		ID:     "scan-errors",
+		Name:   "Scan Errors",
+		Status: StatusGo,
+		Tasks:  []TaskResult{{ID: "scan", Status: StatusGo, Detail: "All files scanned"}},
+	}
+	var errorItems []ListItem
+	for _, r := range results {
+		for _, e := range r.Errors {
+			errorItems = append(errorItems, ListItem{Icon: "🔴", Text: r.FilePath + ": " + e, Status: StatusNoGo})
+		}
+	}
+	if len(errorItems) > 0 {
+		report.Status = StatusNoGo
+		errorSection.Status = StatusNoGo
+		errorSection.Tasks = []TaskResult{{
+			ID:     "scan",
+			Status: StatusNoGo,
+			Detail: formatInt(len(errorItems)) + " scan error(s)",
+		}}
+		errorSection.ContentBlocks = []ContentBlock{{Type: "list", Title: "Errors", Items: errorItems}}
 	}
+	report.Teams = append(report.Teams, errorSection)
 
 	// Create team sections for each threat category
 	threatCategories := []struct {
@@ -140,9 +205,10 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 		{"event-handler-detection", "Event Handler Detection", ThreatEventHandler, "critical"},
 		{"external-ref-detection", "External Reference Detection", ThreatExternalRef, "high"},
 		{"xml-entity-detection", "XML Entity Detection", ThreatXMLEntity, "high"},
+		{"event-animation-detection", "Event Animation Detection", ThreatEventAnimation, "high"},
 		{"animation-detection", "Animation Detection", ThreatAnimation, "medium"},
-		{"style-block-detection", "Style Block Detection", ThreatStyleBlock, "low"},
 		{"link-detection", "Link Detection", ThreatLink, "medium"},
+		{"style-block-detection", "Style Block Detection", ThreatStyleBlock, "low"},
 	}
 
 	for _, cat := range threatCategories {
@@ -163,14 +229,7 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 			}
 		} else {
 			// Determine status based on severity
-			switch cat.severity {
-			case "critical", "high":
-				section.Status = StatusNoGo
-			case "medium":
-				section.Status = StatusWarn
-			default:
-				section.Status = StatusWarn
-			}
+			section.Status = opts.statusFor(cat.severity)
 
 			section.Tasks = []TaskResult{
 				{
@@ -250,19 +309,19 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 			})
 			actionNum++
 		}
-		if threatsByType[ThreatAnimation] > 0 {
+		if threatsByType[ThreatEventAnimation] > 0 {
 			actionItems = append(actionItems, KVPair{
-				Icon:  "🟡",
+				Icon:  "🔴",
 				Key:   formatInt(actionNum),
-				Value: "Remove animation elements for static images (MEDIUM)",
+				Value: "Remove interaction-triggered animation elements (HIGH)",
 			})
 			actionNum++
 		}
-		if threatsByType[ThreatStyleBlock] > 0 {
+		if threatsByType[ThreatAnimation] > 0 {
 			actionItems = append(actionItems, KVPair{
-				Icon:  "🟢",
+				Icon:  "🟡",
 				Key:   formatInt(actionNum),
-				Value: "Consider inlining styles and removing style blocks (LOW)",
+				Value: "Remove animation elements for static images (MEDIUM)",
 			})
 			actionNum++
 		}
@@ -272,6 +331,14 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 				Key:   formatInt(actionNum),
 				Value: "Remove anchor elements for static images (MEDIUM)",
 			})
+			actionNum++
+		}
+		if threatsByType[ThreatStyleBlock] > 0 {
+			actionItems = append(actionItems, KVPair{
+				Icon:  "🟢",
+				Key:   formatInt(actionNum),
+				Value: "Consider inlining styles and removing style blocks (LOW)",
+			})
 		}
 
 		if len(actionItems) > 0 {
@@ -288,6 +355,29 @@ func GenerateReport(results []*Result, project, version string) *TeamReport {
 	return report
 }
 
+// summaryBlocks returns the file and threat totals block for a report.
+func summaryBlocks(results []*Result) []ContentBlock {
+	secureFiles := 0
+	totalThreats := 0
+	for _, r := range results {
+		if r.IsSuccess() {
+			secureFiles++
+		}
+		totalThreats += len(r.Threats)
+	}
+	return []ContentBlock{
+		{
+			Type: "kv_pairs",
+			Pairs: []KVPair{
+				{Key: "Files Scanned", Value: formatInt(len(results))},
+				{Key: "Secure Files", Value: formatInt(secureFiles)},
+				{Key: "Files with Threats", Value: formatInt(len(results) - secureFiles)},
+				{Key: "Total Threats", Value: formatInt(totalThreats)},
+			},
+		},
+	}
+}
+
 // ToJSON converts the report to JSON bytes.
 func (r *TeamReport) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(r, "", "  ")
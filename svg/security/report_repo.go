@@ -0,0 +1,112 @@
+package security
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rootSectionID is the section ID for files directly under the scan root.
+const rootSectionID = "(root)"
+
+// GenerateRepoReport creates a TeamReport with one section per top-level
+// directory under root (e.g. one per brand), so each team sees the status
+// of its own icons. It uses the default severity-to-status mapping.
+func GenerateRepoReport(results []*Result, root, project, version string) *TeamReport {
+	return GenerateRepoReportWithOptions(results, root, project, version, DefaultReportOptions())
+}
+
+// GenerateRepoReportWithOptions creates a per-directory TeamReport using
+// the given options to determine statuses. Each section has one task per
+// file; files that could not be scanned are reported as WARN.
+func GenerateRepoReportWithOptions(results []*Result, root, project, version string, opts ReportOptions) *TeamReport {
+	report := &TeamReport{
+		Schema:        "https://raw.githubusercontent.com/agentplexus/multi-agent-spec/main/schema/report/team-report.schema.json",
+		Title:         "SVG SECURITY SCAN REPORT",
+		Project:       project,
+		Version:       version,
+		Phase:         "SECURITY VALIDATION",
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		GeneratedBy:   "brandkit security-scan",
+		SummaryBlocks: summaryBlocks(results),
+		Teams:         []TeamSection{},
+		Status:        StatusGo,
+	}
+
+	groups := map[string][]*Result{}
+	for _, r := range results {
+		id := topLevelDir(root, r.FilePath)
+		groups[id] = append(groups[id], r)
+	}
+
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		section := TeamSection{ID: id, Name: id, Status: StatusGo}
+		for _, r := range groups[id] {
+			task := fileTask(root, r, opts)
+			if statusRank(task.Status) > statusRank(section.Status) {
+				section.Status = task.Status
+			}
+			section.Tasks = append(section.Tasks, task)
+		}
+		if statusRank(section.Status) > statusRank(report.Status) {
+			report.Status = section.Status
+		}
+		report.Teams = append(report.Teams, section)
+	}
+
+	return report
+}
+
+// fileTask summarizes the scan result of a single file as a task.
+func fileTask(root string, r *Result, opts ReportOptions) TaskResult {
+	task := TaskResult{ID: relativePath(root, r.FilePath), Status: StatusGo, Detail: "No threats detected"}
+
+	if len(r.Errors) > 0 {
+		task.Status = StatusWarn
+		task.Detail = "Scan error: " + strings.Join(r.Errors, "; ")
+		return task
+	}
+	if len(r.Threats) == 0 {
+		return task
+	}
+
+	worst := r.Threats[0].Type
+	for _, t := range r.Threats {
+		if status := opts.statusFor(t.Type.Severity()); statusRank(status) > statusRank(task.Status) {
+			task.Status = status
+		}
+		if t.Type.SeverityRank() > worst.SeverityRank() {
+			worst = t.Type
+		}
+	}
+	task.Severity = worst.Severity()
+	task.Detail = formatInt(len(r.Threats)) + " threat(s) detected"
+	return task
+}
+
+// topLevelDir returns the first path segment of filePath under root, or
+// rootSectionID for files directly in root.
+func topLevelDir(root, filePath string) string {
+	rel := relativePath(root, filePath)
+	dir, _, found := strings.Cut(rel, "/")
+	if !found {
+		return rootSectionID
+	}
+	return dir
+}
+
+// relativePath returns filePath relative to root using forward slashes.
+func relativePath(root, filePath string) string {
+	rel, err := filepath.Rel(root, filePath)
+	if err != nil {
+		rel = filePath
+	}
+	return filepath.ToSlash(rel)
+}
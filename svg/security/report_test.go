@@ -0,0 +1,92 @@
+package security
+
+import (
+	"testing"
+)
+
+func TestGenerateReportSeverityMapping(t *testing.T) {
+	// Animation threats are medium severity
+	result := ScanContent(`<svg viewBox="0 0 100 100"><animate attributeName="x" to="10"/></svg>`, nil)
+	results := []*Result{result}
+
+	report := GenerateReport(results, "test", "1.0.0")
+	if report.Status != StatusWarn {
+		t.Errorf("default Status = %s, want %s", report.Status, StatusWarn)
+	}
+
+	opts := DefaultReportOptions()
+	opts.SeverityStatus["medium"] = StatusNoGo
+
+	report = GenerateReportWithOptions(results, "test", "1.0.0", opts)
+	if report.Status != StatusNoGo {
+		t.Errorf("Status = %s, want %s", report.Status, StatusNoGo)
+	}
+	for _, section := range report.Teams {
+		if section.ID == "animation-detection" && section.Status != StatusNoGo {
+			t.Errorf("animation section Status = %s, want %s", section.Status, StatusNoGo)
+		}
+	}
+}
+
+func TestGenerateReportPartialMapping(t *testing.T) {
+	result := ScanContent(`<svg viewBox="0 0 100 100"><script>alert(1)</script></svg>`, nil)
+
+	// Severities missing from the map fall back to the default mapping
+	report := GenerateReportWithOptions([]*Result{result}, "test", "1.0.0", ReportOptions{
+		SeverityStatus: map[string]Status{"medium": StatusNoGo},
+	})
+	if report.Status != StatusNoGo {
+		t.Errorf("Status = %s, want %s", report.Status, StatusNoGo)
+	}
+}
+
+func TestGenerateReportClean(t *testing.T) {
+	result := ScanContent(`<svg viewBox="0 0 100 100"><path d="M 0 0 L 10 10"/></svg>`, nil)
+
+	report := GenerateReport([]*Result{result}, "test", "1.0.0")
+	if report.Status != StatusGo {
+		t.Errorf("Status = %s, want %s", report.Status, StatusGo)
+	}
+}
+
+func TestGenerateReportScanErrors(t *testing.T) {
+	clean := ScanContent(`<svg viewBox="0 0 100 100"><path d="M 0 0 L 10 10"/></svg>`, nil)
+	empty := ScanContent(" \n", nil)
+
+	report := GenerateReport([]*Result{clean, empty}, "test", "1.0.0")
+	if report.Status != StatusNoGo {
+		t.Errorf("Status = %s, want %s", report.Status, StatusNoGo)
+	}
+	for _, section := range report.Teams {
+		if section.ID == "scan-errors" && section.Status != StatusNoGo {
+			t.Errorf("scan-errors section Status = %s, want %s", section.Status, StatusNoGo)
+		}
+	}
+}
+
+func TestGenerateRepoReport(t *testing.T) {
+	results := []*Result{
+		ScanContent(`<svg viewBox="0 0 10 10"><path d="M0 0L10 10"/></svg>`, &Result{FilePath: "brands/aws/icon.svg", IsSecure: true, ThreatCounts: map[ThreatType]int{}}),
+		ScanContent(`<svg viewBox="0 0 10 10"><path d="M0 0L10 10"/></svg>`, &Result{FilePath: "brands/aws/icon_white.svg", IsSecure: true, ThreatCounts: map[ThreatType]int{}}),
+		ScanContent(`<svg viewBox="0 0 10 10"><script>alert(1)</script></svg>`, &Result{FilePath: "brands/evil/icon.svg", IsSecure: true, ThreatCounts: map[ThreatType]int{}}),
+	}
+
+	report := GenerateRepoReport(results, "brands", "test", "1.0.0")
+	if report.Status != StatusNoGo {
+		t.Errorf("Status = %s, want %s", report.Status, StatusNoGo)
+	}
+	if len(report.Teams) != 2 {
+		t.Fatalf("got %d sections, want 2", len(report.Teams))
+	}
+
+	aws, evil := report.Teams[0], report.Teams[1]
+	if aws.ID != "aws" || aws.Status != StatusGo || len(aws.Tasks) != 2 {
+		t.Errorf("aws section = %s %s with %d tasks, want aws GO with 2", aws.ID, aws.Status, len(aws.Tasks))
+	}
+	if evil.ID != "evil" || evil.Status != StatusNoGo {
+		t.Errorf("evil section = %s %s, want evil NO-GO", evil.ID, evil.Status)
+	}
+	if evil.Tasks[0].ID != "evil/icon.svg" || evil.Tasks[0].Severity != "critical" {
+		t.Errorf("evil task = %+v", evil.Tasks[0])
+	}
+}
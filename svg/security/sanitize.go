@@ -2,18 +2,26 @@ package security
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/grokify/mogo/os/osutil"
+
+	"github.com/grokify/brandkit/svg"
 )
 
 // SanitizeOptions specifies which threat types to remove during sanitization.
 type SanitizeOptions struct {
-	RemoveScripts       bool // Remove script elements and javascript: URIs
-	RemoveEventHandlers bool // Remove on* event handler attributes
-	RemoveExternalRefs  bool // Remove external URLs and foreignObject
-	RemoveAll           bool // Remove all threat types (overrides individual flags)
+	RemoveScripts       bool         // Remove script elements and javascript: URIs
+	RemoveEventHandlers bool         // Remove on* event handler attributes
+	RemoveExternalRefs  bool         // Remove external URLs and foreignObject
+	RemoveAll           bool         // Remove all threat types (overrides individual flags)
+	RemoveComments      bool         // Remove XML comments, which can hide smuggled data
+	RemoveStyleBlocks   bool         // Remove <style> elements; not implied by RemoveAll, since icons often rely on them for colors
+	KeepIDs             []string     // Ids of known-safe style blocks exempt from removal, e.g. a required <style id="vars">
+	Logger              *slog.Logger // Receives structured events; nil discards them
 }
 
 // DefaultSanitizeOptions returns options that remove all threats.
@@ -28,7 +36,7 @@ type SanitizeResult struct {
 	InputPath      string
 	OutputPath     string
 	ThreatsRemoved []Threat
-	Sanitized      bool
+	Sanitized      bool // True if the output differs from the input
 	Error          error
 }
 
@@ -74,6 +82,16 @@ var xmlEntityRemovalPatterns = []sanitizePattern{
 
 // External reference removal patterns.
 var externalRefRemovalPatterns = []sanitizePattern{
+	// Remove HTML embedding elements with their content
+	{regexp.MustCompile(`(?is)<iframe\b[^>]*>.*?</iframe\s*>`), "", "iframe element", ThreatExternalRef},
+	{regexp.MustCompile(`(?i)<iframe\b[^>]*/>`), "", "self-closing iframe element", ThreatExternalRef},
+	{regexp.MustCompile(`(?is)<object\b[^>]*>.*?</object\s*>`), "", "object element", ThreatExternalRef},
+	{regexp.MustCompile(`(?i)<object\b[^>]*/>`), "", "self-closing object element", ThreatExternalRef},
+	// Any tags left over are unbalanced; remove them on their own
+	{regexp.MustCompile(`(?i)</?iframe\b[^>]*>`), "", "unclosed iframe element", ThreatExternalRef},
+	{regexp.MustCompile(`(?i)</?object\b[^>]*>`), "", "unclosed object element", ThreatExternalRef},
+	// embed is a void element; also drop any stray closing tag
+	{regexp.MustCompile(`(?i)<embed\b[^>]*>(?:\s*</embed\s*>)?`), "", "embed element", ThreatExternalRef},
 	// Replace external href with empty
 	{regexp.MustCompile(`(?i)(href\s*=\s*["'])https?://[^"']*["']`), `$1#"`, "external href", ThreatExternalRef},
 	// Replace external xlink:href with empty
@@ -86,8 +104,22 @@ var externalRefRemovalPatterns = []sanitizePattern{
 	{regexp.MustCompile(`(?i)(url\s*\(\s*["']?)https?://[^)"']+([)"']?)`), "${1}none${2}", "external URL in style", ThreatExternalRef},
 }
 
+// Style block removal patterns.
+var styleBlockRemovalPatterns = []sanitizePattern{
+	{regexp.MustCompile(`(?is)<style\b[^>]*>.*?</style\s*>`), "", "style block", ThreatStyleBlock},
+	{regexp.MustCompile(`(?i)<style\b[^>]*/>`), "", "self-closing style block", ThreatStyleBlock},
+}
+
+// idAttrRe matches an id attribute within a start tag.
+var idAttrRe = regexp.MustCompile(`\sid\s*=\s*["']([^"']*)["']`)
+
 // Sanitize removes security threats from an SVG file and writes the result.
 func Sanitize(inputPath, outputPath string, opts SanitizeOptions) (*SanitizeResult, error) {
+	return sanitizeFile(inputPath, outputPath, opts, sanitizePatternsFor(opts))
+}
+
+// sanitizeFile implements Sanitize with the given removal patterns.
+func sanitizeFile(inputPath, outputPath string, opts SanitizeOptions, patterns []sanitizePattern) (*SanitizeResult, error) {
 	result := &SanitizeResult{
 		InputPath:      inputPath,
 		OutputPath:     outputPath,
@@ -95,30 +127,39 @@ func Sanitize(inputPath, outputPath string, opts SanitizeOptions) (*SanitizeResu
 		Sanitized:      false,
 	}
 
+	log := svg.LoggerOrDiscard(opts.Logger)
 	content, err := os.ReadFile(inputPath)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to read input file: %w", err)
+		log.Error("read failed", "file", inputPath, "action", "sanitize", "error", result.Error)
 		return result, result.Error
 	}
 
-	sanitized, threats := SanitizeContent(string(content), opts)
+	sanitized, threats := sanitizeContent(string(content), opts, patterns)
+	sanitized = string(svg.DetectLineEndings(content).Apply([]byte(sanitized)))
 	result.ThreatsRemoved = threats
-	result.Sanitized = len(threats) > 0
+	// A pattern can match without changing the content (e.g. an already
+	// neutralized value), so compare the output rather than counting matches.
+	result.Sanitized = sanitized != string(content)
 
 	if err := osutil.WriteFileSecure(outputPath, []byte(sanitized), 0600); err != nil {
 		result.Error = fmt.Errorf("failed to write output file: %w", err)
+		log.Error("write failed", "file", outputPath, "action", "sanitize", "error", result.Error)
 		return result, result.Error
 	}
 
+	log.Info("sanitized", "file", inputPath, "action", "sanitize", "output", outputPath,
+		"changed", result.Sanitized, "threats_removed", len(threats))
 	return result, nil
 }
 
 // SanitizeContent removes security threats from SVG content in memory.
 func SanitizeContent(content string, opts SanitizeOptions) (string, []Threat) {
-	var threats []Threat
-	sanitized := content
+	return sanitizeContent(content, opts, sanitizePatternsFor(opts))
+}
 
-	// Collect all patterns to apply based on options
+// sanitizePatternsFor returns the removal patterns selected by opts.
+func sanitizePatternsFor(opts SanitizeOptions) []sanitizePattern {
 	var patterns []sanitizePattern
 	if opts.RemoveAll || opts.RemoveScripts {
 		patterns = append(patterns, scriptRemovalPatterns...)
@@ -132,10 +173,31 @@ func SanitizeContent(content string, opts SanitizeOptions) (string, []Threat) {
 	if opts.RemoveAll {
 		patterns = append(patterns, xmlEntityRemovalPatterns...)
 	}
+	if opts.RemoveStyleBlocks {
+		patterns = append(patterns, styleBlockRemovalPatterns...)
+	}
+	return patterns
+}
+
+// sanitizeContent applies patterns to content, honoring opts.KeepIDs and
+// opts.RemoveComments.
+func sanitizeContent(content string, opts SanitizeOptions, patterns []sanitizePattern) (string, []Threat) {
+	var threats []Threat
+	sanitized := content
+
+	keep := make(map[string]bool, len(opts.KeepIDs))
+	for _, id := range opts.KeepIDs {
+		keep[id] = true
+	}
+
+	if opts.RemoveComments {
+		sanitized = string(svg.StripComments([]byte(sanitized)))
+	}
 
 	// Apply each pattern
 	for _, p := range patterns {
-		matches := p.pattern.FindAllString(sanitized, -1)
+		var matches []string
+		sanitized, matches = applySanitizePattern(sanitized, p, keep)
 		for _, match := range matches {
 			displayMatch := match
 			if len(displayMatch) > 80 {
@@ -147,8 +209,59 @@ func SanitizeContent(content string, opts SanitizeOptions) (string, []Threat) {
 				Match:       displayMatch,
 			})
 		}
-		sanitized = p.pattern.ReplaceAllString(sanitized, p.replacement)
 	}
 
 	return sanitized, threats
 }
+
+// applySanitizePattern replaces each match of p in content, skipping
+// matches in elements whose id is in keep if p's threat type is keepable.
+// It returns the updated content and the matches that were replaced.
+func applySanitizePattern(content string, p sanitizePattern, keep map[string]bool) (string, []string) {
+	locs := p.pattern.FindAllStringSubmatchIndex(content, -1)
+	if len(locs) == 0 {
+		return content, nil
+	}
+
+	var sb strings.Builder
+	var replaced []string
+	last := 0
+	for _, loc := range locs {
+		if len(keep) > 0 && keepableThreat(p.threatType) && keep[enclosingElementID(content, loc[0])] {
+			continue
+		}
+		sb.WriteString(content[last:loc[0]])
+		sb.Write(p.pattern.ExpandString(nil, p.replacement, content, loc))
+		replaced = append(replaced, content[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(content[last:])
+	return sb.String(), replaced
+}
+
+// keepableThreat reports whether KeepIDs can exempt threats of type t.
+// Ids come from the untrusted file, so only style blocks can be kept;
+// scripts, event handlers, and external references are always removed.
+func keepableThreat(t ThreatType) bool {
+	return t == ThreatStyleBlock
+}
+
+// enclosingElementID returns the id of the element whose start tag begins
+// at pos or contains it, or "" if it has none.
+func enclosingElementID(content string, pos int) string {
+	start := pos
+	if content[pos] != '<' {
+		start = strings.LastIndex(content[:pos], "<")
+		if start < 0 {
+			return ""
+		}
+	}
+	end := strings.Index(content[start:], ">")
+	if end < 0 {
+		return ""
+	}
+	if m := idAttrRe.FindStringSubmatch(content[start : start+end+1]); m != nil {
+		return m[1]
+	}
+	return ""
+}
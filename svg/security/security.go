@@ -2,9 +2,14 @@
 package security
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/grokify/brandkit/svg"
 )
@@ -27,6 +32,9 @@ const (
 	ThreatLink
 	// ThreatXMLEntity indicates DOCTYPE or ENTITY declarations (XXE risk).
 	ThreatXMLEntity
+	// ThreatEventAnimation indicates animation elements triggered by user
+	// interaction (e.g. begin="button.click").
+	ThreatEventAnimation
 )
 
 // String returns a human-readable name for the threat type.
@@ -46,17 +54,50 @@ func (t ThreatType) String() string {
 		return "link"
 	case ThreatXMLEntity:
 		return "xml_entity"
+	case ThreatEventAnimation:
+		return "event_animation"
 	default:
 		return "unknown"
 	}
 }
 
+// MarshalJSON encodes the threat type as its String name, such as
+// "script", so reports stay readable and stable if constants are reordered.
+func (t ThreatType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a threat type from its String name.
+func (t *ThreatType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("threat type must be a string: %w", err)
+	}
+	return t.UnmarshalText([]byte(name))
+}
+
+// MarshalText encodes the threat type as its String name. It is used for
+// map keys such as Result.ThreatCounts.
+func (t ThreatType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a threat type from its String name.
+func (t *ThreatType) UnmarshalText(text []byte) error {
+	parsed, ok := threatTypeByName(string(text))
+	if !ok {
+		return fmt.Errorf("unknown threat type: %q", text)
+	}
+	*t = parsed
+	return nil
+}
+
 // Severity returns the severity level for a threat type.
 func (t ThreatType) Severity() string {
 	switch t {
 	case ThreatScript, ThreatEventHandler:
 		return "critical"
-	case ThreatExternalRef, ThreatXMLEntity:
+	case ThreatExternalRef, ThreatXMLEntity, ThreatEventAnimation:
 		return "high"
 	case ThreatAnimation, ThreatLink:
 		return "medium"
@@ -67,6 +108,31 @@ func (t ThreatType) Severity() string {
 	}
 }
 
+// SeverityRank returns a numeric rank for the threat type's severity,
+// where higher values are more severe (critical is highest).
+func (t ThreatType) SeverityRank() int {
+	switch t.Severity() {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SortThreatsBySeverity sorts threats in place, most severe first.
+// Threats of equal severity keep their original order.
+func SortThreatsBySeverity(threats []Threat) {
+	sort.SliceStable(threats, func(i, j int) bool {
+		return threats[i].Type.SeverityRank() > threats[j].Type.SeverityRank()
+	})
+}
+
 // Threat represents a detected security threat in an SVG file.
 type Threat struct {
 	Type        ThreatType
@@ -76,11 +142,12 @@ type Threat struct {
 
 // Result contains the result of scanning an SVG file for security threats.
 type Result struct {
-	FilePath     string
-	IsSecure     bool
-	Threats      []Threat
-	ThreatCounts map[ThreatType]int
-	Errors       []string
+	FilePath          string
+	IsSecure          bool
+	Threats           []Threat
+	ThreatCounts      map[ThreatType]int
+	SuppressedThreats []Threat // Threats allowed by brandkit:allow comments
+	Errors            []string
 }
 
 // IsSuccess returns true if the file is secure and has no errors.
@@ -121,6 +188,10 @@ var externalRefPatterns = []threatPattern{
 	// External use references (internal #id refs are OK)
 	{regexp.MustCompile(`(?i)<use[^>]+xlink:href\s*=\s*["']https?://`), "external use reference", ThreatExternalRef, 100},
 	{regexp.MustCompile(`(?i)<use[^>]+href\s*=\s*["']https?://`), "external use reference", ThreatExternalRef, 100},
+	// HTML embedding elements, dangerous when the SVG is inlined into HTML
+	{regexp.MustCompile(`(?i)<iframe\b[^>]*>`), "iframe element", ThreatExternalRef, 100},
+	{regexp.MustCompile(`(?i)<embed\b[^>]*>`), "embed element", ThreatExternalRef, 100},
+	{regexp.MustCompile(`(?i)<object\b[^>]*>`), "object element", ThreatExternalRef, 100},
 }
 
 // Animation patterns detect SVG animation elements.
@@ -132,6 +203,12 @@ var animationPatterns = []threatPattern{
 	{regexp.MustCompile(`(?i)<set\b[^>]*\b(attributeName|to)\s*=`), "set element", ThreatAnimation, 50},
 }
 
+// Event animation patterns detect animation elements whose begin/end
+// timing is tied to user interaction events rather than the document timeline.
+var eventAnimationPatterns = []threatPattern{
+	{regexp.MustCompile(`(?i)<(?:set|animate(?:Transform|Motion|Color)?)\b[^>]*\s(?:begin|end)\s*=\s*["'][^"']*(?:\b(?:click|dblclick|mouse(?:down|up|over|out|move|enter|leave)|focus(?:in|out)?|blur|activate|key(?:down|up|press)|touch(?:start|end|move)|pointer(?:down|up|over|out|enter|leave|move))\b|accessKey\()`), "event-triggered animation", ThreatEventAnimation, 80},
+}
+
 // Style block patterns detect <style> elements.
 var styleBlockPatterns = []threatPattern{
 	{regexp.MustCompile(`(?i)<style\b`), "style element", ThreatStyleBlock, 50},
@@ -158,6 +235,30 @@ const (
 	ScanLevelStandard
 )
 
+// String returns the name of the scan level.
+func (l ScanLevel) String() string {
+	switch l {
+	case ScanLevelStrict:
+		return "strict"
+	case ScanLevelStandard:
+		return "standard"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseScanLevel parses a scan level name ("strict" or "standard").
+func ParseScanLevel(s string) (ScanLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "strict":
+		return ScanLevelStrict, nil
+	case "standard":
+		return ScanLevelStandard, nil
+	default:
+		return ScanLevelStrict, fmt.Errorf("invalid scan level: %s (expected strict or standard)", s)
+	}
+}
+
 // patternsForLevel returns patterns based on scan level.
 func patternsForLevel(level ScanLevel) []threatPattern {
 	var all []threatPattern
@@ -169,12 +270,13 @@ func patternsForLevel(level ScanLevel) []threatPattern {
 	// Always include high severity threats
 	all = append(all, externalRefPatterns...)
 	all = append(all, xmlEntityPatterns...)
+	all = append(all, eventAnimationPatterns...)
 
 	// Include medium/low severity threats only in strict mode
 	if level == ScanLevelStrict {
 		all = append(all, animationPatterns...)
-		all = append(all, styleBlockPatterns...)
 		all = append(all, linkPatterns...)
+		all = append(all, styleBlockPatterns...)
 	}
 
 	return all
@@ -187,20 +289,64 @@ func SVG(filePath string) (*Result, error) {
 
 // SVGWithLevel scans a single SVG file with specified scan level.
 func SVGWithLevel(filePath string, level ScanLevel) (*Result, error) {
-	result := &Result{
-		FilePath:     filePath,
-		IsSecure:     true,
-		Threats:      []Threat{},
-		ThreatCounts: make(map[ThreatType]int),
-		Errors:       []string{},
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	defer func() { _ = f.Close() }()
 
-	content, err := os.ReadFile(filePath)
+	result, err := ScanReader(f, level)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
+	result.FilePath = filePath
+	return result, nil
+}
+
+// allowDirectivePattern matches per-file allow comments such as
+// <!-- brandkit:allow style_block animation -->.
+var allowDirectivePattern = regexp.MustCompile(`<!--\s*brandkit:allow\s+([^>]*?)\s*-->`)
+
+// allThreatTypes lists every threat type, used to resolve names.
+var allThreatTypes = []ThreatType{
+	ThreatScript,
+	ThreatEventHandler,
+	ThreatExternalRef,
+	ThreatAnimation,
+	ThreatStyleBlock,
+	ThreatLink,
+	ThreatXMLEntity,
+	ThreatEventAnimation,
+}
+
+// threatTypeByName returns the threat type whose String is name.
+func threatTypeByName(name string) (ThreatType, bool) {
+	for _, t := range allThreatTypes {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
 
-	return ScanContentWithLevel(string(content), result, level), nil
+// allowedThreatTypes returns the threat types allowed by brandkit:allow
+// comments in the content. Names may be separated by spaces or commas.
+// The directive comes from the scanned file itself, so only medium and low
+// severity threats can be allowed; scripts, event handlers, external
+// references, and entities cannot be smuggled past the scanner.
+func allowedThreatTypes(content string) map[ThreatType]bool {
+	allowed := map[ThreatType]bool{}
+	for _, m := range allowDirectivePattern.FindAllStringSubmatch(content, -1) {
+		names := strings.FieldsFunc(m[1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		})
+		for _, name := range names {
+			if t, ok := threatTypeByName(name); ok && t.SeverityRank() <= ThreatAnimation.SeverityRank() {
+				allowed[t] = true
+			}
+		}
+	}
+	return allowed
 }
 
 // ScanContent scans SVG content for security threats using strict level.
@@ -209,7 +355,51 @@ func ScanContent(content string, result *Result) *Result {
 }
 
 // ScanContentWithLevel scans SVG content for security threats with specified level.
+// Threat types allowed by a <!-- brandkit:allow type ... --> comment in the
+// content are recorded in SuppressedThreats instead of Threats.
 func ScanContentWithLevel(content string, result *Result, level ScanLevel) *Result {
+	return ScanContentWithOptions(content, result, ScanOptions{Level: level})
+}
+
+// ScanOptions configures ScanContentWithOptions.
+type ScanOptions struct {
+	Level       ScanLevel // Scan level
+	FailFast    bool      // Stop at the first threat instead of collecting all of them
+	MaxElements int       // Maximum number of elements (0 = svg.DefaultMaxElements, negative = no limit)
+	MaxDepth    int       // Maximum element nesting depth (0 = svg.DefaultMaxDepth, negative = no limit)
+}
+
+// limits returns the element count and depth limits to check, applying
+// the svg package defaults to unset values.
+func (o ScanOptions) limits() (maxElements, maxDepth int) {
+	maxElements, maxDepth = o.MaxElements, o.MaxDepth
+	if maxElements == 0 {
+		maxElements = svg.DefaultMaxElements
+	}
+	if maxDepth == 0 {
+		maxDepth = svg.DefaultMaxDepth
+	}
+	return maxElements, maxDepth
+}
+
+// ScanContentWithOptions scans SVG content for security threats. With
+// FailFast, scanning stops at the first threat, so Threats holds at most
+// one entry. Patterns are checked in severity order, critical first.
+func ScanContentWithOptions(content string, result *Result, opts ScanOptions) *Result {
+	result, _ = scanContent(context.Background(), content, result, opts)
+	return result
+}
+
+// IsSecure reports whether content passes a security scan at the given
+// level. It stops at the first threat, making it cheaper than a full scan
+// when only an accept/reject decision is needed.
+func IsSecure(content []byte, level ScanLevel) bool {
+	return ScanContentWithOptions(string(content), nil, ScanOptions{Level: level, FailFast: true}).IsSuccess()
+}
+
+// scanContent scans content, checking ctx between patterns so that scans
+// of large content can be cancelled.
+func scanContent(ctx context.Context, content string, result *Result, opts ScanOptions) (*Result, error) {
 	if result == nil {
 		result = &Result{
 			IsSecure:     true,
@@ -219,8 +409,30 @@ func ScanContentWithLevel(content string, result *Result, level ScanLevel) *Resu
 		}
 	}
 
-	for _, p := range patternsForLevel(level) {
-		matches := p.pattern.FindAllString(content, -1)
+	if err := svg.CheckEmpty([]byte(content)); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+
+	// Element bombs are reported as errors. Malformed XML is not: the
+	// patterns below match text, and entity payloads are often malformed.
+	maxElements, maxDepth := opts.limits()
+	if err := svg.CheckLimits([]byte(content), maxElements, maxDepth); errors.Is(err, svg.ErrLimitExceeded) {
+		result.IsSecure = false
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	allowed := allowedThreatTypes(content)
+
+	for _, p := range patternsForLevel(opts.Level) {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		limit := -1
+		if opts.FailFast && !allowed[p.threatType] {
+			limit = 1
+		}
+		matches := p.pattern.FindAllString(content, limit)
 		for _, match := range matches {
 			// Truncate match for display
 			displayMatch := match
@@ -232,64 +444,45 @@ func ScanContentWithLevel(content string, result *Result, level ScanLevel) *Resu
 				displayMatch = displayMatch[:maxLen] + "..."
 			}
 
-			result.Threats = append(result.Threats, Threat{
+			threat := Threat{
 				Type:        p.threatType,
 				Description: p.desc,
 				Match:       displayMatch,
-			})
+			}
+			if allowed[p.threatType] {
+				result.SuppressedThreats = append(result.SuppressedThreats, threat)
+				continue
+			}
+			result.Threats = append(result.Threats, threat)
 			result.ThreatCounts[p.threatType]++
 			result.IsSecure = false
+			if opts.FailFast {
+				return result, nil
+			}
 		}
 	}
 
-	return result
+	return result, nil
 }
 
-// Directory scans all SVG files in a directory (non-recursive).
+// Directory scans all SVG files in a directory (non-recursive) in strict
+// mode, concurrently using GOMAXPROCS workers. Results are sorted by
+// FilePath.
 func Directory(dirPath string) ([]*Result, error) {
-	files, err := svg.ListSVGFiles(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
-	}
-
-	var results []*Result
-	for _, filePath := range files {
-		result, err := SVG(filePath)
-		if err != nil {
-			results = append(results, &Result{
-				FilePath: filePath,
-				IsSecure: false,
-				Errors:   []string{err.Error()},
-			})
-			continue
-		}
-		results = append(results, result)
-	}
-
-	return results, nil
+	return ScanDirectoryCtx(context.Background(), dirPath, DirectoryScanOptions{Level: ScanLevelStrict})
 }
 
-// DirectoryRecursive scans all SVG files in a directory tree.
+// DirectoryRecursive scans all SVG files in a directory tree in strict
+// mode, concurrently using GOMAXPROCS workers. Results are sorted by
+// FilePath. Unreadable subdirectories are reported as failed Results and
+// do not stop the walk.
 func DirectoryRecursive(dirPath string) ([]*Result, error) {
-	files, err := svg.ListSVGFilesRecursive(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
-	}
-
-	var results []*Result
-	for _, filePath := range files {
-		result, err := SVG(filePath)
-		if err != nil {
-			results = append(results, &Result{
-				FilePath:     filePath,
-				IsSecure:     false,
-				ThreatCounts: make(map[ThreatType]int),
-				Errors:       []string{err.Error()},
-			})
-			continue
-		}
-		results = append(results, result)
-	}
+	return DirectoryRecursiveContext(context.Background(), dirPath)
+}
 
-	return results, nil
+// DirectoryRecursiveContext is like DirectoryRecursive but stops and
+// returns ctx.Err() once ctx is cancelled, e.g. when a server request
+// times out.
+func DirectoryRecursiveContext(ctx context.Context, dirPath string) ([]*Result, error) {
+	return ScanDirectoryCtx(ctx, dirPath, DirectoryScanOptions{Level: ScanLevelStrict, Recursive: true})
 }
@@ -1,10 +1,14 @@
 package security
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/grokify/brandkit/svg"
 )
 
 func TestSVGSecure(t *testing.T) {
@@ -240,6 +244,43 @@ func TestDirectory(t *testing.T) {
 	}
 }
 
+func TestDirectoryRecursiveUnreadableEntry(t *testing.T) {
+	dir := t.TempDir()
+
+	secure := `<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/></svg>`
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "secure.svg"), []byte(secure), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// A dangling symlink is listed by the walk but cannot be read
+	if err := os.Symlink(filepath.Join(dir, "missing.svg"), filepath.Join(dir, "broken.svg")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	results, err := DirectoryRecursive(dir)
+	if err != nil {
+		t.Fatalf("DirectoryRecursive error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	var failed, passed int
+	for _, r := range results {
+		switch {
+		case len(r.Errors) > 0:
+			failed++
+		case r.IsSuccess():
+			passed++
+		}
+	}
+	if failed != 1 || passed != 1 {
+		t.Errorf("failed = %d, passed = %d; want 1, 1", failed, passed)
+	}
+}
+
 func TestIsSuccess(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -271,6 +312,7 @@ func TestThreatTypeString(t *testing.T) {
 		{ThreatStyleBlock, "style_block"},
 		{ThreatLink, "link"},
 		{ThreatXMLEntity, "xml_entity"},
+		{ThreatEventAnimation, "event_animation"},
 		{ThreatType(99), "unknown"},
 	}
 
@@ -281,6 +323,51 @@ func TestThreatTypeString(t *testing.T) {
 	}
 }
 
+func TestThreatJSONRoundTrip(t *testing.T) {
+	threat := Threat{Type: ThreatEventHandler, Description: "onclick handler", Match: `onclick="x()"`}
+	data, err := json.Marshal(threat)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), `"Type":"event_handler"`) {
+		t.Errorf("expected type name in JSON, got %s", data)
+	}
+
+	var decoded Threat
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if decoded != threat {
+		t.Errorf("round trip = %+v, want %+v", decoded, threat)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Type":"bogus"}`), &decoded); err == nil {
+		t.Error("expected error for unknown threat type name")
+	}
+	if err := json.Unmarshal([]byte(`{"Type":1}`), &decoded); err == nil {
+		t.Error("expected error for numeric threat type")
+	}
+}
+
+func TestThreatCountsJSONKeys(t *testing.T) {
+	result := Result{ThreatCounts: map[ThreatType]int{ThreatScript: 2}}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), `"ThreatCounts":{"script":2}`) {
+		t.Errorf("expected threat type names as map keys, got %s", data)
+	}
+
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if decoded.ThreatCounts[ThreatScript] != 2 {
+		t.Errorf("ThreatCounts = %v, want script: 2", decoded.ThreatCounts)
+	}
+}
+
 func TestThreatTypeSeverity(t *testing.T) {
 	tests := []struct {
 		threatType ThreatType
@@ -290,6 +377,7 @@ func TestThreatTypeSeverity(t *testing.T) {
 		{ThreatEventHandler, "critical"},
 		{ThreatExternalRef, "high"},
 		{ThreatXMLEntity, "high"},
+		{ThreatEventAnimation, "high"},
 		{ThreatAnimation, "medium"},
 		{ThreatLink, "medium"},
 		{ThreatStyleBlock, "low"},
@@ -302,6 +390,172 @@ func TestThreatTypeSeverity(t *testing.T) {
 	}
 }
 
+func TestScanContentAllowDirective(t *testing.T) {
+	content := `<!-- brandkit:allow style_block -->
+<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <style>.a { fill: red; }</style>
+  <path class="a" d="M 0 0 L 10 10"/>
+</svg>`
+
+	result := ScanContentWithLevel(content, nil, ScanLevelStrict)
+	if !result.IsSuccess() {
+		t.Errorf("expected allowed style block to pass, got threats: %v", result.Threats)
+	}
+	if len(result.SuppressedThreats) != 1 {
+		t.Fatalf("got %d suppressed threats, want 1", len(result.SuppressedThreats))
+	}
+	if result.SuppressedThreats[0].Type != ThreatStyleBlock {
+		t.Errorf("suppressed Type = %s, want %s", result.SuppressedThreats[0].Type, ThreatStyleBlock)
+	}
+}
+
+func TestScanContentAllowDirectiveCritical(t *testing.T) {
+	content := `<!-- brandkit:allow script, style_block -->
+<svg viewBox="0 0 100 100"><script>alert(1)</script></svg>`
+
+	result := ScanContentWithLevel(content, nil, ScanLevelStrict)
+	if result.IsSuccess() {
+		t.Error("critical threats must not be suppressible")
+	}
+	if len(result.SuppressedThreats) != 0 {
+		t.Errorf("got %d suppressed threats, want 0", len(result.SuppressedThreats))
+	}
+}
+
+func TestScanContentAllowDirectiveHigh(t *testing.T) {
+	content := `<!-- brandkit:allow xml_entity external_ref event_animation -->
+<!DOCTYPE svg [<!ENTITY xxe SYSTEM "file:///etc/passwd">]>
+<svg viewBox="0 0 100 100"><image href="https://evil.example/x.png"/></svg>`
+
+	result := ScanContentWithLevel(content, nil, ScanLevelStrict)
+	if result.IsSuccess() {
+		t.Error("high severity threats must not be suppressible")
+	}
+	if len(result.SuppressedThreats) != 0 {
+		t.Errorf("got %d suppressed threats, want 0", len(result.SuppressedThreats))
+	}
+}
+
+func TestScanContentElementBomb(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10">` + strings.Repeat("<g>", 1000) + strings.Repeat("</g>", 1000) + `</svg>`
+
+	result := ScanContent(content, nil)
+	if result.IsSuccess() {
+		t.Error("expected deeply nested content to be rejected")
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("got errors %v, want 1", result.Errors)
+	}
+}
+
+func TestScanContentLimits(t *testing.T) {
+	content := `<svg viewBox="0 0 10 10">` + strings.Repeat(`<path d="M0 0"/>`, 50) + `</svg>`
+
+	if result := ScanContentWithOptions(content, nil, ScanOptions{MaxElements: 20}); result.IsSuccess() {
+		t.Error("expected content over MaxElements to be rejected")
+	}
+	if result := ScanContentWithOptions(content, nil, ScanOptions{}); !result.IsSuccess() {
+		t.Errorf("expected content within the default limits to pass, got errors %v", result.Errors)
+	}
+
+	malformed := `<svg viewBox="0 0 10 10"><g a=1>` + strings.Repeat(`<g/>`, 200) + `</g></svg>`
+	if result := ScanContentWithOptions(malformed, nil, ScanOptions{MaxElements: 100}); result.IsSuccess() {
+		t.Error("expected malformed element bomb to be rejected")
+	}
+}
+
+func TestScanContentEntityPayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"xxe", `<?xml version="1.0"?>
+<!DOCTYPE svg [<!ENTITY xxe SYSTEM "file:///etc/passwd">]>
+<svg viewBox="0 0 10 10"><text>&xxe;</text></svg>`},
+		{"billion laughs", `<?xml version="1.0"?>
+<!DOCTYPE svg [
+  <!ENTITY lol "lol">
+  <!ENTITY lol1 "&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;">
+  <!ENTITY lol2 "&lol1;&lol1;&lol1;&lol1;&lol1;&lol1;&lol1;&lol1;&lol1;&lol1;">
+]>
+<svg viewBox="0 0 10 10"><text>&lol2;</text></svg>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ScanContent(tt.content, nil)
+			if result.ThreatCounts[ThreatXMLEntity] == 0 {
+				t.Errorf("expected xml_entity threats, got %v (errors %v)", result.Threats, result.Errors)
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("unexpected errors %v", result.Errors)
+			}
+		})
+	}
+
+	// Undefined entities alone are malformed but not an error
+	result := ScanContent(`<svg viewBox="0 0 10 10"><text>a&nbsp;b</text></svg>`, nil)
+	if !result.IsSuccess() {
+		t.Errorf("expected &nbsp; content to pass, got threats %v errors %v", result.Threats, result.Errors)
+	}
+}
+
+func TestSVGEmbeddingElements(t *testing.T) {
+	tests := []struct {
+		name    string
+		element string
+	}{
+		{"iframe", `<iframe src="https://evil.example.com/"></iframe>`},
+		{"embed", `<embed src="payload.swf" type="application/x-shockwave-flash"/>`},
+		{"object", `<object data="payload.html" type="text/html"><p>fallback</p></object>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/>` + tt.element + `</svg>`
+
+			result := ScanContentWithLevel(content, nil, ScanLevelStandard)
+			if result.IsSuccess() {
+				t.Fatalf("expected %s element to be detected", tt.name)
+			}
+			if result.ThreatCounts[ThreatExternalRef] == 0 {
+				t.Errorf("ThreatCounts = %v, want external_ref", result.ThreatCounts)
+			}
+
+			sanitized, threats := SanitizeContent(content, DefaultSanitizeOptions())
+			if len(threats) == 0 {
+				t.Error("expected sanitize to report removed threats")
+			}
+			if strings.Contains(strings.ToLower(sanitized), "<"+tt.name) {
+				t.Errorf("%s element not removed: %s", tt.name, sanitized)
+			}
+			if rescan := ScanContent(sanitized, nil); !rescan.IsSuccess() {
+				t.Errorf("sanitized content still has threats: %v", rescan.Threats)
+			}
+		})
+	}
+}
+
+func TestSanitizeUnclosedEmbeddingElements(t *testing.T) {
+	for _, element := range []string{
+		`<iframe src="https://evil.example.com/">`,
+		`<object data="payload.html" type="text/html">`,
+	} {
+		content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/>` + element + `</svg>`
+
+		if result := ScanContent(content, nil); result.IsSuccess() {
+			t.Fatalf("expected %s to be detected", element)
+		}
+		sanitized, threats := SanitizeContent(content, DefaultSanitizeOptions())
+		if len(threats) == 0 {
+			t.Errorf("%s: expected sanitize to report removed threats", element)
+		}
+		if rescan := ScanContent(sanitized, nil); !rescan.IsSuccess() {
+			t.Errorf("%s: sanitized content still has threats: %v\n%s", element, rescan.Threats, sanitized)
+		}
+	}
+}
+
 func TestSVGAnimation(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "test.svg")
@@ -618,3 +872,248 @@ func TestSanitizeNoChanges(t *testing.T) {
 		t.Errorf("expected 0 threats removed, got %d", len(result.ThreatsRemoved))
 	}
 }
+
+func TestSortThreatsBySeverity(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100">
+  <style>.a { fill: red }</style>
+  <script>alert('XSS')</script>
+</svg>`
+
+	result := ScanContent(content, nil)
+	if len(result.Threats) < 2 {
+		t.Fatalf("expected at least 2 threats, got %d", len(result.Threats))
+	}
+
+	threats := []Threat{
+		{Type: ThreatStyleBlock, Description: "style element"},
+		{Type: ThreatLink, Description: "anchor element with href"},
+		{Type: ThreatScript, Description: "script element"},
+	}
+	SortThreatsBySeverity(threats)
+
+	if threats[0].Type != ThreatScript {
+		t.Errorf("threats[0].Type = %s, want %s", threats[0].Type, ThreatScript)
+	}
+	if threats[2].Type != ThreatStyleBlock {
+		t.Errorf("threats[2].Type = %s, want %s", threats[2].Type, ThreatStyleBlock)
+	}
+
+	SortThreatsBySeverity(result.Threats)
+	if result.Threats[0].Type != ThreatScript {
+		t.Errorf("scanned threats[0].Type = %s, want %s", result.Threats[0].Type, ThreatScript)
+	}
+	if last := result.Threats[len(result.Threats)-1]; last.Type != ThreatStyleBlock {
+		t.Errorf("scanned last threat = %s, want %s", last.Type, ThreatStyleBlock)
+	}
+}
+
+func TestThreatTypeSeverityRank(t *testing.T) {
+	if ThreatScript.SeverityRank() <= ThreatExternalRef.SeverityRank() {
+		t.Error("critical should rank above high")
+	}
+	if ThreatExternalRef.SeverityRank() <= ThreatAnimation.SeverityRank() {
+		t.Error("high should rank above medium")
+	}
+	if ThreatAnimation.SeverityRank() <= ThreatStyleBlock.SeverityRank() {
+		t.Error("medium should rank above low")
+	}
+}
+
+func TestSVGEventAnimation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"syncbase click", `<animate begin="x.click" attributeName="width" to="100"/>`, true},
+		{"set mouseover", `<set attributeName="fill" to="red" begin="mouseover"/>`, true},
+		{"end on keydown", `<animateTransform attributeName="transform" end="keydown" dur="1s"/>`, true},
+		{"access key", `<set attributeName="visibility" to="visible" begin="accessKey(a)"/>`, true},
+		{"timed begin", `<animate begin="1s" attributeName="width" to="100"/>`, false},
+		{"syncbase begin", `<animate begin="other.end" attributeName="width" to="100"/>`, false},
+	}
+
+	for _, tt := range tests {
+		content := `<svg viewBox="0 0 100 100"><rect width="10" height="10">` + tt.content + `</rect></svg>`
+		result := ScanContentWithLevel(content, nil, ScanLevelStandard)
+		got := result.ThreatCounts[ThreatEventAnimation] > 0
+		if got != tt.want {
+			t.Errorf("%s: event animation detected = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeMatchWithoutChange(t *testing.T) {
+	// A pattern whose replacement is identical to its match
+	opts := DefaultSanitizeOptions()
+	patterns := append(sanitizePatternsFor(opts),
+		sanitizePattern{regexp.MustCompile(`href="#"`), `href="#"`, "neutralized href", ThreatScript})
+
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "input.svg")
+	outputFile := filepath.Join(dir, "output.svg")
+
+	content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <a href="#"><path d="M 0 0 L 10 10"/></a>
+</svg>`
+
+	if err := os.WriteFile(inputFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := sanitizeFile(inputFile, outputFile, opts, patterns)
+	if err != nil {
+		t.Fatalf("Sanitize error: %v", err)
+	}
+
+	if result.Sanitized {
+		t.Error("expected Sanitized = false when output equals input")
+	}
+	if len(result.ThreatsRemoved) != 1 {
+		t.Errorf("expected 1 matched threat reported, got %d", len(result.ThreatsRemoved))
+	}
+}
+
+func TestParseScanLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ScanLevel
+		wantErr bool
+	}{
+		{"strict", ScanLevelStrict, false},
+		{"Standard", ScanLevelStandard, false},
+		{"paranoid", ScanLevelStrict, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseScanLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseScanLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseScanLevel(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeRemoveComments(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100"><!-- PHNjcmlwdD4= --><path d="M 0 0 L 10 10"/></svg>`
+
+	sanitized, _ := SanitizeContent(content, DefaultSanitizeOptions())
+	if !strings.Contains(sanitized, "<!--") {
+		t.Error("comments should be kept unless RemoveComments is set")
+	}
+
+	opts := DefaultSanitizeOptions()
+	opts.RemoveComments = true
+	sanitized, _ = SanitizeContent(content, opts)
+	if strings.Contains(sanitized, "<!--") {
+		t.Errorf("comments should be removed, got: %s", sanitized)
+	}
+	if !strings.Contains(sanitized, "<path") {
+		t.Error("sanitized content should still contain path element")
+	}
+}
+
+func TestSanitizeKeepIDs(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.svg")
+	output := filepath.Join(dir, "output.svg")
+	content := `<svg viewBox="0 0 100 100">
+<style id="keep">:root { --brand: #f00; }</style>
+<style>.a { fill: url(http://evil.example/x); }</style>
+<path onclick="x()" d="M 10 10 L 20 20"/>
+</svg>`
+	if err := os.WriteFile(input, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultSanitizeOptions()
+	opts.RemoveStyleBlocks = true
+	opts.KeepIDs = []string{"keep"}
+	result, err := Sanitize(input, output, opts)
+	if err != nil {
+		t.Fatalf("Sanitize error: %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	s := string(got)
+
+	if want := `<style id="keep">:root { --brand: #f00; }</style>`; !strings.Contains(s, want) {
+		t.Errorf("kept element missing %s:\n%s", want, s)
+	}
+	for _, unwanted := range []string{"evil.example", ".a {", "onclick"} {
+		if strings.Contains(s, unwanted) {
+			t.Errorf("output still contains %q:\n%s", unwanted, s)
+		}
+	}
+	for _, threat := range result.ThreatsRemoved {
+		if strings.Contains(threat.Match, `id="keep"`) {
+			t.Errorf("kept element reported as removed: %+v", threat)
+		}
+	}
+}
+
+func TestIsSecure(t *testing.T) {
+	clean := []byte(`<svg viewBox="0 0 100 100"><path d="M 0 0 L 10 10"/></svg>`)
+	if !IsSecure(clean, ScanLevelStrict) {
+		t.Error("IsSecure(clean) = false, want true")
+	}
+
+	scripted := []byte(`<svg viewBox="0 0 100 100"><script>alert(1)</script>` +
+		strings.Repeat(`<path onclick="x()" d="M 0 0"/>`, 1000) + `</svg>`)
+	if IsSecure(scripted, ScanLevelStrict) {
+		t.Error("IsSecure(scripted) = true, want false")
+	}
+
+	// Fail-fast stops at the first threat rather than collecting all 1001
+	result := ScanContentWithOptions(string(scripted), nil, ScanOptions{Level: ScanLevelStrict, FailFast: true})
+	if len(result.Threats) != 1 || result.Threats[0].Type != ThreatScript {
+		t.Errorf("FailFast threats = %d (first %v), want 1 script threat", len(result.Threats), result.Threats)
+	}
+}
+
+func TestPatternsForLevelSeverityOrder(t *testing.T) {
+	patterns := patternsForLevel(ScanLevelStrict)
+	for i := 1; i < len(patterns); i++ {
+		prev, cur := patterns[i-1].threatType, patterns[i].threatType
+		if cur.SeverityRank() > prev.SeverityRank() {
+			t.Errorf("pattern %d (%s) is checked after lower-severity %s", i, cur, prev)
+		}
+	}
+}
+
+func TestSVGEmptyFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "empty.svg")
+	if err := os.WriteFile(file, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.IsSuccess() || len(result.Errors) != 1 || result.Errors[0] != svg.ErrEmptySVG.Error() {
+		t.Errorf("Errors = %q, want only %q", result.Errors, svg.ErrEmptySVG)
+	}
+}
+
+func TestSanitizeKeepIDsDoesNotExemptCriticalThreats(t *testing.T) {
+	content := `<svg viewBox="0 0 100 100">
+<script id="brand-vars">alert(1)</script>
+<path id="brand-vars" onclick="steal()" d="M 0 0 L 10 10"/>
+<a id="brand-vars" href="https://evil.example/"><path d="M 10 10 L 20 20"/></a>
+</svg>`
+
+	opts := DefaultSanitizeOptions()
+	opts.KeepIDs = []string{"brand-vars"}
+	got, threats := SanitizeContent(content, opts)
+
+	for _, unwanted := range []string{"<script", "alert(1)", "onclick", "evil.example"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("output still contains %q:\n%s", unwanted, got)
+		}
+	}
+	if len(threats) < 3 {
+		t.Errorf("ThreatsRemoved = %d, want at least 3: %+v", len(threats), threats)
+	}
+}
@@ -0,0 +1,53 @@
+package security
+
+import (
+	"bytes"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// ScanZip scans every SVG entry of a zip archive in memory using strict
+// level. Each Result's FilePath is the entry's path within the archive.
+// Entries that cannot be read, including those over svg.MaxZipEntrySize,
+// are reported as failed Results.
+func ScanZip(archivePath string) ([]*Result, error) {
+	return ScanZipWithLevel(archivePath, ScanLevelStrict)
+}
+
+// ScanZipWithLevel scans every SVG entry of a zip archive with the
+// specified level.
+func ScanZipWithLevel(archivePath string, level ScanLevel) ([]*Result, error) {
+	entries, err := svg.ReadZipSVGs(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*Result
+	for _, entry := range entries {
+		result, err := scanZipEntry(entry, level)
+		if err != nil {
+			results = append(results, &Result{
+				FilePath:     entry.Name,
+				IsSecure:     false,
+				ThreatCounts: make(map[ThreatType]int),
+				Errors:       []string{err.Error()},
+			})
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// scanZipEntry scans one entry read by svg.ReadZipSVGs.
+func scanZipEntry(entry svg.ZipEntry, level ScanLevel) (*Result, error) {
+	if entry.Err != nil {
+		return nil, entry.Err
+	}
+	result, err := ScanReader(bytes.NewReader(entry.Content), level)
+	if err != nil {
+		return nil, err
+	}
+	result.FilePath = entry.Name
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package svg
+
+// SplitSubpaths splits path data at each move command into standalone
+// subpath strings, e.g. to measure or remove one glyph of a combined path.
+// A relative move (m) that starts a later subpath is rewritten as an
+// absolute M so each subpath draws in the same place on its own. Commands
+// before the first move command are dropped, as renderers ignore them.
+func SplitSubpaths(d string) []string {
+	var subpaths []string
+	var current []PathCommand
+	flush := func() {
+		if len(current) > 0 {
+			subpaths = append(subpaths, PathCommandsToString(current))
+		}
+		current = nil
+	}
+
+	var x, y, startX, startY float64
+	for _, cmd := range ParsePath(d) {
+		if cmd.Command == 'M' || cmd.Command == 'm' {
+			flush()
+			if cmd.Command == 'm' && len(cmd.Params) >= 2 {
+				// The first pair is relative to the current point; later
+				// pairs are relative line-tos and stay as they are.
+				params := append([]float64{x + cmd.Params[0], y + cmd.Params[1]}, cmd.Params[2:]...)
+				if len(params) > 2 {
+					current = append(current, PathCommand{Command: 'M', Params: params[:2]},
+						PathCommand{Command: 'l', Params: params[2:]})
+				} else {
+					current = append(current, PathCommand{Command: 'M', Params: params})
+				}
+			} else {
+				current = append(current, cmd)
+			}
+		} else if current != nil {
+			current = append(current, cmd)
+		}
+		x, y, startX, startY = advancePathPoint(cmd, x, y, startX, startY)
+	}
+	flush()
+	return subpaths
+}
+
+// advancePathPoint returns the current point and subpath start after cmd.
+func advancePathPoint(cmd PathCommand, x, y, startX, startY float64) (float64, float64, float64, float64) {
+	p := cmd.Params
+	relative := cmd.Command >= 'a'
+	step := 0
+	switch cmd.Command {
+	case 'M', 'm', 'L', 'l', 'T', 't':
+		step = 2
+	case 'H', 'h', 'V', 'v':
+		step = 1
+	case 'S', 's', 'Q', 'q':
+		step = 4
+	case 'C', 'c':
+		step = 6
+	case 'A', 'a':
+		step = 7
+	case 'Z', 'z':
+		return startX, startY, startX, startY
+	}
+
+	for i := 0; step > 0 && i+step <= len(p); i += step {
+		switch cmd.Command {
+		case 'H', 'h':
+			if relative {
+				x += p[i]
+			} else {
+				x = p[i]
+			}
+		case 'V', 'v':
+			if relative {
+				y += p[i]
+			} else {
+				y = p[i]
+			}
+		default:
+			// The end point is the last pair of each parameter group
+			ex, ey := p[i+step-2], p[i+step-1]
+			if relative {
+				x, y = x+ex, y+ey
+			} else {
+				x, y = ex, ey
+			}
+		}
+		if i == 0 && (cmd.Command == 'M' || cmd.Command == 'm') {
+			startX, startY = x, y
+		}
+	}
+	return x, y, startX, startY
+}
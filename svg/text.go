@@ -0,0 +1,102 @@
+package svg
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/JoshVarga/svgparser"
+)
+
+// Approximate glyph metrics as fractions of the font size. Exact text
+// bounds need font metrics, so text bounds are estimates.
+const (
+	defaultFontSize = 16.0
+	glyphWidth      = 0.6
+	glyphAscent     = 0.8
+	glyphDescent    = 0.2
+)
+
+// textCursor tracks the current text position and font size while walking
+// a <text> element and its <tspan> children.
+type textCursor struct {
+	x, y     float64
+	fontSize float64
+}
+
+// TextBounds estimates the bounding box of a <text> element, including
+// nested <tspan> elements. Absolute positions (x, y) and relative shifts
+// (dx, dy) are applied as each span is entered, and each run of characters
+// advances the position by an average glyph width. An element with a
+// textLength attribute uses it as its width instead, with the height still
+// derived from the font size.
+func TextBounds(elem *svgparser.Element) *BoundingBox {
+	box := NewBoundingBox()
+	cur := &textCursor{fontSize: defaultFontSize}
+	walkText(elem, cur, box)
+	return box
+}
+
+// walkText applies the positioning of elem to cur and expands box with
+// its text content and that of its <tspan> children.
+func walkText(elem *svgparser.Element, cur *textCursor, box *BoundingBox) {
+	if v, ok := firstLength(elem.Attributes["x"]); ok {
+		cur.x = v
+	}
+	if v, ok := firstLength(elem.Attributes["y"]); ok {
+		cur.y = v
+	}
+	if v, ok := firstLength(elem.Attributes["dx"]); ok {
+		cur.x += v
+	}
+	if v, ok := firstLength(elem.Attributes["dy"]); ok {
+		cur.y += v
+	}
+
+	// Font size is inherited by children but restored afterwards
+	parentFontSize := cur.fontSize
+	if v, ok := firstLength(elem.Attributes["font-size"]); ok && v > 0 {
+		cur.fontSize = v
+	}
+	defer func() { cur.fontSize = parentFontSize }()
+
+	// textLength states the rendered advance of the element's text,
+	// including its children, so it replaces the estimated width
+	if length, ok := firstLength(elem.Attributes["textLength"]); ok && length > 0 {
+		startX := cur.x
+		estimated := NewBoundingBox()
+		walkTextContent(elem, cur, estimated)
+		if estimated.IsValid() {
+			box.Expand(startX, estimated.MinY)
+			box.Expand(startX+length, estimated.MaxY)
+		}
+		cur.x = startX + length
+		return
+	}
+	walkTextContent(elem, cur, box)
+}
+
+// walkTextContent expands box with the text content of elem and its
+// <tspan> children, starting at the current position.
+func walkTextContent(elem *svgparser.Element, cur *textCursor, box *BoundingBox) {
+	if text := strings.TrimSpace(elem.Content); text != "" {
+		width := float64(utf8.RuneCountInString(text)) * cur.fontSize * glyphWidth
+		box.Expand(cur.x, cur.y-cur.fontSize*glyphAscent)
+		box.Expand(cur.x+width, cur.y+cur.fontSize*glyphDescent)
+		cur.x += width
+	}
+
+	for _, child := range elem.Children {
+		if child.Name == "tspan" {
+			walkText(child, cur, box)
+		}
+	}
+}
+
+// firstLength parses the first value of a length list such as "10 20 30".
+func firstLength(s string) (float64, bool) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return 0, false
+	}
+	return ParseFloat(fields[0], 0), true
+}
@@ -0,0 +1,46 @@
+package svg
+
+import (
+	"testing"
+)
+
+func TestTextBoundsTspans(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 200 100"><text x="10" y="50" font-size="10" fill="#000000">AB<tspan dx="5" fill="#ff0000">CD</tspan><tspan x="100" font-size="20" fill="#00ff00">E</tspan></text></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	box := ContentBounds(root)
+	// "AB" spans 10-22, "CD" is shifted by dx to 27-39, and "E" restarts
+	// at x=100 with a 20px font, spanning 100-112 and y 34-54.
+	if box.MinX != 10 || box.MaxX != 112 || box.MinY != 34 || box.MaxY != 54 {
+		t.Errorf("bounds = %+v, want 10,34 to 112,54", box)
+	}
+}
+
+func TestTextBoundsTextLength(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 200 100"><text x="10" y="50" textLength="80" font-size="20">Brand</text></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	box := ContentBounds(root)
+	// The estimate for "Brand" would be 5 * 20 * 0.6 = 60 wide; textLength
+	// states 80. Height still comes from the font size: 34 to 54.
+	if box.MinX != 10 || box.MaxX != 90 || box.MinY != 34 || box.MaxY != 54 {
+		t.Errorf("bounds = %+v, want 10,34 to 90,54", box)
+	}
+}
+
+func TestTextBoundsTspanTextLength(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 200 100"><text x="0" y="50" font-size="10">AB<tspan textLength="30">CD</tspan></text></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	box := ContentBounds(root)
+	// "AB" spans 0-12 and the tspan is stretched from 12 to 42.
+	if box.MinX != 0 || box.MaxX != 42 {
+		t.Errorf("bounds = %+v, want x from 0 to 42", box)
+	}
+}
@@ -0,0 +1,22 @@
+package svg
+
+import "strings"
+
+// RootTitle returns the text of the <title> element that is a direct
+// child of the root <svg> element, as used by assistive technology for the
+// icon's accessible name. It returns false if there is no such title, the
+// title is blank, or the content cannot be parsed.
+func RootTitle(content []byte) (string, bool) {
+	root, err := Parse(content)
+	if err != nil {
+		return "", false
+	}
+	for _, child := range root.Children {
+		if child.Name == "title" {
+			if title := strings.TrimSpace(child.Content); title != "" {
+				return title, true
+			}
+		}
+	}
+	return "", false
+}
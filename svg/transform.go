@@ -0,0 +1,155 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matrix is a 2D affine transform in SVG's matrix(a b c d e f) form,
+// mapping (x, y) to (a*x + c*y + e, b*x + d*y + f).
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// IdentityMatrix returns the transform that leaves points unchanged.
+func IdentityMatrix() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Multiply returns the transform that applies n and then m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.E, m.B*x + m.D*y + m.F
+}
+
+var transformFuncRe = regexp.MustCompile(`\s*([A-Za-z]+)\s*\(([^)]*)\)\s*,?`)
+
+// ParseTransform parses a transform attribute such as
+// "translate(10,10) rotate(45)" into a single matrix. Chained transforms
+// apply right to left, as in SVG. Supported functions are matrix,
+// translate, scale, rotate (with optional center), skewX, and skewY.
+func ParseTransform(s string) (Matrix, error) {
+	m := IdentityMatrix()
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		loc := transformFuncRe.FindStringSubmatchIndex(rest)
+		if loc == nil || loc[0] != 0 {
+			return IdentityMatrix(), fmt.Errorf("invalid transform: %q", s)
+		}
+		name := rest[loc[2]:loc[3]]
+		args, err := parseTransformArgs(rest[loc[4]:loc[5]])
+		if err != nil {
+			return IdentityMatrix(), fmt.Errorf("invalid transform %q: %w", s, err)
+		}
+		t, err := transformMatrix(name, args)
+		if err != nil {
+			return IdentityMatrix(), fmt.Errorf("invalid transform %q: %w", s, err)
+		}
+		m = m.Multiply(t)
+		rest = strings.TrimSpace(rest[loc[1]:])
+	}
+	return m, nil
+}
+
+// parseTransformArgs parses a comma or whitespace separated number list.
+func parseTransformArgs(s string) ([]float64, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	args := make([]float64, 0, len(fields))
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", f)
+		}
+		args = append(args, v)
+	}
+	return args, nil
+}
+
+// transformMatrix returns the matrix for a single transform function.
+func transformMatrix(name string, args []float64) (Matrix, error) {
+	argCount := func(counts ...int) error {
+		for _, c := range counts {
+			if len(args) == c {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s takes %v arguments, got %d", name, counts, len(args))
+	}
+	radians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	switch name {
+	case "matrix":
+		if err := argCount(6); err != nil {
+			return Matrix{}, err
+		}
+		return Matrix{args[0], args[1], args[2], args[3], args[4], args[5]}, nil
+	case "translate":
+		if err := argCount(1, 2); err != nil {
+			return Matrix{}, err
+		}
+		ty := 0.0
+		if len(args) == 2 {
+			ty = args[1]
+		}
+		return Matrix{A: 1, D: 1, E: args[0], F: ty}, nil
+	case "scale":
+		if err := argCount(1, 2); err != nil {
+			return Matrix{}, err
+		}
+		sy := args[0]
+		if len(args) == 2 {
+			sy = args[1]
+		}
+		return Matrix{A: args[0], D: sy}, nil
+	case "rotate":
+		if err := argCount(1, 3); err != nil {
+			return Matrix{}, err
+		}
+		a := radians(args[0])
+		cos, sin := math.Cos(a), math.Sin(a)
+		r := Matrix{A: cos, B: sin, C: -sin, D: cos}
+		if len(args) == 3 {
+			cx, cy := args[1], args[2]
+			return Matrix{A: 1, D: 1, E: cx, F: cy}.Multiply(r).Multiply(Matrix{A: 1, D: 1, E: -cx, F: -cy}), nil
+		}
+		return r, nil
+	case "skewX":
+		if err := argCount(1); err != nil {
+			return Matrix{}, err
+		}
+		return Matrix{A: 1, C: math.Tan(radians(args[0])), D: 1}, nil
+	case "skewY":
+		if err := argCount(1); err != nil {
+			return Matrix{}, err
+		}
+		return Matrix{A: 1, B: math.Tan(radians(args[0])), D: 1}, nil
+	}
+	return Matrix{}, fmt.Errorf("unknown transform function %q", name)
+}
+
+// elementTransform returns the matrix of an element's transform attribute.
+// A missing or invalid transform is the identity, matching how renderers
+// ignore transforms they cannot parse.
+func elementTransform(attrs map[string]string) Matrix {
+	m, err := ParseTransform(attrs["transform"])
+	if err != nil {
+		return IdentityMatrix()
+	}
+	return m
+}
@@ -0,0 +1,82 @@
+package svg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		transform string
+		x, y      float64 // image of the point (10, 0)
+	}{
+		{"", 10, 0},
+		{"translate(5)", 15, 0},
+		{"translate(5, -5)", 15, -5},
+		{"scale(2 3)", 20, 0},
+		{"rotate(90)", 0, 10},
+		{"rotate(90 10 10)", 20, 10},
+		{"matrix(1 0 0 1 7 8)", 17, 8},
+		// Chained transforms apply right to left: rotate first, then translate
+		{"translate(10,10) rotate(90)", 10, 20},
+		{"translate(10,10),scale(2)", 30, 10},
+	}
+	for _, tt := range tests {
+		m, err := ParseTransform(tt.transform)
+		if err != nil {
+			t.Errorf("ParseTransform(%q) error: %v", tt.transform, err)
+			continue
+		}
+		x, y := m.Apply(10, 0)
+		if math.Abs(x-tt.x) > 1e-9 || math.Abs(y-tt.y) > 1e-9 {
+			t.Errorf("ParseTransform(%q) maps (10, 0) to (%g, %g), want (%g, %g)", tt.transform, x, y, tt.x, tt.y)
+		}
+	}
+
+	for _, bad := range []string{"translate(", "spin(45)", "scale(1 2 3)", "translate(a)"} {
+		if _, err := ParseTransform(bad); err == nil {
+			t.Errorf("ParseTransform(%q) expected error", bad)
+		}
+	}
+}
+
+func TestElementBoundsTranslatedRect(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 100 100"><rect width="20" height="10" transform="translate(30 40)"/></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	box := GetElementBounds(root.Children[0])
+	if box.MinX != 30 || box.MinY != 40 || box.MaxX != 50 || box.MaxY != 50 {
+		t.Errorf("bounds = (%g, %g)-(%g, %g), want (30, 40)-(50, 50)", box.MinX, box.MinY, box.MaxX, box.MaxY)
+	}
+}
+
+func TestContentBoundsNestedTransforms(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 100 100">
+  <g transform="translate(10,10)">
+    <g transform="scale(2)">
+      <rect width="10" height="10"/>
+      <circle cx="20" cy="5" r="5" transform="translate(5)"/>
+    </g>
+  </g>
+</svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rect: (10,10)-(30,30); circle spans x 20..30 before scaling: (50,10)-(70,30)
+	box := ContentBounds(root)
+	if box.MinX != 10 || box.MinY != 10 || box.MaxX != 70 || box.MaxY != 30 {
+		t.Errorf("bounds = (%g, %g)-(%g, %g), want (10, 10)-(70, 30)", box.MinX, box.MinY, box.MaxX, box.MaxY)
+	}
+}
+
+func TestElementBoundsRotatedPath(t *testing.T) {
+	root, err := Parse([]byte(`<svg viewBox="0 0 100 100"><path d="M 0 0 L 10 0" transform="translate(50 50) rotate(90)"/></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	box := GetElementBounds(root.Children[0])
+	if math.Abs(box.MinX-50) > 1e-9 || math.Abs(box.MaxX-50) > 1e-9 || box.MinY != 50 || math.Abs(box.MaxY-60) > 1e-9 {
+		t.Errorf("bounds = (%g, %g)-(%g, %g), want (50, 50)-(50, 60)", box.MinX, box.MinY, box.MaxX, box.MaxY)
+	}
+}
@@ -0,0 +1,56 @@
+package svg
+
+import (
+	"fmt"
+
+	"github.com/JoshVarga/svgparser"
+)
+
+// ContentBounds returns the bounding box of the rendered content of an SVG
+// root element, skipping defs, mask, and clipPath subtrees. The box is
+// invalid if no geometry was found. Percentage lengths resolve against the
+// root viewBox, or the width and height if there is no viewBox. Transforms
+// on descendants are applied, so the box is in viewBox coordinates. A
+// transform on the root element itself is not applied: it moves the
+// rendered viewport as a whole and does not change where content sits
+// within the viewBox.
+func ContentBounds(root *svgparser.Element) *BoundingBox {
+	vb, err := ParseViewBox(root.Attributes["viewBox"])
+	if err != nil {
+		vb = ViewBox{
+			Width:  ParseFloat(root.Attributes["width"], 0),
+			Height: ParseFloat(root.Attributes["height"], 0),
+		}
+	}
+
+	box := NewBoundingBox()
+	for _, child := range root.Children {
+		if isNonRenderedContainer(child.Name) {
+			continue
+		}
+		box.Merge(GetElementBoundsInViewBox(child, vb))
+	}
+	return box
+}
+
+// UnionContentBounds returns the envelope of the content bounds of several
+// SVG files, e.g. to choose one consistent viewBox for an icon family.
+// It returns an error if a file cannot be parsed or none has content.
+func UnionContentBounds(paths []string) (*BoundingBox, error) {
+	union := NewBoundingBox()
+	for _, path := range paths {
+		content, err := ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		root, err := Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		union.Merge(ContentBounds(root))
+	}
+	if !union.IsValid() {
+		return nil, fmt.Errorf("no parseable content found")
+	}
+	return union, nil
+}
@@ -0,0 +1,33 @@
+package svg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnionContentBounds(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.svg")
+	b := filepath.Join(dir, "b.svg")
+
+	if err := os.WriteFile(a, []byte(`<svg viewBox="0 0 100 100"><rect x="10" y="20" width="30" height="30"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`<svg viewBox="0 0 100 100"><defs><rect x="0" y="0" width="100" height="100"/></defs><circle cx="70" cy="70" r="10"/></svg>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	box, err := UnionContentBounds([]string{a, b})
+	if err != nil {
+		t.Fatalf("UnionContentBounds error: %v", err)
+	}
+	want := BoundingBox{MinX: 10, MinY: 20, MaxX: 80, MaxY: 80}
+	if *box != want {
+		t.Errorf("UnionContentBounds = %+v, want %+v", *box, want)
+	}
+
+	if _, err := UnionContentBounds([]string{filepath.Join(dir, "missing.svg")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
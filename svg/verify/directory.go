@@ -0,0 +1,72 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/grokify/brandkit/svg"
+)
+
+// DirectoryOptions configures DirectoryWithOptions.
+type DirectoryOptions struct {
+	Options        // Checks applied to each file
+	Recursive bool // Verify the whole directory tree
+	Workers   int  // Number of concurrent workers (0 = GOMAXPROCS)
+}
+
+// DirectoryWithOptions verifies the SVG files in a directory concurrently.
+// Results are sorted by FilePath. Per-file errors, including unreadable
+// subdirectories, are recorded in their Result.
+func DirectoryWithOptions(dirPath string, opts DirectoryOptions) ([]*Result, error) {
+	return directoryContext(context.Background(), dirPath, opts)
+}
+
+// directoryContext implements DirectoryWithOptions, stopping early with
+// ctx.Err() once ctx is cancelled.
+func directoryContext(ctx context.Context, dirPath string, opts DirectoryOptions) ([]*Result, error) {
+	var files []string
+	var results []*Result
+	if opts.Recursive {
+		var pathErrs []*svg.PathError
+		var err error
+		files, pathErrs, err = svg.WalkSVGFilesContext(ctx, dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+		for _, pe := range pathErrs {
+			results = append(results, &Result{
+				FilePath: pe.Path,
+				IsValid:  false,
+				Errors:   []string{pe.Err.Error()},
+			})
+		}
+	} else {
+		var err error
+		files, err = svg.ListSVGFiles(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+	}
+
+	fileResults, err := svg.MapFiles(ctx, files, opts.Workers, func(filePath string) *Result {
+		result, err := SVGWithOptions(filePath, opts.Options)
+		if err != nil {
+			return &Result{
+				FilePath: filePath,
+				IsValid:  false,
+				Errors:   []string{err.Error()},
+			}
+		}
+		return result
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results = append(results, fileResults...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].FilePath < results[j].FilePath
+	})
+	return results, nil
+}
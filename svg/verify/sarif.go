@@ -0,0 +1,154 @@
+package verify
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// SARIF constants for the 2.1.0 format read by GitHub code scanning.
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	// invalidSVGRuleID is the rule for errors no other rule covers, such
+	// as invalid XML or a missing <svg> element.
+	invalidSVGRuleID = "invalid-svg"
+)
+
+// sarifRules are the rules for errors other than embedded data in the
+// verified file itself, which get a rule per pattern.
+var sarifRules = []sarifRule{
+	{ID: invalidSVGRuleID, ShortDescription: sarifMessage{Text: "invalid SVG"}},
+	{ID: "foreign-object", ShortDescription: sarifMessage{Text: "foreignObject element"}},
+	{ID: "missing-namespace", ShortDescription: sarifMessage{Text: "root <svg> element missing the SVG namespace"}},
+	{ID: "missing-title", ShortDescription: sarifMessage{Text: "missing root-level <title> element"}},
+	{ID: "security-threat", ShortDescription: sarifMessage{Text: "critical or high severity security threat"}},
+	{ID: "referenced-file-embedded-data", ShortDescription: sarifMessage{Text: "referenced SVG file contains embedded data"}},
+}
+
+// sarifClassRuleID returns the rule in sarifRules for an error message.
+func sarifClassRuleID(e string) string {
+	switch {
+	case e == errForeignObject:
+		return "foreign-object"
+	case e == errMissingNamespace:
+		return "missing-namespace"
+	case e == errMissingTitle:
+		return "missing-title"
+	case strings.HasPrefix(e, securityThreatPrefix):
+		return "security-threat"
+	case strings.HasPrefix(e, referencedFilePrefix):
+		return "referenced-file-embedded-data"
+	default:
+		return invalidSVGRuleID
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIF encodes verification results as a SARIF 2.1.0 log for code
+// scanning tools. Each embedded-data error becomes a result under a rule
+// named after the pattern that matched, e.g. "base64-embedded-image", with
+// the file path as its artifact location. Other error classes have their
+// own rules, such as "missing-title" and "security-threat", with
+// "invalid-svg" for malformed content.
+func SARIF(results []*Result) ([]byte, error) {
+	rules := append([]sarifRule{}, sarifRules...)
+	ruleForError := map[string]string{}
+	for _, p := range embeddedPatterns {
+		id := sarifRuleID(p.desc)
+		rules = append(rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: p.desc}})
+		ruleForError["contains "+p.desc] = id
+	}
+
+	sarifResults := []sarifResult{}
+	for _, r := range results {
+		for _, e := range r.Errors {
+			ruleID, ok := ruleForError[e]
+			if !ok {
+				ruleID = sarifClassRuleID(e)
+			}
+			sarifResults = append(sarifResults, sarifResult{
+				RuleID:  ruleID,
+				Level:   "error",
+				Message: sarifMessage{Text: e},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(r.FilePath)},
+					},
+				}},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "brandkit",
+				InformationURI: "https://github.com/grokify/brandkit",
+				Rules:          rules,
+			}},
+			Results: sarifResults,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// sarifRuleID converts a pattern description such as "xlink:href with
+// data URI" into a rule ID such as "xlink-href-with-data-uri".
+func sarifRuleID(desc string) string {
+	fields := strings.FieldsFunc(strings.ToLower(desc), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	return strings.Join(fields, "-")
+}
@@ -0,0 +1,90 @@
+package verify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSARIFNoFindings(t *testing.T) {
+	out, err := SARIF([]*Result{{FilePath: "ok.svg", IsValid: true, IsPureVector: true}})
+	if err != nil {
+		t.Fatalf("SARIF error: %v", err)
+	}
+	var doc struct {
+		Schema string `json:"$schema"`
+		Runs   []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID string } `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, out)
+	}
+	if doc.Schema == "" || len(doc.Runs) != 1 {
+		t.Fatalf("unexpected SARIF document:\n%s", out)
+	}
+	if doc.Runs[0].Results == nil || len(doc.Runs[0].Results) != 0 {
+		t.Errorf("results = %v, want an empty array", doc.Runs[0].Results)
+	}
+	if got := len(doc.Runs[0].Tool.Driver.Rules); got != len(embeddedPatterns)+len(sarifRules) {
+		t.Errorf("got %d rules, want one per embedded pattern plus the error class rules", got)
+	}
+}
+
+func TestSARIFErrorClassRules(t *testing.T) {
+	result := &Result{FilePath: "icon.svg", Errors: []string{
+		"contains base64 embedded image",
+		errForeignObject,
+		errMissingTitle,
+		errMissingNamespace,
+		securityThreatPrefix + "[script]: script element",
+		referencedFilePrefix + "shared.svg contains base64 embedded image",
+		"invalid XML: unexpected EOF",
+	}}
+	out, err := SARIF([]*Result{result})
+	if err != nil {
+		t.Fatalf("SARIF error: %v", err)
+	}
+	var doc struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID string } `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, out)
+	}
+
+	declared := map[string]bool{}
+	for _, r := range doc.Runs[0].Tool.Driver.Rules {
+		declared[r.ID] = true
+	}
+	want := []string{
+		sarifRuleID("base64 embedded image"), "foreign-object", "missing-title", "missing-namespace",
+		"security-threat", "referenced-file-embedded-data", invalidSVGRuleID,
+	}
+	for i, r := range doc.Runs[0].Results {
+		if r.RuleID != want[i] {
+			t.Errorf("result %d rule = %q, want %q", i, r.RuleID, want[i])
+		}
+		if !declared[r.RuleID] {
+			t.Errorf("rule %q is not declared", r.RuleID)
+		}
+	}
+}
+
+func TestSARIFRuleID(t *testing.T) {
+	if got := sarifRuleID("xlink:href with data URI"); got != "xlink-href-with-data-uri" {
+		t.Errorf("sarifRuleID = %q", got)
+	}
+}
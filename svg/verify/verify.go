@@ -2,13 +2,17 @@
 package verify
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/grokify/brandkit/svg"
+	"github.com/grokify/brandkit/svg/security"
 )
 
 // Result contains the result of validating an SVG file.
@@ -21,6 +25,18 @@ type Result struct {
 	Errors          []string
 }
 
+// Options configures optional verification checks.
+type Options struct {
+	ForbidForeignObject bool // Fail on <foreignObject> elements, which break pure-vector rendering
+	IncludeSecurity     bool // Also fail on critical and high severity security threats
+	FollowLocalRefs     bool // Also check same-directory SVG files referenced via href
+	RequireNamespace    bool // Fail if the root <svg> does not declare xmlns="http://www.w3.org/2000/svg"
+	RequireTitle        bool // Fail if the root <svg> has no non-empty <title> child, for accessibility
+}
+
+// maxLocalRefDepth limits how many levels of local references are followed.
+const maxLocalRefDepth = 3
+
 // embeddedPattern defines a pattern to detect embedded binary data.
 type embeddedPattern struct {
 	pattern *regexp.Regexp
@@ -28,10 +44,10 @@ type embeddedPattern struct {
 }
 
 var embeddedPatterns = []embeddedPattern{
-	{regexp.MustCompile(`data:image/(png|jpeg|jpg|gif|webp|bmp)`), "base64 embedded image"},
+	{regexp.MustCompile(`data:image/(png|jpeg|jpg|gif|webp|bmp|avif|heic|heif|tiff|tif|x-icon|vnd\.microsoft\.icon|ico)`), "base64 embedded image"},
 	{regexp.MustCompile(`xlink:href\s*=\s*["']data:`), "xlink:href with data URI"},
 	{regexp.MustCompile(`href\s*=\s*["']data:image`), "href with embedded image data"},
-	{regexp.MustCompile(`<image[^>]+xlink:href\s*=\s*["'][^"']*\.(png|jpg|jpeg|gif|webp|bmp)`), "image element referencing binary file"},
+	{regexp.MustCompile(`<image[^>]+xlink:href\s*=\s*["'][^"']*\.(png|jpg|jpeg|gif|webp|bmp|avif|heic|heif|tiff|tif|ico)`), "image element referencing binary file"},
 }
 
 var vectorPatterns = map[string]*regexp.Regexp{
@@ -45,8 +61,20 @@ var vectorPatterns = map[string]*regexp.Regexp{
 	"text":     regexp.MustCompile(`<text\b`),
 }
 
+var foreignObjectPattern = regexp.MustCompile(`(?i)<foreignObject\b`)
+
+var svgNamespaceAttr = regexp.MustCompile(`\sxmlns\s*=\s*["']http://www\.w3\.org/2000/svg["']`)
+
+// localRefPattern matches href and xlink:href values referencing a file.
+var localRefPattern = regexp.MustCompile(`(?i)\s(?:xlink:)?href\s*=\s*["']([^"'#][^"']*)["']`)
+
 // SVG checks if an SVG file is a pure vector image without embedded binary data.
 func SVG(filePath string) (*Result, error) {
+	return SVGWithOptions(filePath, Options{})
+}
+
+// SVGWithOptions checks an SVG file with the given verification options.
+func SVGWithOptions(filePath string, opts Options) (*Result, error) {
 	result := &Result{
 		FilePath:       filePath,
 		IsValid:        true,
@@ -60,6 +88,56 @@ func SVG(filePath string) (*Result, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	verifyContent(result, content, opts)
+
+	if opts.FollowLocalRefs {
+		visited := map[string]bool{filepath.Clean(filePath): true}
+		checkLocalRefs(result, filepath.Dir(filePath), string(content), []string{filepath.Base(filePath)}, visited)
+	}
+
+	return result, nil
+}
+
+// Reader checks SVG content read from r, e.g. an HTTP request body.
+// Options.FollowLocalRefs is ignored since there is no file location.
+func Reader(r io.Reader, opts Options) (*Result, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	return Content(content, opts), nil
+}
+
+// Content checks SVG content in memory, e.g. an embedded icon.
+// Options.FollowLocalRefs is ignored since there is no file location.
+func Content(content []byte, opts Options) *Result {
+	result := &Result{
+		IsValid:        true,
+		IsPureVector:   true,
+		VectorElements: []string{},
+		Errors:         []string{},
+	}
+	verifyContent(result, content, opts)
+	return result
+}
+
+// Error messages recorded by verifyContent and checkLocalRefs, which SARIF
+// maps to rules.
+const (
+	errForeignObject     = "contains foreignObject element"
+	errMissingNamespace  = `root <svg> element missing xmlns="http://www.w3.org/2000/svg"`
+	errMissingTitle      = "missing root-level <title> element"
+	securityThreatPrefix = "security threat "
+	referencedFilePrefix = "referenced file "
+)
+
+// verifyContent runs the content checks, recording findings in result.
+func verifyContent(result *Result, content []byte, opts Options) {
+	if err := svg.CheckEmpty(content); err != nil {
+		result.IsValid = false
+		result.Errors = append(result.Errors, err.Error())
+		return
+	}
 	contentStr := string(content)
 
 	// Check for valid XML/SVG structure
@@ -77,6 +155,36 @@ func SVG(filePath string) (*Result, error) {
 		}
 	}
 
+	if opts.ForbidForeignObject && foreignObjectPattern.MatchString(contentStr) {
+		result.IsPureVector = false
+		result.Errors = append(result.Errors, errForeignObject)
+	}
+
+	if opts.RequireNamespace {
+		if root := svg.RootStartTag(contentStr); root != "" && !svgNamespaceAttr.MatchString(root) {
+			result.IsValid = false
+			result.Errors = append(result.Errors, errMissingNamespace)
+		}
+	}
+
+	if opts.RequireTitle {
+		if _, ok := svg.RootTitle(content); !ok {
+			result.IsValid = false
+			result.Errors = append(result.Errors, errMissingTitle)
+		}
+	}
+
+	if opts.IncludeSecurity {
+		scan := security.ScanContentWithLevel(contentStr, nil, security.ScanLevelStrict)
+		for _, t := range scan.Threats {
+			if sev := t.Type.Severity(); sev != "critical" && sev != "high" {
+				continue
+			}
+			result.IsValid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("%s[%s]: %s", securityThreatPrefix, t.Type, t.Description))
+		}
+	}
+
 	// Count vector elements
 	for name, pattern := range vectorPatterns {
 		matches := pattern.FindAllString(contentStr, -1)
@@ -91,32 +199,56 @@ func SVG(filePath string) (*Result, error) {
 		result.IsValid = false
 		result.Errors = append(result.Errors, fmt.Sprintf("invalid XML: %v", err))
 	}
-
-	return result, nil
 }
 
-// Directory validates all SVG files in a directory.
-func Directory(dirPath string) ([]*Result, error) {
-	files, err := svg.ListSVGFiles(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+// checkLocalRefs checks SVG files in dir referenced by content for embedded
+// binary data, recording failures with the reference chain. Already visited
+// files are skipped to break cycles, and at most maxLocalRefDepth levels of
+// references are followed.
+func checkLocalRefs(result *Result, dir, content string, chain []string, visited map[string]bool) {
+	if len(chain) > maxLocalRefDepth {
+		return
 	}
+	for _, m := range localRefPattern.FindAllStringSubmatch(content, -1) {
+		ref, _, _ := strings.Cut(m[1], "#")
+		if !isLocalSVGRef(ref) {
+			continue
+		}
+		refPath := filepath.Join(dir, ref)
+		if visited[refPath] {
+			continue
+		}
+		visited[refPath] = true
 
-	var results []*Result
-	for _, filePath := range files {
-		result, err := SVG(filePath)
+		refContent, err := os.ReadFile(refPath)
 		if err != nil {
-			results = append(results, &Result{
-				FilePath: filePath,
-				IsValid:  false,
-				Errors:   []string{err.Error()},
-			})
-			continue
+			continue // missing references are a rendering problem, not embedded data
 		}
-		results = append(results, result)
+		refChain := append(append([]string{}, chain...), ref)
+		for _, p := range embeddedPatterns {
+			if p.pattern.Match(refContent) {
+				result.IsPureVector = false
+				result.HasEmbeddedData = true
+				result.Errors = append(result.Errors, fmt.Sprintf("%s%s contains %s", referencedFilePrefix, strings.Join(refChain, " -> "), p.desc))
+			}
+		}
+		checkLocalRefs(result, dir, string(refContent), refChain, visited)
+	}
+}
+
+// isLocalSVGRef returns true for a reference to an SVG file in the same
+// directory, e.g. "shared.svg".
+func isLocalSVGRef(ref string) bool {
+	if ref == "" || strings.Contains(ref, ":") || strings.ContainsAny(ref, `/\`) {
+		return false
 	}
+	return svg.IsSVGFile(ref)
+}
 
-	return results, nil
+// Directory validates all SVG files in a directory concurrently using
+// GOMAXPROCS workers. Results are sorted by FilePath.
+func Directory(dirPath string) ([]*Result, error) {
+	return DirectoryWithOptions(dirPath, DirectoryOptions{})
 }
 
 // IsSuccess returns true if the result indicates a valid pure vector SVG.
@@ -124,26 +256,17 @@ func (r *Result) IsSuccess() bool {
 	return r.IsValid && r.IsPureVector
 }
 
-// DirectoryRecursive validates all SVG files in a directory tree.
+// DirectoryRecursive validates all SVG files in a directory tree
+// concurrently using GOMAXPROCS workers. Results are sorted by FilePath.
+// Unreadable subdirectories are reported as failed Results and do not
+// stop the walk.
 func DirectoryRecursive(dirPath string) ([]*Result, error) {
-	files, err := svg.ListSVGFilesRecursive(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
-	}
-
-	var results []*Result
-	for _, filePath := range files {
-		result, err := SVG(filePath)
-		if err != nil {
-			results = append(results, &Result{
-				FilePath: filePath,
-				IsValid:  false,
-				Errors:   []string{err.Error()},
-			})
-			continue
-		}
-		results = append(results, result)
-	}
+	return DirectoryRecursiveContext(context.Background(), dirPath)
+}
 
-	return results, nil
+// DirectoryRecursiveContext is like DirectoryRecursive but stops and
+// returns ctx.Err() once ctx is cancelled, e.g. when a server request
+// times out.
+func DirectoryRecursiveContext(ctx context.Context, dirPath string) ([]*Result, error) {
+	return directoryContext(ctx, dirPath, DirectoryOptions{Recursive: true})
 }
@@ -1,9 +1,15 @@
 package verify
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/grokify/brandkit/svg"
 )
 
 func TestSVGPureVector(t *testing.T) {
@@ -147,6 +153,25 @@ func TestSVGFileNotFound(t *testing.T) {
 	}
 }
 
+func TestDirectoryRecursiveContextCancelled(t *testing.T) {
+	dir := t.TempDir()
+	content := `<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/></svg>`
+	if err := os.WriteFile(filepath.Join(dir, "icon.svg"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DirectoryRecursiveContext(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+
+	results, err := DirectoryRecursiveContext(context.Background(), dir)
+	if err != nil || len(results) != 1 {
+		t.Errorf("DirectoryRecursiveContext = %d results, %v; want 1 result", len(results), err)
+	}
+}
+
 func TestDirectory(t *testing.T) {
 	dir := t.TempDir()
 
@@ -198,3 +223,202 @@ func TestIsSuccess(t *testing.T) {
 		}
 	}
 }
+
+func TestSVGForbidForeignObject(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "test.svg")
+
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <path d="M 10 10 L 90 10 L 90 90 Z"/>
+  <foreignObject width="100" height="100"><div xmlns="http://www.w3.org/1999/xhtml">Hi</div></foreignObject>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if !result.IsSuccess() {
+		t.Errorf("expected success without option, got errors: %v", result.Errors)
+	}
+
+	result, err = SVGWithOptions(file, Options{ForbidForeignObject: true})
+	if err != nil {
+		t.Fatalf("SVGWithOptions error: %v", err)
+	}
+	if result.IsSuccess() {
+		t.Error("expected failure for foreignObject with ForbidForeignObject")
+	}
+	if result.IsPureVector {
+		t.Error("expected IsPureVector = false")
+	}
+}
+
+func TestContentRequireNamespace(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100"><path d="M 10 10 L 90 10 L 90 90 Z"/></svg>`)
+
+	if result := Content(content, Options{}); !result.IsSuccess() {
+		t.Errorf("expected success without option, got errors: %v", result.Errors)
+	}
+
+	result := Content(content, Options{RequireNamespace: true})
+	if result.IsSuccess() {
+		t.Error("expected failure for missing namespace with RequireNamespace")
+	}
+
+	withNS := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100"><path d="M 10 10 L 90 10 L 90 90 Z"/></svg>`)
+	if result := Content(withNS, Options{RequireNamespace: true}); !result.IsSuccess() {
+		t.Errorf("expected success with namespace, got errors: %v", result.Errors)
+	}
+}
+
+func TestSVGIncludeSecurity(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "test.svg")
+
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <script>alert(1)</script>
+  <style>.a { fill: red; }</style>
+  <path class="a" d="M 10 10 L 90 10 L 90 90 Z"/>
+</svg>`
+
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if !result.IsSuccess() {
+		t.Errorf("expected success without option, got errors: %v", result.Errors)
+	}
+
+	result, err = SVGWithOptions(file, Options{IncludeSecurity: true})
+	if err != nil {
+		t.Fatalf("SVGWithOptions error: %v", err)
+	}
+	if result.IsSuccess() {
+		t.Error("expected failure for script with IncludeSecurity")
+	}
+	// Only the critical script threat is reported, not the low severity style block
+	if len(result.Errors) != 1 {
+		t.Errorf("got errors %v, want 1", result.Errors)
+	}
+}
+
+func TestSVGModernRasterFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		element string
+	}{
+		{"avif data URI", `<image href="data:image/avif;base64,AAAAIGZ0eXBhdmlm" width="10" height="10"/>`},
+		{"heic data URI", `<image href="data:image/heic;base64,AAAAGGZ0eXBoZWlj" width="10" height="10"/>`},
+		{"tiff data URI", `<image href="data:image/tiff;base64,SUkqAAgAAAA=" width="10" height="10"/>`},
+		{"ico data URI", `<image href="data:image/x-icon;base64,AAABAAEAEBA=" width="10" height="10"/>`},
+		{"avif file reference", `<image xlink:href="photo.avif" width="10" height="10"/>`},
+		{"ico file reference", `<image xlink:href="favicon.ico" width="10" height="10"/>`},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		file := filepath.Join(dir, "test.svg")
+		content := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">` +
+			tt.element + `</svg>`
+		if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := SVG(file)
+		if err != nil {
+			t.Fatalf("%s: SVG error: %v", tt.name, err)
+		}
+		if result.IsSuccess() {
+			t.Errorf("%s: expected verification failure", tt.name)
+		}
+		if !result.HasEmbeddedData {
+			t.Errorf("%s: expected HasEmbeddedData = true", tt.name)
+		}
+	}
+}
+
+func TestSVGFollowLocalRefs(t *testing.T) {
+	dir := t.TempDir()
+	icon := filepath.Join(dir, "icon.svg")
+
+	files := map[string]string{
+		"icon.svg": `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+  <path d="M 10 10 L 90 90"/>
+  <use href="shared.svg#g"/>
+</svg>`,
+		"shared.svg": `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
+  <use href="icon.svg#g"/>
+  <g id="g"><image href="data:image/png;base64,iVBORw0KGgo=" width="10" height="10"/></g>
+</svg>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := SVG(icon)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if !result.IsSuccess() {
+		t.Errorf("expected success without option, got errors: %v", result.Errors)
+	}
+
+	// shared.svg references icon.svg back; the cycle must not loop
+	result, err = SVGWithOptions(icon, Options{FollowLocalRefs: true})
+	if err != nil {
+		t.Fatalf("SVGWithOptions error: %v", err)
+	}
+	if result.IsSuccess() {
+		t.Fatal("expected failure for referenced file with embedded data")
+	}
+	if !result.HasEmbeddedData {
+		t.Error("expected HasEmbeddedData = true")
+	}
+	found := false
+	for _, e := range result.Errors {
+		if strings.Contains(e, "icon.svg -> shared.svg") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected reference chain in errors, got %v", result.Errors)
+	}
+}
+
+func TestReader(t *testing.T) {
+	content := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,iVBORw0KGgo="/></svg>`)
+
+	result, err := Reader(bytes.NewReader(content), Options{})
+	if err != nil {
+		t.Fatalf("Reader error: %v", err)
+	}
+	if result.IsPureVector {
+		t.Error("expected embedded image to be detected")
+	}
+}
+
+func TestSVGEmptyFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "empty.svg")
+	if err := os.WriteFile(file, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := SVG(file)
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	if result.IsSuccess() || len(result.Errors) != 1 || result.Errors[0] != svg.ErrEmptySVG.Error() {
+		t.Errorf("Errors = %q, want only %q", result.Errors, svg.ErrEmptySVG)
+	}
+}
@@ -0,0 +1,33 @@
+package verify
+
+import (
+	"github.com/grokify/brandkit/svg"
+)
+
+// ScanZip verifies every SVG entry of a zip archive in memory. Each
+// Result's FilePath is the entry's path within the archive. Entries that
+// cannot be read, including those over svg.MaxZipEntrySize, are reported
+// as failed Results. Options.FollowLocalRefs is ignored since entries are
+// not on disk.
+func ScanZip(archivePath string, opts Options) ([]*Result, error) {
+	entries, err := svg.ReadZipSVGs(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*Result
+	for _, entry := range entries {
+		if entry.Err != nil {
+			results = append(results, &Result{
+				FilePath: entry.Name,
+				IsValid:  false,
+				Errors:   []string{entry.Err.Error()},
+			})
+			continue
+		}
+		result := Content(entry.Content, opts)
+		result.FilePath = entry.Name
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct{ name, content string }{
+		{"clean.svg", `<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 10"/></svg>`},
+		{"raster.svg", `<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,AAAA"/></svg>`},
+	}
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "icons.zip")
+	if err := os.WriteFile(archive, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ScanZip(archive, Options{})
+	if err != nil {
+		t.Fatalf("ScanZip error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].FilePath != "clean.svg" || !results[0].IsSuccess() {
+		t.Errorf("clean entry: %+v", results[0])
+	}
+	if results[1].FilePath != "raster.svg" || results[1].IsSuccess() || !results[1].HasEmbeddedData {
+		t.Errorf("raster entry: %+v", results[1])
+	}
+}
@@ -0,0 +1,57 @@
+package svg
+
+import (
+	"bytes"
+	"regexp"
+)
+
+var (
+	xmlDeclRe          = regexp.MustCompile(`^\s*<\?xml\b([^?]*)\?>`)
+	xmlEncodingRe      = regexp.MustCompile(`\bencoding\s*=\s*["']([^"']*)["']`)
+	spaceBetweenTagsRe = regexp.MustCompile(`>\s+<`)
+	textRegionRe       = regexp.MustCompile(`(?s)<text\b.*?</text>`)
+)
+
+// CollapseWhitespace removes whitespace between tags and around the
+// content, leaving <text> elements untouched since whitespace there is
+// rendered.
+func CollapseWhitespace(content []byte) []byte {
+	textRegions := textRegionRe.FindAllIndex(content, -1)
+	var buf bytes.Buffer
+	buf.Grow(len(content))
+	last := 0
+	for _, loc := range spaceBetweenTagsRe.FindAllIndex(content, -1) {
+		if withinRegion(loc, textRegions) {
+			continue
+		}
+		buf.Write(content[last:loc[0]])
+		buf.WriteString("><")
+		last = loc[1]
+	}
+	buf.Write(content[last:])
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+// withinRegion reports whether loc lies strictly within one of the regions.
+func withinRegion(loc []int, regions [][]int) bool {
+	for _, r := range regions {
+		if r[0] < loc[0] && loc[1] < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// StripXMLDeclaration removes a leading <?xml?> declaration, which is
+// optional for UTF-8 documents. A declaration naming another encoding is
+// kept, since parsers need it to read the file.
+func StripXMLDeclaration(content []byte) []byte {
+	m := xmlDeclRe.FindSubmatchIndex(content)
+	if m == nil {
+		return content
+	}
+	if enc := xmlEncodingRe.FindSubmatch(content[m[2]:m[3]]); enc != nil && !bytes.EqualFold(enc[1], []byte("utf-8")) {
+		return content
+	}
+	return bytes.TrimLeft(content[m[1]:], " \t\r\n")
+}
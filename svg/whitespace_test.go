@@ -0,0 +1,30 @@
+package svg
+
+import (
+	"testing"
+)
+
+func TestCollapseWhitespace(t *testing.T) {
+	content := "\n<svg>\n  <g>\n    <path d=\"M0 0\"/>\n  </g>\n  <text>a  <tspan>b</tspan>  c</text>\n</svg>\n"
+	want := `<svg><g><path d="M0 0"/></g><text>a  <tspan>b</tspan>  c</text></svg>`
+	if got := string(CollapseWhitespace([]byte(content))); got != want {
+		t.Errorf("CollapseWhitespace() = %q, want %q", got, want)
+	}
+}
+
+func TestStripXMLDeclaration(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"<?xml version=\"1.0\"?>\n<svg/>", "<svg/>"},
+		{`<?xml version="1.0" encoding="UTF-8"?><svg/>`, "<svg/>"},
+		{`<?xml version="1.0" encoding="ISO-8859-1"?><svg/>`, `<?xml version="1.0" encoding="ISO-8859-1"?><svg/>`},
+		{"<svg/>", "<svg/>"},
+	}
+	for _, tt := range tests {
+		if got := string(StripXMLDeclaration([]byte(tt.content))); got != tt.want {
+			t.Errorf("StripXMLDeclaration(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package svg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/mogo/os/osutil"
+)
+
+// GzipNoCompression selects gzip.NoCompression for WriteOptions.GzipLevel,
+// whose zero value means gzip.DefaultCompression.
+const GzipNoCompression = gzip.HuffmanOnly - 1
+
+// WriteOptions configures WriteSVG.
+type WriteOptions struct {
+	GzipLevel int // gzip level for .svgz output (0 = gzip.DefaultCompression, GzipNoCompression = stored)
+}
+
+// gzipLevel returns the compress/gzip level for GzipLevel.
+func (o WriteOptions) gzipLevel() int {
+	switch o.GzipLevel {
+	case 0:
+		return gzip.DefaultCompression
+	case GzipNoCompression:
+		return gzip.NoCompression
+	default:
+		return o.GzipLevel
+	}
+}
+
+// DefaultWriteOptions returns options that compress .svgz output at
+// gzip.DefaultCompression.
+func DefaultWriteOptions() WriteOptions {
+	return WriteOptions{GzipLevel: gzip.DefaultCompression}
+}
+
+// WriteSVG writes SVG content to path. Paths ending in .svgz are written
+// gzip-compressed at opts.GzipLevel.
+func WriteSVG(path string, content []byte, opts WriteOptions) error {
+	data, err := EncodeForPath(path, content, opts)
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFileSecure(path, data, 0600)
+}
+
+// EncodeForPath returns the bytes WriteSVG would write to path: content
+// gzip-compressed for .svgz paths, otherwise content unchanged.
+func EncodeForPath(path string, content []byte, opts WriteOptions) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(path), ".svgz") {
+		return Compress(content, opts.gzipLevel())
+	}
+	return content, nil
+}
+
+// HashedPath returns the path in the same directory as path named by the
+// SHA-256 of data, e.g. "out/3a7b...e9.svg", for content-addressable
+// storage. The name ends in ".svgz" if path does, otherwise ".svg".
+func HashedPath(path string, data []byte) string {
+	ext := ".svg"
+	if strings.EqualFold(filepath.Ext(path), ".svgz") {
+		ext = ".svgz"
+	}
+	sum := sha256.Sum256(data)
+	return filepath.Join(filepath.Dir(path), hex.EncodeToString(sum[:])+ext)
+}
+
+// Compress gzip-compresses content for svgz output. The level is a
+// compress/gzip level: gzip.DefaultCompression, gzip.HuffmanOnly, or
+// gzip.NoCompression (0) through gzip.BestCompression (9).
+func Compress(content []byte, level int) ([]byte, error) {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return nil, fmt.Errorf("invalid gzip level %d: must be between %d and %d", level, gzip.HuffmanOnly, gzip.BestCompression)
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip level: %w", err)
+	}
+	if _, err := zw.Write(content); err != nil {
+		return nil, fmt.Errorf("failed to compress content: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress content: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package svg
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSVGGzipLevel(t *testing.T) {
+	dir := t.TempDir()
+
+	var sb strings.Builder
+	sb.WriteString(`<svg viewBox="0 0 1000 1000" xmlns="http://www.w3.org/2000/svg">`)
+	for i := 0; i < 2000; i++ {
+		sb.WriteString(`<path d="M 10.5 20.25 C 30 40 50 60 70 80 L 90 100 Z" fill="#1a73e8"/>`)
+		sb.WriteString(`<circle cx="` + strings.Repeat("1", i%7+1) + `" cy="50" r="5"/>`)
+	}
+	sb.WriteString(`</svg>`)
+	content := []byte(sb.String())
+
+	fast := filepath.Join(dir, "fast.svgz")
+	best := filepath.Join(dir, "best.svgz")
+	if err := WriteSVG(fast, content, WriteOptions{GzipLevel: gzip.BestSpeed}); err != nil {
+		t.Fatalf("WriteSVG error: %v", err)
+	}
+	if err := WriteSVG(best, content, WriteOptions{GzipLevel: gzip.BestCompression}); err != nil {
+		t.Fatalf("WriteSVG error: %v", err)
+	}
+
+	fastInfo, err := os.Stat(fast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bestInfo, err := os.Stat(best)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bestInfo.Size() >= fastInfo.Size() {
+		t.Errorf("BestCompression size %d not smaller than BestSpeed size %d", bestInfo.Size(), fastInfo.Size())
+	}
+
+	for _, path := range []string{fast, best} {
+		got, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error: %v", path, err)
+		}
+		if string(got) != string(content) {
+			t.Errorf("%s did not round-trip", filepath.Base(path))
+		}
+	}
+}
+
+func TestWriteSVGDefaultLevel(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte(strings.Repeat(`<path d="M 0 0 L 10 10"/>`, 100))
+
+	// The zero value compresses; GzipNoCompression stores
+	zero := filepath.Join(dir, "zero.svgz")
+	stored := filepath.Join(dir, "stored.svgz")
+	if err := WriteSVG(zero, content, WriteOptions{}); err != nil {
+		t.Fatalf("WriteSVG error: %v", err)
+	}
+	if err := WriteSVG(stored, content, WriteOptions{GzipLevel: GzipNoCompression}); err != nil {
+		t.Fatalf("WriteSVG error: %v", err)
+	}
+
+	zeroInfo, err := os.Stat(zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storedInfo, err := os.Stat(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zeroInfo.Size() >= int64(len(content)) || storedInfo.Size() <= int64(len(content)) {
+		t.Errorf("sizes: content %d, zero %d, stored %d; want zero < content < stored", len(content), zeroInfo.Size(), storedInfo.Size())
+	}
+	for _, path := range []string{zero, stored} {
+		if got, err := ReadFile(path); err != nil || string(got) != string(content) {
+			t.Errorf("%s did not round-trip: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestWriteSVGPlain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icon.svg")
+	content := []byte(`<svg viewBox="0 0 10 10"/>`)
+	if err := WriteSVG(path, content, WriteOptions{GzipLevel: gzip.BestCompression}); err != nil {
+		t.Fatalf("WriteSVG error: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("content = %q, want uncompressed %q", got, content)
+	}
+}
+
+func TestCompressInvalidLevel(t *testing.T) {
+	for _, level := range []int{42, 10, -3} {
+		if _, err := Compress([]byte("<svg/>"), level); err == nil {
+			t.Errorf("expected error for invalid gzip level %d", level)
+		}
+	}
+}
+
+func TestCompressNoCompression(t *testing.T) {
+	content := []byte(strings.Repeat(`<path d="M 0 0 L 10 10"/>`, 100))
+	stored, err := Compress(content, gzip.NoCompression)
+	if err != nil {
+		t.Fatalf("Compress error: %v", err)
+	}
+	compressed, err := Compress(content, DefaultWriteOptions().GzipLevel)
+	if err != nil {
+		t.Fatalf("Compress error: %v", err)
+	}
+	if len(stored) <= len(content) || len(compressed) >= len(stored) {
+		t.Errorf("sizes: content %d, stored %d, compressed %d; want stored > content > compressed", len(content), len(stored), len(compressed))
+	}
+	if got, err := Decode(stored); err != nil || string(got) != string(content) {
+		t.Errorf("stored output did not round-trip: %v", err)
+	}
+}
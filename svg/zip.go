@@ -0,0 +1,73 @@
+package svg
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Limits applied by ReadZipSVGs to guard against zip bombs.
+const (
+	MaxZipEntrySize = 10 << 20  // 10 MiB uncompressed per SVG entry
+	MaxZipTotalSize = 100 << 20 // 100 MiB uncompressed across all SVG entries
+)
+
+// ZipEntry is an SVG file read from a zip archive.
+type ZipEntry struct {
+	Name    string // Path within the archive
+	Content []byte
+	Err     error // Set if the entry could not be read; Content is nil
+}
+
+// ReadZipSVGs reads every SVG entry of a zip archive into memory, in
+// archive order, without extracting it. macOS resource fork entries
+// (__MACOSX/ and ._ files) are skipped. An entry larger than
+// MaxZipEntrySize is returned with an error; an archive whose SVG entries
+// together expand beyond MaxZipTotalSize fails as a whole.
+func ReadZipSVGs(archivePath string) ([]ZipEntry, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	var entries []ZipEntry
+	var total int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !IsSVGFile(f.Name) ||
+			strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), "._") {
+			continue
+		}
+		content, err := readZipEntry(f)
+		total += int64(len(content))
+		if total > MaxZipTotalSize {
+			return nil, fmt.Errorf("archive expands beyond %d bytes", MaxZipTotalSize)
+		}
+		entries = append(entries, ZipEntry{Name: f.Name, Content: content, Err: err})
+	}
+	return entries, nil
+}
+
+// readZipEntry reads a zip entry, enforcing MaxZipEntrySize on the actual
+// decompressed data rather than trusting the size in the header.
+func readZipEntry(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > MaxZipEntrySize {
+		return nil, fmt.Errorf("entry exceeds %d bytes", MaxZipEntrySize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open entry: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(rc, MaxZipEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entry: %w", err)
+	}
+	if len(content) > MaxZipEntrySize {
+		return nil, fmt.Errorf("entry exceeds %d bytes", MaxZipEntrySize)
+	}
+	return content, nil
+}
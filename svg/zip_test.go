@@ -0,0 +1,66 @@
+package svg
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a zip archive with the given entries in order.
+func writeZip(t *testing.T, path string, names []string, contents [][]byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(contents[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadZipSVGs(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "icons.zip")
+	icon := []byte(`<svg viewBox="0 0 10 10"><path d="M0 0L10 10"/></svg>`)
+	writeZip(t, archive,
+		[]string{"pack/a.svg", "pack/readme.txt", "__MACOSX/pack/._a.svg", "pack/B.SVG", "pack/bomb.svg"},
+		[][]byte{icon, []byte("hello"), []byte("resource fork"), icon, bytes.Repeat([]byte(" "), MaxZipEntrySize+1)})
+
+	entries, err := ReadZipSVGs(archive)
+	if err != nil {
+		t.Fatalf("ReadZipSVGs error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(entries), entries)
+	}
+	for i, name := range []string{"pack/a.svg", "pack/B.SVG"} {
+		if entries[i].Name != name || entries[i].Err != nil || !bytes.Equal(entries[i].Content, icon) {
+			t.Errorf("entry %d = %s (err %v), want %s", i, entries[i].Name, entries[i].Err, name)
+		}
+	}
+	if bomb := entries[2]; bomb.Err == nil || bomb.Content != nil || !strings.Contains(bomb.Err.Error(), "exceeds") {
+		t.Errorf("oversized entry: err = %v, want size error", bomb.Err)
+	}
+}
+
+func TestReadZipSVGsNotArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icon.svg")
+	if err := os.WriteFile(path, []byte(`<svg/>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadZipSVGs(path); err == nil {
+		t.Error("expected error for a file that is not a zip archive")
+	}
+}
@@ -0,0 +1,21 @@
+package brandkit
+
+import (
+	"github.com/grokify/brandkit/svg"
+)
+
+// EnsureTitle returns content with a <title> as the first child of the
+// root <svg> element, for accessibility. Content that already has a
+// non-empty root-level title, or has no <svg> element, is returned
+// unchanged; an empty root-level title is replaced. The title text is
+// XML-escaped.
+func EnsureTitle(content []byte, title string) []byte {
+	if _, ok := svg.RootTitle(content); ok {
+		return content
+	}
+	titled, err := svg.SetTitle(string(content), title)
+	if err != nil {
+		return content
+	}
+	return []byte(titled)
+}
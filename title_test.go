@@ -0,0 +1,57 @@
+package brandkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/brandkit/svg/verify"
+)
+
+func TestEnsureTitle(t *testing.T) {
+	content := []byte(`<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><path d="M 10 10 L 90 90"/></svg>`)
+
+	opts := verify.Options{RequireTitle: true}
+	if result := verify.Content(content, opts); result.IsSuccess() {
+		t.Fatal("expected title-less icon to fail with RequireTitle")
+	} else if !strings.Contains(strings.Join(result.Errors, ";"), "<title>") {
+		t.Errorf("Errors = %q, want a missing title error", result.Errors)
+	}
+
+	fixed := EnsureTitle(content, "AT&T <logo>")
+	want := `<svg viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg"><title>AT&amp;T &lt;logo&gt;</title><path d="M 10 10 L 90 90"/></svg>`
+	if string(fixed) != want {
+		t.Errorf("EnsureTitle =\n%s\nwant\n%s", fixed, want)
+	}
+	if result := verify.Content(fixed, opts); !result.IsSuccess() {
+		t.Errorf("EnsureTitle output fails verification: %q", result.Errors)
+	}
+
+	if again := EnsureTitle(fixed, "Other"); string(again) != string(fixed) {
+		t.Errorf("EnsureTitle changed content that already has a title:\n%s", again)
+	}
+}
+
+func TestEnsureTitleNestedAndSelfClosing(t *testing.T) {
+	// A title inside a group is not the icon's accessible name
+	nested := []byte(`<svg viewBox="0 0 10 10"><g><title>Part</title></g></svg>`)
+	if got := string(EnsureTitle(nested, "Logo")); got != `<svg viewBox="0 0 10 10"><title>Logo</title><g><title>Part</title></g></svg>` {
+		t.Errorf("EnsureTitle(nested) = %s", got)
+	}
+
+	if got := string(EnsureTitle([]byte(`<svg viewBox="0 0 10 10" />`), "Logo")); got != `<svg viewBox="0 0 10 10"><title>Logo</title></svg>` {
+		t.Errorf("EnsureTitle(self-closing) = %s", got)
+	}
+}
+
+func TestEnsureTitleReplacesEmptyTitle(t *testing.T) {
+	for _, content := range []string{
+		`<svg viewBox="0 0 10 10"><title></title><path d="M 0 0 L 10 10"/></svg>`,
+		`<svg viewBox="0 0 10 10"><title> </title><path d="M 0 0 L 10 10"/></svg>`,
+		`<svg viewBox="0 0 10 10"><title/><path d="M 0 0 L 10 10"/></svg>`,
+	} {
+		got := string(EnsureTitle([]byte(content), "Logo"))
+		if want := `<svg viewBox="0 0 10 10"><title>Logo</title><path d="M 0 0 L 10 10"/></svg>`; got != want {
+			t.Errorf("EnsureTitle(%s) =\n%s\nwant\n%s", content, got, want)
+		}
+	}
+}